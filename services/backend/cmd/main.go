@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,10 +27,38 @@ type VersionResponse struct {
 type HealthResponse struct {
 	Status    string    `json:"status"`
 	Service   string    `json:"service"`
+	Uptime    string    `json:"uptime"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func setupRoutes(app *fiber.App) {
+// Readiness tracks whether the service should receive new traffic. The
+// backend has no downstream dependencies yet, so it is ready from start
+// until graceful shutdown begins.
+type Readiness struct {
+	startedAt    time.Time
+	shuttingDown atomic.Bool
+}
+
+// NewReadiness returns a Readiness that reports ready.
+func NewReadiness() *Readiness {
+	return &Readiness{startedAt: time.Now()}
+}
+
+// MarkShuttingDown flips readiness off so load balancers stop routing.
+func (r *Readiness) MarkShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Status returns "healthy" while serving and "unhealthy" once draining.
+func (r *Readiness) Status() string {
+	if r.shuttingDown.Load() {
+		return "unhealthy"
+	}
+
+	return "healthy"
+}
+
+func setupRoutes(app *fiber.App, readiness *Readiness) {
 	// Version endpoint - returns application version
 	app.Get("/version", func(ctx *fiber.Ctx) error {
 		log.Info().
@@ -50,12 +79,39 @@ func setupRoutes(app *fiber.App) {
 			Str("method", "GET").
 			Msg("Health check endpoint accessed")
 
+		return readinessResponse(ctx, readiness)
+	})
+
+	// Liveness probe - the process is up and serving requests
+	app.Get("/livez", func(ctx *fiber.Ctx) error {
 		return ctx.JSON(HealthResponse{
-			Status:    "healthy",
+			Status:    "alive",
 			Service:   serviceName,
+			Uptime:    time.Since(readiness.startedAt).Round(time.Second).String(),
 			Timestamp: time.Now(),
 		})
 	})
+
+	// Readiness probe - 503 once graceful shutdown has started
+	app.Get("/readyz", func(ctx *fiber.Ctx) error {
+		return readinessResponse(ctx, readiness)
+	})
+}
+
+func readinessResponse(ctx *fiber.Ctx, readiness *Readiness) error {
+	status := readiness.Status()
+
+	code := fiber.StatusOK
+	if status != "healthy" {
+		code = fiber.StatusServiceUnavailable
+	}
+
+	return ctx.Status(code).JSON(HealthResponse{
+		Status:    status,
+		Service:   serviceName,
+		Uptime:    time.Since(readiness.startedAt).Round(time.Second).String(),
+		Timestamp: time.Now(),
+	})
 }
 
 func setupLogger() {
@@ -106,7 +162,7 @@ func createFiberApp(corsAllowedOrigins string) *fiber.App {
 	return app
 }
 
-func gracefulShutdown(app *fiber.App) {
+func gracefulShutdown(app *fiber.App, readiness *Readiness) {
 	// Create channel to receive OS signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -133,6 +189,7 @@ func gracefulShutdown(app *fiber.App) {
 	<-sigChan
 
 	log.Info().Msg("Graceful shutdown initiated")
+	readiness.MarkShuttingDown()
 
 	// Create context with timeout for graceful shutdown
 	const shutdownTimeout = 30 * time.Second
@@ -168,10 +225,11 @@ func main() {
 	app := createFiberApp(corsAllowedOrigins)
 
 	// Setup routes
-	setupRoutes(app)
+	readiness := NewReadiness()
+	setupRoutes(app, readiness)
 
 	// Start server with graceful shutdown
-	gracefulShutdown(app)
+	gracefulShutdown(app, readiness)
 
 	log.Info().Msg("Application terminated")
 }
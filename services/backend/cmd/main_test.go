@@ -26,3 +26,37 @@ func TestORPHAN_CreateFiberApp_ReturnsConfiguredApp(t *testing.T) {
 	// Verify strict routing is enabled (should return 404 for non-existent routes)
 	assert.Equal(t, 404, resp.StatusCode)
 }
+
+// Unit test for the readiness probe during graceful shutdown.
+func TestORPHAN_ReadyzEndpoint_ShuttingDown_ReturnsServiceUnavailable(t *testing.T) {
+	// Arrange
+	app := createFiberApp("http://localhost:3000")
+	readiness := NewReadiness()
+	setupRoutes(app, readiness)
+
+	// Act
+	readyReq := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+	readyResp, err := app.Test(readyReq)
+	require.NoError(t, err)
+
+	defer readyResp.Body.Close()
+
+	readiness.MarkShuttingDown()
+
+	drainingReq := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+	drainingResp, err := app.Test(drainingReq)
+	require.NoError(t, err)
+
+	defer drainingResp.Body.Close()
+
+	livezReq := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/livez", nil)
+	livezResp, err := app.Test(livezReq)
+	require.NoError(t, err)
+
+	defer livezResp.Body.Close()
+
+	// Assert
+	assert.Equal(t, http.StatusOK, readyResp.StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, drainingResp.StatusCode)
+	assert.Equal(t, http.StatusOK, livezResp.StatusCode)
+}
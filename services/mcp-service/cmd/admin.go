@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/subtle"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminSession is one session as reported by the admin API
+type AdminSession struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastActive   time.Time `json:"lastActive"`
+	MessageCount int64     `json:"messageCount"`
+	SSEConnected bool      `json:"sseConnected"`
+	QueueDepth   int       `json:"queueDepth"`
+}
+
+// setupAdminRoutes serves the admin API under /admin for callers
+// presenting ADMIN_API_TOKEN as bearer token. Without the token the
+// routes are not registered at all.
+func setupAdminRoutes(app *fiber.App) {
+	token := os.Getenv("ADMIN_API_TOKEN")
+	if token == "" {
+		log.Info().Msg("Admin API disabled - ADMIN_API_TOKEN not set")
+		return
+	}
+
+	admin := app.Group("/admin", adminAuth(token))
+	admin.Get("/sessions", adminListSessionsHandler)
+	admin.Get("/sessions/:id", adminGetSessionHandler)
+	admin.Delete("/sessions/:id", adminDeleteSessionHandler)
+	admin.Post("/drain", adminDrainHandler)
+	admin.Delete("/drain", adminResumeHandler)
+
+	log.Info().Msg("Admin API enabled")
+}
+
+// adminAuth rejects requests without the admin bearer token
+func adminAuth(token string) fiber.Handler {
+	expected := []byte("Bearer " + token)
+
+	return func(c *fiber.Ctx) error {
+		if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(map[string]interface{}{
+				"error": "unauthorized",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// adminListSessionsHandler lists every session, oldest first
+func adminListSessionsHandler(c *fiber.Ctx) error {
+	sessions := []AdminSession{}
+	pool.sessions.Range(func(_, value interface{}) bool {
+		sessions = append(sessions, adminSession(value.(*SessionInfo)))
+		return true
+	})
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return c.JSON(map[string]interface{}{
+		"draining": draining.Load(),
+		"sessions": sessions,
+	})
+}
+
+// adminGetSessionHandler reports one session
+func adminGetSessionHandler(c *fiber.Ctx) error {
+	value, ok := pool.sessions.Load(c.Params("id"))
+	if !ok {
+		return adminSessionNotFound(c)
+	}
+
+	return c.JSON(adminSession(value.(*SessionInfo)))
+}
+
+// adminDeleteSessionHandler force-closes a session: it is dropped from
+// the pool and its SSE stream, if any, is ended
+func adminDeleteSessionHandler(c *fiber.Ctx) error {
+	sessionID := c.Params("id")
+
+	value, ok := pool.sessions.Load(sessionID)
+	if !ok {
+		return adminSessionNotFound(c)
+	}
+
+	session := value.(*SessionInfo)
+	removeSession(sessionID, session)
+	session.Close()
+
+	log.Info().
+		Str("session_id", sessionID).
+		Msg("Session closed by admin")
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// adminDrainHandler stops accepting new sessions ahead of a deploy;
+// existing sessions are served until they end or the process stops
+func adminDrainHandler(c *fiber.Ctx) error {
+	draining.Store(true)
+
+	log.Warn().
+		Int64("active_sessions", pool.activeCount.Load()).
+		Msg("Draining - new sessions are refused")
+
+	return c.JSON(map[string]interface{}{
+		"draining":       true,
+		"activeSessions": pool.activeCount.Load(),
+	})
+}
+
+// adminResumeHandler cancels a drain, for a deploy that was called off
+func adminResumeHandler(c *fiber.Ctx) error {
+	draining.Store(false)
+
+	log.Info().Msg("Drain cancelled - accepting new sessions")
+
+	return c.JSON(map[string]interface{}{
+		"draining":       false,
+		"activeSessions": pool.activeCount.Load(),
+	})
+}
+
+func adminSessionNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(map[string]interface{}{
+		"error": "session not found",
+	})
+}
+
+// adminSession snapshots session for the admin API
+func adminSession(session *SessionInfo) AdminSession {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	return AdminSession{
+		ID:           session.ID,
+		UserID:       session.UserID,
+		CreatedAt:    session.CreatedAt,
+		LastActive:   session.LastActive,
+		MessageCount: session.MessageCount.Load(),
+		SSEConnected: session.SSEConnected.Load(),
+		QueueDepth:   len(session.SSEChannel),
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "admin-secret"
+
+// newAdminApp serves the admin API guarded by testAdminToken
+func newAdminApp(t *testing.T) *fiber.App {
+	t.Helper()
+	t.Setenv("ADMIN_API_TOKEN", testAdminToken)
+
+	app := fiber.New()
+	setupAdminRoutes(app)
+
+	return app
+}
+
+// adminRequest calls the admin API with the given Authorization header
+func adminRequest(t *testing.T, app *fiber.App, method, path, authorization string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), method, path, nil)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestORPHAN_AdminAuth_MissingToken_Returns401(t *testing.T) {
+	// Arrange
+	app := newAdminApp(t)
+
+	// Act
+	resp := adminRequest(t, app, http.MethodGet, "/admin/sessions", "")
+
+	// Assert
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func TestORPHAN_AdminAuth_WrongToken_Returns401(t *testing.T) {
+	// Arrange
+	app := newAdminApp(t)
+
+	// Act
+	wrong := adminRequest(t, app, http.MethodGet, "/admin/sessions", "Bearer not-the-token")
+	prefix := adminRequest(t, app, http.MethodGet, "/admin/sessions", "Bearer "+testAdminToken[:5])
+	scheme := adminRequest(t, app, http.MethodGet, "/admin/sessions", "Basic "+testAdminToken)
+
+	// Assert
+	assert.Equal(t, fiber.StatusUnauthorized, wrong.StatusCode)
+	assert.Equal(t, fiber.StatusUnauthorized, prefix.StatusCode)
+	assert.Equal(t, fiber.StatusUnauthorized, scheme.StatusCode)
+}
+
+func TestORPHAN_AdminAuth_ValidToken_ListsSessions(t *testing.T) {
+	// Arrange
+	const sessionID = "admin-test-listed"
+	session, ok := getOrCreateSession(sessionID)
+	require.True(t, ok)
+	t.Cleanup(func() { removeSession(sessionID, session) })
+	app := newAdminApp(t)
+
+	// Act
+	resp := adminRequest(t, app, http.MethodGet, "/admin/sessions", "Bearer "+testAdminToken)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Draining bool           `json:"draining"`
+		Sessions []AdminSession `json:"sessions"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	ids := make([]string, len(body.Sessions))
+	for i, s := range body.Sessions {
+		ids[i] = s.ID
+	}
+	assert.Contains(t, ids, sessionID)
+}
+
+func TestORPHAN_AdminSessions_UnknownSession_Returns404(t *testing.T) {
+	// Arrange
+	app := newAdminApp(t)
+
+	// Act
+	get := adminRequest(t, app, http.MethodGet, "/admin/sessions/admin-test-unknown", "Bearer "+testAdminToken)
+	del := adminRequest(t, app, http.MethodDelete, "/admin/sessions/admin-test-unknown", "Bearer "+testAdminToken)
+
+	// Assert
+	assert.Equal(t, fiber.StatusNotFound, get.StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, del.StatusCode)
+}
+
+func TestORPHAN_AdminRoutes_WithoutConfiguredToken_AreNotServed(t *testing.T) {
+	// Arrange
+	t.Setenv("ADMIN_API_TOKEN", "")
+	app := fiber.New()
+	setupAdminRoutes(app)
+
+	// Act
+	resp := adminRequest(t, app, http.MethodGet, "/admin/sessions", "Bearer ")
+
+	// Assert
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
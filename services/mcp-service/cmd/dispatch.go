@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/schema"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+func handleMCPMethod(ctx context.Context, msg MCPMessage, sessionID string) MCPMessage {
+	switch msg.Method {
+	case "initialize":
+		protocolVersion := recordClientCapabilities(sessionID, msg.Params)
+
+		// Return initialize response with session ID
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Result: map[string]interface{}{
+				"protocolVersion": protocolVersion,
+				"capabilities": map[string]interface{}{
+					"tools": map[string]interface{}{},
+				},
+				"serverInfo": map[string]string{
+					"name":    "mcp-service",
+					"version": "1.0.0",
+				},
+				"sessionId": sessionID,
+			},
+		}
+
+	case "ping":
+		// Return pong response
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Result:  map[string]interface{}{},
+		}
+
+	case "tools/list":
+		// Return list of available tools
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Result: map[string]interface{}{
+				"tools": toolCatalog,
+			},
+		}
+
+	case "tools/call":
+		// Parse tool call parameters
+		var params ToolCallParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			if err := limiter.Allow(ctx, ratelimit.SubjectFrom(ctx)); err != nil {
+				return rateLimitedResponse(msg.ID, err)
+			}
+
+			return MCPMessage{
+				JSONRPC: "2.0",
+				ID:      msg.ID,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: fmt.Sprintf("Invalid params - failed to parse tool call parameters: %v", err),
+				},
+			}
+		}
+
+		// Route to tool handler
+		return handleToolCall(ctx, params, msg.ID, sessionID)
+
+	default:
+		// Method not found
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Error: &MCPError{
+				Code:    -32601,
+				Message: fmt.Sprintf("Method not found - unknown method: %s", msg.Method),
+			},
+		}
+	}
+}
+
+// ToolCallParams represents the parameters for a tools/call request
+type ToolCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// toolCall is a tools/call whose arguments passed the tool's inputSchema,
+// with the document they name already resolved from an ID or URL. Lock
+// is the document's edit lock for edits, nil for reads.
+type toolCall struct {
+	RequestID interface{}
+	SessionID string
+	Arguments json.RawMessage
+	Document  docs.Reference
+	Lock      *documentLock
+}
+
+// scope returns the section a tool is limited to: section when given,
+// otherwise the heading the documentId URL points at
+func (c toolCall) scope(section string) string {
+	if section != "" {
+		return section
+	}
+	return c.Document.HeadingID
+}
+
+// editingTools are the tools that write to a document and so run under
+// its edit lock
+var editingTools = map[string]bool{
+	"replaceAll": true, "replace_all": true, "append": true, "prepend": true,
+	"insertBefore": true, "insertAfter": true, "batch_edit": true,
+	"replace_section": true, "format_text": true, "revert_last_edit": true,
+}
+
+// handleToolCall dispatches the tool and records its outcome and latency
+func handleToolCall(ctx context.Context, params ToolCallParams, requestID interface{}, sessionID string) MCPMessage {
+	ctx, span := tracing.Tracer().Start(ctx, "tools/call "+toolLabel(params.Name),
+		trace.WithAttributes(attribute.String("mcp.tool.name", params.Name)))
+	defer span.End()
+
+	start := time.Now()
+	response := dispatchToolCall(ctx, params, requestID, sessionID)
+	outcome := toolOutcome(response)
+	telemetry.ObserveToolCall(toolLabel(params.Name), outcome, time.Since(start))
+
+	span.SetAttributes(attribute.String("mcp.tool.outcome", outcome))
+	if outcome != "success" {
+		span.SetStatus(codes.Error, outcome)
+	}
+
+	return response
+}
+
+// toolHandlers runs each tool once dispatchToolCall has validated and
+// resolved its call
+var toolHandlers = map[string]func(ctx context.Context, call toolCall) MCPMessage{
+	"replaceAll":        tool(handleReplaceAll),
+	"replace_all":       tool(handleReplaceAll),
+	"append":            tool(handleAppend),
+	"prepend":           tool(handlePrepend),
+	"insertBefore":      tool(handleInsertBefore),
+	"insertAfter":       tool(handleInsertAfter),
+	"batch_edit":        tool(handleBatchEdit),
+	"replace_section":   tool(handleReplaceSection),
+	"format_text":       tool(handleFormatText),
+	"revert_last_edit":  tool(handleRevertLastEdit),
+	"list_edit_history": tool(handleListEditHistory),
+	"search_document":   tool(handleSearchDocument),
+	"get_outline":       tool(handleGetOutline),
+}
+
+// tool decodes the schema-checked arguments of a call into A, so each
+// handler starts from its typed arguments
+func tool[A any](handle func(ctx context.Context, call toolCall, args A) MCPMessage) func(ctx context.Context, call toolCall) MCPMessage {
+	return func(ctx context.Context, call toolCall) MCPMessage {
+		var args A
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return MCPMessage{
+				JSONRPC: "2.0",
+				ID:      call.RequestID,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: fmt.Sprintf("Invalid params - failed to parse tool arguments: %v", err),
+				},
+			}
+		}
+
+		return handle(ctx, call, args)
+	}
+}
+
+// dispatchToolCall resolves the document the arguments name, charges
+// the call's rate limit budgets, validates the arguments and routes tool
+// execution to the appropriate handler
+func dispatchToolCall(ctx context.Context, params ToolCallParams, requestID interface{}, sessionID string) MCPMessage {
+	// Resolve documentId, which may also be a Docs or Drive URL, once for
+	// the budget, the lock and the tool. URLs are reduced to their ID so
+	// that both forms share one budget.
+	var target struct {
+		DocumentID string `json:"documentId"`
+	}
+	_ = json.Unmarshal(params.Arguments, &target)
+	ref, refErr := documentRefs.Parse(target.DocumentID)
+
+	// Charge the user, session and, for edits, document budgets at once.
+	// Reads do not count against the document budget, which guards the
+	// Docs write quota.
+	subject := ratelimit.SubjectFrom(ctx)
+	if editingTools[params.Name] && refErr == nil {
+		subject.DocumentID = ref.DocumentID
+	}
+
+	limitCtx, limitSpan := tracing.StartStage(ctx, "rate_limit",
+		attribute.String("mcp.document_id", subject.DocumentID))
+	err := limiter.Allow(limitCtx, subject)
+	tracing.EndStage(limitSpan, err)
+	if err != nil {
+		return rateLimitedResponse(requestID, err)
+	}
+
+	handler, ok := toolHandlers[params.Name]
+	if !ok {
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      requestID,
+			Error: &MCPError{
+				Code:    -32601,
+				Message: fmt.Sprintf("Method not found - unknown tool: %s", params.Name),
+			},
+		}
+	}
+
+	// Check the arguments against the tool's inputSchema, so handlers can
+	// rely on required fields being present and well typed
+	if inputSchema, ok := toolInputSchemas[params.Name]; ok {
+		violations, err := schema.Validate(inputSchema, params.Arguments)
+		if errors.Is(err, schema.ErrInvalidSchema) {
+			return MCPMessage{
+				JSONRPC: "2.0",
+				ID:      requestID,
+				Error: &MCPError{
+					Code:    -32603,
+					Message: fmt.Sprintf("Internal error - invalid tool schema: %v", err),
+				},
+			}
+		}
+		if err != nil {
+			return MCPMessage{
+				JSONRPC: "2.0",
+				ID:      requestID,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: fmt.Sprintf("Invalid params - failed to parse tool arguments: %v", err),
+				},
+			}
+		}
+		if len(violations) > 0 {
+			return invalidArgumentsResponse(requestID, violations)
+		}
+	}
+
+	if refErr != nil {
+		return documentIDErrorResponse(requestID, target.DocumentID, refErr)
+	}
+
+	// Edits read the document, compute indexes and then write, so two
+	// edits to one document must not overlap
+	call := toolCall{RequestID: requestID, SessionID: sessionID, Arguments: params.Arguments, Document: ref}
+	if editingTools[params.Name] {
+		release, err := lockDocument(ctx, ref.DocumentID)
+		if err != nil {
+			return documentBusyResponse(requestID, ref.DocumentID, err)
+		}
+		call.Lock = &documentLock{documentID: ref.DocumentID, release: release}
+		defer call.Lock.Release()
+	}
+
+	return handler(ctx, call)
+}
+
+// invalidArgumentsResponse reports tool arguments that do not fit the
+// tool's inputSchema. Every violation is listed in data with its JSON
+// pointer and a hint, so a client can fix them all in one retry.
+func invalidArgumentsResponse(requestID interface{}, violations []schema.Violation) MCPMessage {
+	messages := make([]string, len(violations))
+	for i, v := range violations {
+		messages[i] = v.Message
+	}
+
+	return MCPMessage{
+		JSONRPC: "2.0",
+		ID:      requestID,
+		Error: &MCPError{
+			Code:    -32602,
+			Message: "Invalid params - " + strings.Join(messages, "; "),
+			Data: map[string]interface{}{
+				"errors": violations,
+			},
+		},
+	}
+}
+
+// documentIDErrorResponse reports a documentId that is neither a
+// document ID nor a Docs or Drive URL, in the shape of a schema violation
+func documentIDErrorResponse(requestID interface{}, documentID string, err error) MCPMessage {
+	return invalidArgumentsResponse(requestID, []schema.Violation{{
+		Pointer: "/documentId",
+		Kind:    schema.KindFormat,
+		Message: fmt.Sprintf("documentId validation failed: %v", err),
+		Hint:    fmt.Sprintf("Got %q; documentId should be a Google Docs document ID or a docs.google.com/document/d/DOCUMENT_ID/edit or drive.google.com URL", documentID),
+	}})
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/editlock"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// lockDocument waits for the document's edit lock and records the wait
+func lockDocument(ctx context.Context, documentID string) (func(), error) {
+	lockCtx, span := tracing.StartStage(ctx, "edit_lock",
+		attribute.String("mcp.document_id", documentID))
+	start := time.Now()
+
+	release, err := editLocks.Lock(lockCtx, documentID)
+	tracing.EndStage(span, err)
+
+	outcome := "acquired"
+	switch {
+	case errors.Is(err, editlock.ErrTimeout):
+		outcome = "timeout"
+	case errors.Is(err, editlock.ErrQueueFull):
+		outcome = "queue_full"
+	case err != nil:
+		outcome = "error"
+	}
+	telemetry.ObserveEditLockWait(outcome, time.Since(start))
+
+	return release, err
+}
+
+// documentLock is the edit lock an edit holds on its document
+type documentLock struct {
+	documentID string
+	release    func()
+}
+
+// Release gives the lock up; a lock handed back by Unlocked is not held
+func (l *documentLock) Release() {
+	l.release()
+	l.release = func() {}
+}
+
+// Unlocked runs fn with the lock released, so that other edits of the
+// document are not held up while fn waits on the user, and takes the
+// lock back when fn succeeds. Without a lock fn simply runs.
+func (l *documentLock) Unlocked(ctx context.Context, fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+
+	l.Release()
+	if err := fn(); err != nil {
+		return err
+	}
+
+	release, err := lockDocument(ctx, l.documentID)
+	if err != nil {
+		return err
+	}
+	l.release = release
+
+	return nil
+}
+
+// documentBusyResponse reports an edit that could not get the document's
+// edit lock. Waiting too long is a tool error the caller can retry; a
+// failing lock backend is an internal error.
+func documentBusyResponse(requestID interface{}, documentID string, err error) MCPMessage {
+	if !errors.Is(err, editlock.ErrTimeout) && !errors.Is(err, editlock.ErrQueueFull) {
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      requestID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: fmt.Sprintf("Internal error - edit lock failed: %v", err),
+			},
+		}
+	}
+
+	log.Warn().Err(err).Str("document_id", documentID).Msg("Edit lock not acquired")
+
+	return toolErrorResponse(requestID, ToolErrorResult{
+		Type:    "error",
+		Code:    "DOCUMENT_BUSY",
+		Message: fmt.Sprintf("Other edits to document %s are still running (%v); retry shortly", documentID, err),
+	})
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/history"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// EditOptions are the arguments shared by every edit tool
+type EditOptions struct {
+	DryRun             bool   `json:"dry_run,omitempty"`
+	RequiredRevisionID string `json:"requiredRevisionId,omitempty"`
+	Segment            string `json:"segment,omitempty"`
+}
+
+// options converts the shared arguments for the edit pipeline, editing
+// the tab the document reference points into
+func (o EditOptions) options(ref docs.Reference) operations.Options {
+	return operations.Options{
+		DryRun: o.DryRun, RequiredRevisionID: o.RequiredRevisionID, Segment: o.Segment, TabID: ref.TabID,
+	}
+}
+
+// ReplaceAllArgs represents the arguments for the replaceAll tool
+type ReplaceAllArgs struct {
+	DocumentID string `json:"documentId"`
+	Content    string `json:"content"`
+	Section    string `json:"section,omitempty"`
+	EditOptions
+}
+
+// AppendArgs represents the arguments for the append tool
+type AppendArgs struct {
+	DocumentID string                `json:"documentId"`
+	Content    string                `json:"content"`
+	AnchorText string                `json:"anchorText,omitempty"`
+	Occurrence operations.Occurrence `json:"occurrence"`
+	Section    string                `json:"section,omitempty"`
+	AnchorMatching
+	EditOptions
+}
+
+// PrependArgs represents the arguments for the prepend tool
+type PrependArgs struct {
+	DocumentID string `json:"documentId"`
+	Content    string `json:"content"`
+	Section    string `json:"section,omitempty"`
+	EditOptions
+}
+
+// InsertBeforeArgs represents the arguments for the insertBefore tool
+type InsertBeforeArgs struct {
+	DocumentID string                `json:"documentId"`
+	Content    string                `json:"content"`
+	AnchorText string                `json:"anchorText"`
+	Occurrence operations.Occurrence `json:"occurrence"`
+	Section    string                `json:"section,omitempty"`
+	AnchorMatching
+	EditOptions
+}
+
+// InsertAfterArgs represents the arguments for the insertAfter tool
+type InsertAfterArgs struct {
+	DocumentID string                `json:"documentId"`
+	Content    string                `json:"content"`
+	AnchorText string                `json:"anchorText"`
+	Occurrence operations.Occurrence `json:"occurrence"`
+	Section    string                `json:"section,omitempty"`
+	AnchorMatching
+	EditOptions
+}
+
+// AnchorMatching holds the fuzzy matching options accepted by the
+// anchor-based edit tools
+type AnchorMatching struct {
+	Fuzzy         bool    `json:"fuzzy,omitempty"`
+	MinSimilarity float64 `json:"minSimilarity,omitempty"`
+}
+
+// ReplaceSectionArgs represents the arguments for the replace_section tool
+type ReplaceSectionArgs struct {
+	DocumentID string `json:"documentId"`
+	Section    string `json:"section"`
+	Content    string `json:"content"`
+	EditOptions
+}
+
+// BatchEditArgs represents the arguments for the batch_edit tool
+type BatchEditArgs struct {
+	DocumentID string               `json:"documentId"`
+	Operations []BatchEditOperation `json:"operations"`
+	EditOptions
+}
+
+// BatchEditOperation is one entry of a batch_edit operations list
+type BatchEditOperation struct {
+	Mode          string                `json:"mode"`
+	Content       string                `json:"content"`
+	AnchorText    string                `json:"anchorText,omitempty"`
+	CaseSensitive bool                  `json:"caseSensitive,omitempty"`
+	Occurrence    operations.Occurrence `json:"occurrence"`
+	Section       string                `json:"section,omitempty"`
+	AnchorMatching
+}
+
+// handleReplaceAll handles the replaceAll tool execution
+func handleReplaceAll(ctx context.Context, call toolCall, args ReplaceAllArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Int("content_length", len(args.Content)).
+		Msg("Executing replaceAll tool")
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "replaceAll", args.DocumentID,
+		[]operations.Operation{{Mode: operations.ModeReplaceAll, Content: args.Content, Section: call.scope(args.Section)}},
+		args.options(call.Document), fmt.Sprintf("success: replaced content in document %s", args.DocumentID))
+}
+
+// handleAppend handles the append tool execution
+func handleAppend(ctx context.Context, call toolCall, args AppendArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("anchor_text", args.AnchorText).
+		Int("content_length", len(args.Content)).
+		Msg("Executing append tool")
+
+	successMsg := fmt.Sprintf("success: appended content to document %s", args.DocumentID)
+	if args.AnchorText != "" {
+		successMsg = fmt.Sprintf("success: appended content after '%s' in document %s", args.AnchorText, args.DocumentID)
+	}
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "append", args.DocumentID,
+		[]operations.Operation{{
+			Mode: operations.ModeAppend, Content: args.Content, AnchorText: args.AnchorText,
+			Occurrence: args.Occurrence, Section: call.scope(args.Section),
+			Fuzzy: args.Fuzzy, MinSimilarity: args.MinSimilarity,
+		}},
+		withElicitation(args.options(call.Document), call, "append", args.DocumentID), successMsg)
+}
+
+// handlePrepend handles the prepend tool execution
+func handlePrepend(ctx context.Context, call toolCall, args PrependArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Int("content_length", len(args.Content)).
+		Msg("Executing prepend tool")
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "prepend", args.DocumentID,
+		[]operations.Operation{{Mode: operations.ModePrepend, Content: args.Content, Section: call.scope(args.Section)}},
+		args.options(call.Document), fmt.Sprintf("success: prepended content to document %s", args.DocumentID))
+}
+
+// handleInsertBefore handles the insertBefore tool execution
+func handleInsertBefore(ctx context.Context, call toolCall, args InsertBeforeArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("anchor_text", args.AnchorText).
+		Int("content_length", len(args.Content)).
+		Msg("Executing insertBefore tool")
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "insertBefore", args.DocumentID,
+		[]operations.Operation{{
+			Mode: operations.ModeInsertBefore, Content: args.Content, AnchorText: args.AnchorText,
+			Occurrence: args.Occurrence, Section: call.scope(args.Section),
+			Fuzzy: args.Fuzzy, MinSimilarity: args.MinSimilarity,
+		}},
+		withElicitation(args.options(call.Document), call, "insertBefore", args.DocumentID), fmt.Sprintf("success: inserted content before '%s' in document %s", args.AnchorText, args.DocumentID))
+}
+
+// handleInsertAfter handles the insertAfter tool execution
+func handleInsertAfter(ctx context.Context, call toolCall, args InsertAfterArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("anchor_text", args.AnchorText).
+		Int("content_length", len(args.Content)).
+		Msg("Executing insertAfter tool")
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "insertAfter", args.DocumentID,
+		[]operations.Operation{{
+			Mode: operations.ModeInsertAfter, Content: args.Content, AnchorText: args.AnchorText,
+			Occurrence: args.Occurrence, Section: call.scope(args.Section),
+			Fuzzy: args.Fuzzy, MinSimilarity: args.MinSimilarity,
+		}},
+		withElicitation(args.options(call.Document), call, "insertAfter", args.DocumentID), fmt.Sprintf("success: inserted content after '%s' in document %s", args.AnchorText, args.DocumentID))
+}
+
+// handleBatchEdit handles the batch_edit tool execution
+func handleBatchEdit(ctx context.Context, call toolCall, args BatchEditArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	ops := make([]operations.Operation, 0, len(args.Operations))
+	for i, raw := range args.Operations {
+		mode, err := operations.ParseMode(raw.Mode)
+		if err == nil {
+			op := operations.Operation{
+				Mode:          mode,
+				Content:       raw.Content,
+				AnchorText:    raw.AnchorText,
+				CaseSensitive: raw.CaseSensitive,
+				Occurrence:    raw.Occurrence,
+				Section:       call.scope(raw.Section),
+				Fuzzy:         raw.Fuzzy,
+				MinSimilarity: raw.MinSimilarity,
+			}
+			err = op.Validate()
+			ops = append(ops, op)
+		}
+
+		if err != nil {
+			return MCPMessage{
+				JSONRPC: "2.0",
+				ID:      call.RequestID,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: fmt.Sprintf("Invalid params - operations[%d]: %v", i, err),
+					Data: map[string]interface{}{
+						"operation": i,
+						"modes":     operations.Modes(),
+					},
+				},
+			}
+		}
+	}
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Int("operations", len(ops)).
+		Msg("Executing batch_edit tool")
+
+	opts := withElicitation(args.options(call.Document), call, "batch_edit", args.DocumentID)
+	opts.Atomic = true
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "batch_edit", args.DocumentID, ops, opts,
+		fmt.Sprintf("success: applied %d operations to document %s", len(ops), args.DocumentID))
+}
+
+// handleReplaceSection handles the replace_section tool execution
+func handleReplaceSection(ctx context.Context, call toolCall, args ReplaceSectionArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("section", args.Section).
+		Int("content_length", len(args.Content)).
+		Msg("Executing replace_section tool")
+
+	return runEdit(ctx, call.RequestID, call.SessionID, "replace_section", args.DocumentID,
+		[]operations.Operation{{Mode: operations.ModeReplaceAll, Content: args.Content, Section: args.Section}},
+		args.options(call.Document), fmt.Sprintf("success: replaced section '%s' in document %s", args.Section, args.DocumentID))
+}
+
+// runEdit applies ops through the edit pipeline, or only previews them
+// for a dry run, and wraps the outcome as a tool result:
+// successText plus the structured result on success, a structured error
+// with isError set when the edit could not be applied. Applied edits are
+// recorded in the session's edit history under tool.
+func runEdit(ctx context.Context, requestID interface{}, sessionID, tool, documentID string, ops []operations.Operation, opts operations.Options, successText string) MCPMessage {
+	result, err := editor.Apply(ctx, documentID, ops, opts)
+
+	modes := make([]string, len(ops))
+	for i, op := range ops {
+		modes[i] = string(op.Mode)
+	}
+
+	return editResponse(ctx, requestID, sessionID, tool, documentID, modes, result, err, successText)
+}
+
+// editResponse turns the outcome of an edit into the tool response and
+// records edits that changed the document in the session's edit history,
+// including partially applied ones so they can still be reverted
+func editResponse(ctx context.Context, requestID interface{}, sessionID, tool, documentID string, modes []string, result *operations.Result, err error, successText string) MCPMessage {
+	if err != nil {
+		log.Warn().
+			Ctx(ctx).
+			Err(err).
+			Str("document_id", documentID).
+			Msg("Edit failed")
+
+		var partial *operations.PartialEditError
+		if errors.As(err, &partial) {
+			editHistory.Record(sessionID, history.Entry{
+				DocumentID:     documentID,
+				Tool:           tool,
+				Modes:          modes,
+				RevisionBefore: partial.Snapshot.RevisionID,
+				RevisionAfter:  partial.RevisionID,
+				Segment:        partial.Segment,
+				Snapshot:       partial.Snapshot,
+			})
+		}
+
+		return editErrorResponse(requestID, len(modes) > 1, err)
+	}
+
+	if result.DryRun {
+		successText = fmt.Sprintf("dry run: %d requests would be sent to document %s; nothing was applied",
+			len(result.Requests), documentID)
+	} else {
+		editHistory.Record(sessionID, history.Entry{
+			DocumentID:     documentID,
+			Tool:           tool,
+			Modes:          modes,
+			RevisionBefore: result.Snapshot.RevisionID,
+			RevisionAfter:  result.RevisionID,
+			Segment:        result.Segment,
+			Snapshot:       result.Snapshot,
+		})
+	}
+
+	return toolResult(requestID, successText, result)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/elicitation"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// elicitations pairs the elicitation/create requests sent to clients
+// with the answers they post back
+var elicitations = elicitation.NewBroker(elicitation.DefaultTimeout)
+
+// protocolVersions are the MCP revisions the server speaks, latest first.
+// Elicitation was introduced in elicitationProtocolVersion.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const elicitationProtocolVersion = "2025-06-18"
+
+// errEditCancelled signals an edit the user declined when asked which
+// match to act on
+var errEditCancelled = errors.New("edit cancelled")
+
+// handleClientResponse hands a client's JSON-RPC response to the server
+// request waiting for it
+func handleClientResponse(ctx context.Context, msg MCPMessage, sessionID string) {
+	var result json.RawMessage
+	rpcErr := ""
+	if msg.Error != nil {
+		rpcErr = fmt.Sprintf("%d %s", msg.Error.Code, msg.Error.Message)
+	} else {
+		result, _ = json.Marshal(msg.Result)
+	}
+
+	id := fmt.Sprint(msg.ID)
+	if !elicitations.Resolve(sessionID, id, result, rpcErr) {
+		log.Warn().
+			Ctx(ctx).
+			Str("session_id", sessionID).
+			Str("id", id).
+			Msg("Ignored response to unknown server request")
+		return
+	}
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", sessionID).
+		Str("id", id).
+		Msg("Received client response")
+}
+
+// recordClientCapabilities remembers the optional features the client
+// declared in initialize and returns the protocol version negotiated for
+// the session. Elicitation only counts under a version that defines it.
+func recordClientCapabilities(sessionID string, params json.RawMessage) string {
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Capabilities    struct {
+			Elicitation json.RawMessage `json:"elicitation"`
+		} `json:"capabilities"`
+	}
+	_ = json.Unmarshal(params, &init)
+
+	version := negotiateProtocolVersion(init.ProtocolVersion)
+	if value, ok := pool.sessions.Load(sessionID); ok {
+		value.(*SessionInfo).Elicitation.Store(
+			present(init.Capabilities.Elicitation) && version >= elicitationProtocolVersion)
+	}
+
+	return version
+}
+
+// negotiateProtocolVersion answers with the version the client asked for
+// when the server speaks it, and with the latest one it speaks otherwise
+func negotiateProtocolVersion(requested string) string {
+	if slices.Contains(protocolVersions, requested) {
+		return requested
+	}
+	return protocolVersions[0]
+}
+
+// present reports whether a JSON value was given and is not null
+func present(value json.RawMessage) bool {
+	return len(value) > 0 && string(value) != "null"
+}
+
+// withElicitation lets an anchored edit ask the user which match to act
+// on when its anchor matches several places and the arguments name no
+// occurrence. It applies to sessions whose client declared the
+// elicitation capability under a protocol version that has it and has an
+// SSE stream open to receive the question; dry runs never ask. The edit
+// lock is released while the user decides, and the edit is then only
+// written if the document is still at the revision it was planned on.
+func withElicitation(opts operations.Options, call toolCall, tool, documentID string) operations.Options {
+	if opts.DryRun || !canElicit(call.SessionID) {
+		return opts
+	}
+
+	explicit := explicitOccurrences(call.Arguments)
+	opts.Choose = func(ctx context.Context, op int, anchor string, matches []operations.MatchPreview) (operations.Occurrence, error) {
+		if explicit(op) {
+			return operations.OccurrenceAll, nil
+		}
+
+		var occurrence operations.Occurrence
+		err := call.Lock.Unlocked(ctx, func() (err error) {
+			occurrence, err = askOccurrence(ctx, call.SessionID, tool, documentID, anchor, matches)
+			return err
+		})
+		return occurrence, err
+	}
+
+	return opts
+}
+
+// canElicit reports whether the server can ask the session's user
+func canElicit(sessionID string) bool {
+	value, ok := pool.sessions.Load(sessionID)
+	if !ok {
+		return false
+	}
+	session := value.(*SessionInfo)
+	return session.Elicitation.Load() && session.SSEConnected.Load()
+}
+
+// explicitOccurrences reports which operations of the arguments set an
+// occurrence: the tool's own for single edits, each entry's for batch_edit
+func explicitOccurrences(argsRaw json.RawMessage) func(op int) bool {
+	var args struct {
+		Occurrence json.RawMessage `json:"occurrence"`
+		Operations []struct {
+			Occurrence json.RawMessage `json:"occurrence"`
+		} `json:"operations"`
+	}
+	_ = json.Unmarshal(argsRaw, &args)
+
+	return func(op int) bool {
+		if op < len(args.Operations) {
+			return present(args.Operations[op].Occurrence)
+		}
+		return present(args.Occurrence)
+	}
+}
+
+// askOccurrence sends the matches of an ambiguous anchor to the user as
+// an elicitation/create request and returns the occurrence they chose.
+// Any answer other than an accepted choice, including a client that
+// fails to handle the request, cancels the edit: only clients without
+// the capability get the default of every match.
+func askOccurrence(ctx context.Context, sessionID, tool, documentID, anchor string, matches []operations.MatchPreview) (operations.Occurrence, error) {
+	ctx, span := tracing.StartStage(ctx, "elicitation", attribute.Int("mcp.matches", len(matches)))
+	response, err := elicitations.Ask(ctx, sessionID, occurrenceRequest(tool, documentID, anchor, matches),
+		func(id string, request elicitation.Request) error {
+			params, err := json.Marshal(request)
+			if err != nil {
+				return err
+			}
+			return sendSSEMessage(sessionID, MCPMessage{JSONRPC: "2.0", ID: id, Method: elicitation.Method, Params: params})
+		})
+	tracing.EndStage(span, err)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, elicitation.ErrTimeout), errors.Is(err, elicitation.ErrSessionClosed), ctx.Err() != nil:
+		return operations.Occurrence{}, err
+	default:
+		log.Warn().
+			Ctx(ctx).
+			Err(err).
+			Str("session_id", sessionID).
+			Msg("Elicitation failed, cancelling the edit")
+		return operations.Occurrence{}, fmt.Errorf("%w: the client could not ask the user: %v", errEditCancelled, err)
+	}
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", sessionID).
+		Str("action", response.Action).
+		Interface("content", response.Content).
+		Msg("Elicitation answered")
+
+	if response.Action != elicitation.ActionAccept {
+		return operations.Occurrence{}, fmt.Errorf("%w: the user chose to %s", errEditCancelled, response.Action)
+	}
+
+	choice, _ := response.Content["occurrence"].(string)
+	occurrence, err := operations.ParseOccurrence(choice)
+	if err != nil || choice == "" {
+		return operations.Occurrence{}, fmt.Errorf("%w: no valid match was chosen (%q)", errEditCancelled, choice)
+	}
+
+	return occurrence, nil
+}
+
+// occurrenceRequest asks which match of anchor an edit should act on,
+// offering each match with the text around it, or every match
+func occurrenceRequest(tool, documentID, anchor string, matches []operations.MatchPreview) elicitation.Request {
+	options := make([]string, 0, len(matches)+1)
+	labels := make([]string, 0, len(matches)+1)
+	for _, m := range matches {
+		options = append(options, strconv.Itoa(m.Occurrence))
+		labels = append(labels, fmt.Sprintf("%d: %s[%s]%s", m.Occurrence, m.ContextBefore, m.Text, m.ContextAfter))
+	}
+	options = append(options, "all")
+	labels = append(labels, "Every match")
+
+	return elicitation.Request{
+		Message: fmt.Sprintf("%q matches %d places in document %s. Which one should %s change?",
+			anchor, len(matches), documentID, tool),
+		RequestedSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"occurrence": map[string]interface{}{
+					"type":        "string",
+					"title":       "Match",
+					"description": "The match to change, numbered in document order",
+					"enum":        options,
+					"enumNames":   labels,
+				},
+			},
+			"required": []string{"occurrence"},
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestORPHAN_NegotiateProtocolVersion_AnswersSupportedOrLatest(t *testing.T) {
+	// Act
+	legacy := negotiateProtocolVersion("2024-11-05")
+	unknown := negotiateProtocolVersion("999.999.999")
+	missing := negotiateProtocolVersion("")
+
+	// Assert
+	assert.Equal(t, "2024-11-05", legacy)
+	assert.Equal(t, protocolVersions[0], unknown)
+	assert.Equal(t, protocolVersions[0], missing)
+}
+
+func TestORPHAN_ExplicitOccurrences_ReadsToolAndBatchOccurrences(t *testing.T) {
+	// Arrange
+	single := json.RawMessage(`{"anchorText":"foo","occurrence":"first"}`)
+	batch := json.RawMessage(`{"operations":[{"anchorText":"a"},{"anchorText":"b","occurrence":2}]}`)
+
+	// Act
+	singleExplicit := explicitOccurrences(single)
+	batchExplicit := explicitOccurrences(batch)
+
+	// Assert
+	assert.True(t, singleExplicit(0))
+	assert.False(t, batchExplicit(0))
+	assert.True(t, batchExplicit(1))
+}
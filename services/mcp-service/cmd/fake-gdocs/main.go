@@ -0,0 +1,97 @@
+// Command fake-gdocs serves an offline fake of the Google Docs, Drive
+// and OAuth token APIs for integration and e2e test stacks. It must
+// never be pointed at by a production deployment.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/fakegdocs"
+)
+
+const (
+	defaultPort     = "8090"
+	shutdownTimeout = 5 * time.Second
+)
+
+func main() {
+	// Configure logging
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	// Unknown document IDs become empty documents unless
+	// FAKE_GDOCS_AUTO_CREATE=false, so suites can use fresh IDs per test
+	store := docs.NewMemoryStore(os.Getenv("FAKE_GDOCS_AUTO_CREATE") != "false")
+
+	// Seed fixtures from FAKE_GDOCS_FIXTURES, a directory of .md and .json files
+	var fixtures []fakegdocs.Fixture
+
+	if dir := os.Getenv("FAKE_GDOCS_FIXTURES"); dir != "" {
+		loaded, err := fakegdocs.LoadFixtures(dir)
+		if err != nil {
+			log.Fatal().Err(err).Str("dir", dir).Msg("Failed to load fixtures")
+		}
+
+		fixtures = loaded
+	}
+
+	server, err := fakegdocs.NewServer(store, fixtures)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to seed fixtures")
+	}
+
+	httpServer := &http.Server{
+		Addr:              ":" + port,
+		Handler:           logRequests(server.Handler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Str("port", port).Int("fixtures", len(fixtures)).Msg("Fake Google Docs API listening")
+
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Fake Google Docs API failed")
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Fake Google Docs API shutdown failed")
+	}
+}
+
+// logRequests logs every call, which is most of what there is to debug
+// when a test disagrees with the fake.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Debug().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Dur("duration", time.Since(start)).
+			Msg("Request served")
+	})
+}
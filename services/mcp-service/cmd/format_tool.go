@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// FormatTextArgs represents the arguments for the format_text tool
+type FormatTextArgs struct {
+	DocumentID    string                `json:"documentId"`
+	AnchorText    string                `json:"anchorText,omitempty"`
+	CaseSensitive bool                  `json:"caseSensitive,omitempty"`
+	Occurrence    operations.Occurrence `json:"occurrence"`
+	Section       string                `json:"section,omitempty"`
+	AnchorMatching
+	operations.Format
+	EditOptions
+}
+
+// handleFormatText handles the format_text tool execution
+func handleFormatText(ctx context.Context, call toolCall, args FormatTextArgs) MCPMessage {
+	args.Section = call.scope(args.Section)
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("anchor_text", args.AnchorText).
+		Str("section", args.Section).
+		Msg("Executing format_text tool")
+
+	selection := operations.Selection{
+		AnchorText: args.AnchorText, CaseSensitive: args.CaseSensitive, Section: args.Section,
+		Occurrence: args.Occurrence, Fuzzy: args.Fuzzy, MinSimilarity: args.MinSimilarity,
+	}
+	result, err := editor.Format(ctx, args.DocumentID, selection, args.Format,
+		withElicitation(args.options(call.Document), call, "format_text", args.DocumentID))
+
+	target := fmt.Sprintf("'%s'", args.AnchorText)
+	if args.AnchorText == "" {
+		target = fmt.Sprintf("section '%s'", args.Section)
+	}
+
+	return editResponse(ctx, call.RequestID, call.SessionID, "format_text", args.DocumentID, []string{"format"}, result, err,
+		fmt.Sprintf("success: formatted %s in document %s", target, args.DocumentID))
+}
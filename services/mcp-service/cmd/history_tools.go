@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/history"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// RevertLastEditArgs represents the arguments for the revert_last_edit tool
+type RevertLastEditArgs struct {
+	DocumentID string `json:"documentId"`
+	Force      bool   `json:"force,omitempty"`
+	DryRun     bool   `json:"dry_run,omitempty"`
+}
+
+// ListEditHistoryArgs represents the arguments for the list_edit_history tool
+type ListEditHistoryArgs struct {
+	DocumentID string `json:"documentId"`
+}
+
+// RevertResult is the structuredContent of a successful revert_last_edit
+type RevertResult struct {
+	*operations.Result
+	RevertedEdit history.Entry `json:"revertedEdit"`
+}
+
+// EditHistoryResult is the structuredContent of list_edit_history
+type EditHistoryResult struct {
+	Type       string          `json:"type"`
+	DocumentID string          `json:"docId"`
+	Edits      []history.Entry `json:"edits"`
+}
+
+// handleRevertLastEdit restores the document as it was before the
+// session's latest recorded edit and drops that edit from the history
+func handleRevertLastEdit(ctx context.Context, call toolCall, args RevertLastEditArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	entry, ok := editHistory.Last(call.SessionID, args.DocumentID)
+	if !ok {
+		return toolErrorResponse(call.RequestID, ToolErrorResult{
+			Type:    "error",
+			Code:    "NO_EDIT_HISTORY",
+			Message: "This session has no recorded edit of the document to revert",
+		})
+	}
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Str("edit_id", entry.ID).
+		Bool("force", args.Force).
+		Msg("Executing revert_last_edit tool")
+
+	// Without the revision the edit left, an unchanged document cannot be told apart
+	if entry.RevisionAfter == "" && !args.Force {
+		return toolErrorResponse(call.RequestID, ToolErrorResult{
+			Type:    "error",
+			Code:    "REVISION_UNKNOWN",
+			Message: "The revision the edit left the document at was not recorded, so later changes cannot be ruled out; pass force: true to revert anyway",
+			Hints: []operations.Hint{
+				{Action: "force", Label: "Revert anyway, discarding any later changes"},
+				{Action: "ask_user", Label: "Ask the user"},
+			},
+		})
+	}
+
+	// Unless forced, the document must still be exactly as the edit left it
+	opts := operations.Options{DryRun: args.DryRun, Segment: entry.Segment, TabID: call.Document.TabID}
+	if !args.Force {
+		opts.RequiredRevisionID = entry.RevisionAfter
+	}
+
+	result, err := editor.Restore(ctx, args.DocumentID, entry.Snapshot, opts)
+
+	var mismatch *operations.RevisionMismatchError
+	switch {
+	case errors.As(err, &mismatch):
+		return toolErrorResponse(call.RequestID, ToolErrorResult{
+			Type:    "error",
+			Code:    "DOCUMENT_CHANGED",
+			Message: "The document changed after the edit; reverting now would discard those changes",
+			Hints: []operations.Hint{
+				{Action: "force", Label: "Revert anyway, discarding the later changes"},
+				{Action: "ask_user", Label: "Ask the user"},
+			},
+			RequiredRevisionID: mismatch.Required,
+			CurrentRevisionID:  mismatch.Current,
+		})
+	case err != nil:
+		log.Warn().
+			Ctx(ctx).
+			Err(err).
+			Str("document_id", args.DocumentID).
+			Msg("Revert failed")
+
+		return editErrorResponse(call.RequestID, false, err)
+	}
+
+	text := fmt.Sprintf("success: reverted the last %s edit of document %s", entry.Tool, args.DocumentID)
+	if result.DryRun {
+		text = fmt.Sprintf("dry run: %d requests would be sent to document %s; nothing was applied",
+			len(result.Requests), args.DocumentID)
+	} else {
+		editHistory.Reverted(call.SessionID, args.DocumentID, entry.ID, result.RevisionID)
+	}
+
+	return toolResult(call.RequestID, text, RevertResult{Result: result, RevertedEdit: entry})
+}
+
+// handleListEditHistory lists the session's revertible edits of a document
+func handleListEditHistory(ctx context.Context, call toolCall, args ListEditHistoryArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	edits := editHistory.List(call.SessionID, args.DocumentID)
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Int("edits", len(edits)).
+		Msg("Executing list_edit_history tool")
+
+	return toolResult(call.RequestID,
+		fmt.Sprintf("success: %d revertible edits of document %s", len(edits), args.DocumentID),
+		EditHistoryResult{Type: "ok", DocumentID: args.DocumentID, Edits: edits})
+}
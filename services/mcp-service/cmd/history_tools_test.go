@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/history"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+func TestORPHAN_RevertLastEdit_UnknownRevisionAfter_RequiresForce(t *testing.T) {
+	// Arrange
+	const sessionID, documentID = "revert-test-session", "revert-test-doc"
+	store := docs.NewMemoryStore(true)
+	snapshot, err := store.Get(context.Background(), documentID)
+	require.NoError(t, err)
+	previous := editor
+	editor = operations.NewEditor(store)
+	t.Cleanup(func() { editor = previous })
+	editHistory.Record(sessionID, history.Entry{
+		DocumentID: documentID, Tool: "append", RevisionBefore: snapshot.RevisionID, Snapshot: snapshot,
+	})
+	call := toolCall{RequestID: 1, SessionID: sessionID, Document: docs.Reference{DocumentID: documentID}}
+
+	// Act
+	refused := handleRevertLastEdit(context.Background(), call, RevertLastEditArgs{})
+	forced := handleRevertLastEdit(context.Background(), call, RevertLastEditArgs{Force: true})
+
+	// Assert
+	refusedResult := refused.Result.(map[string]interface{})
+	assert.Equal(t, true, refusedResult["isError"])
+	assert.Equal(t, "REVISION_UNKNOWN", refusedResult["structuredContent"].(ToolErrorResult).Code)
+	assert.Equal(t, false, forced.Result.(map[string]interface{})["isError"])
+}
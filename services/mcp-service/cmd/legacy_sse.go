@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// legacySSEKeepAlive is how often an idle legacy SSE stream gets a
+// comment line, so a vanished client is noticed and its session dropped.
+const legacySSEKeepAlive = 15 * time.Second
+
+// legacySSEHandler handles GET /sse, the stream of the 2024-11-05 HTTP+SSE
+// transport. Every stream starts its own session: the first event names
+// the endpoint the client posts its messages to, and each response then
+// arrives on the stream as a message event. The session ends with the
+// stream.
+func legacySSEHandler(c *fiber.Ctx) error {
+	sessionID := uuid.New().String()
+	session, ok := getOrCreateSession(sessionID)
+	if !ok {
+		return drainingResponse(c)
+	}
+	touchSession(session, userIDFromRequest(c))
+
+	log.Info().
+		Str("session_id", sessionID).
+		Msg("Legacy SSE stream requested")
+
+	// Set SSE headers
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		pool.sseStreams.Add(1)
+		session.SSEConnected.Store(true)
+		defer func() {
+			session.SSEConnected.Store(false)
+			pool.sseStreams.Add(-1)
+			removeSession(sessionID, session)
+
+			log.Info().
+				Str("session_id", sessionID).
+				Msg("Legacy SSE stream closed")
+		}()
+
+		// Tell the client where to post its messages
+		fmt.Fprintf(w, "event: endpoint\ndata: /messages?sessionId=%s\n\n", sessionID)
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(legacySSEKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case msg := <-session.SSEChannel:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+			case <-session.done:
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+
+			if err := w.Flush(); err != nil {
+				log.Warn().
+					Err(err).
+					Str("session_id", sessionID).
+					Msg("SSE write error, closing stream")
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// legacyMessagesHandler handles POST /messages?sessionId= of the legacy
+// transport. Messages run through the same pipeline as POST /mcp, but the
+// response is delivered on the session's stream and the POST itself is
+// only acknowledged with 202 Accepted.
+func legacyMessagesHandler(c *fiber.Ctx) error {
+	sessionID := c.Query("sessionId")
+	if sessionID == "" {
+		return c.Status(400).SendString("Missing sessionId parameter")
+	}
+
+	value, ok := pool.sessions.Load(sessionID)
+	if !ok {
+		return c.Status(404).SendString("Session not found")
+	}
+	session := value.(*SessionInfo)
+	touchSession(session, userIDFromRequest(c))
+
+	mcpMsg, parseErr := parseMCPBody(c, sessionID)
+	if parseErr != "" {
+		return c.Status(400).SendString(parseErr)
+	}
+
+	if response := processMCPMessage(c, mcpMsg, sessionID); response != nil {
+		if err := sendSSEMessage(sessionID, *response); err != nil {
+			log.Warn().
+				Err(err).
+				Str("session_id", sessionID).
+				Msg("Failed to queue legacy SSE response")
+			return c.Status(503).SendString("Session stream is not accepting messages")
+		}
+	}
+
+	return c.Status(202).SendString("Accepted")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLegacyApp serves only the legacy transport routes
+func newLegacyApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/sse", legacySSEHandler)
+	app.Post("/messages", legacyMessagesHandler)
+
+	return app
+}
+
+// closeStreamingSessions ends every session whose SSE stream is open, once
+// one is, so a streaming request under test returns
+func closeStreamingSessions(t *testing.T) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		closed := false
+		pool.sessions.Range(func(_, value interface{}) bool {
+			if session := value.(*SessionInfo); session.SSEConnected.Load() {
+				session.Close()
+				closed = true
+			}
+			return true
+		})
+		if closed {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestORPHAN_LegacySSE_Stream_AnnouncesMessagesEndpoint(t *testing.T) {
+	// Arrange
+	app := newLegacyApp()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/sse", nil)
+	go closeStreamingSessions(t)
+
+	// Act
+	resp, err := app.Test(req, 10_000)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+	assert.True(t, strings.HasPrefix(string(body), "event: endpoint\ndata: /messages?sessionId="), string(body))
+}
+
+func TestORPHAN_LegacyMessages_KnownSession_Returns202AndQueuesResponse(t *testing.T) {
+	// Arrange
+	const sessionID = "legacy-test-known"
+	session, ok := getOrCreateSession(sessionID)
+	require.True(t, ok)
+	t.Cleanup(func() { removeSession(sessionID, session) })
+
+	app := newLegacyApp()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/messages?sessionId="+sessionID,
+		strings.NewReader(`{"jsonrpc":"2.0","method":"ping","id":7}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	// Act
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Assert
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+	require.Len(t, session.SSEChannel, 1)
+	var response MCPMessage
+	require.NoError(t, json.Unmarshal(<-session.SSEChannel, &response))
+	assert.InDelta(t, 7, response.ID, 0)
+	assert.Nil(t, response.Error)
+}
+
+func TestORPHAN_LegacyMessages_UnknownSession_Returns404(t *testing.T) {
+	// Arrange
+	app := newLegacyApp()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/messages?sessionId=legacy-test-unknown",
+		strings.NewReader(`{"jsonrpc":"2.0","method":"ping","id":1}`))
+
+	// Act
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Assert
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestORPHAN_LegacyMessages_MissingSessionID_Returns400(t *testing.T) {
+	// Arrange
+	app := newLegacyApp()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/messages",
+		strings.NewReader(`{"jsonrpc":"2.0","method":"ping","id":1}`))
+
+	// Act
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Assert
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
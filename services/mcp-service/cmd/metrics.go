@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/metrics"
+)
+
+// metricsRegistry backs /metrics; telemetry records into it
+var (
+	metricsRegistry = prometheus.NewRegistry()
+	telemetry       = metrics.New(metricsRegistry)
+)
+
+// setupMetrics registers runtime collectors and the session pool gauges
+func setupMetrics() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	telemetry.RegisterPoolGauges(
+		func() float64 { return float64(pool.activeCount.Load()) },
+		func() float64 { return float64(pool.sseStreams.Load()) },
+		func() float64 { return float64(sseQueueDepth()) },
+	)
+	telemetry.RegisterEditLockGauge(func() float64 { return float64(editLocks.Depth()) })
+}
+
+// sseQueueDepth sums the messages waiting in every session's SSE channel
+func sseQueueDepth() int {
+	depth := 0
+	pool.sessions.Range(func(_, value interface{}) bool {
+		depth += len(value.(*SessionInfo).SSEChannel)
+		return true
+	})
+	return depth
+}
+
+// knownMethods and knownTools bound the cardinality of metric labels
+var (
+	knownMethods = map[string]bool{"initialize": true, "ping": true, "tools/list": true, "tools/call": true}
+	knownTools   = map[string]bool{
+		"replaceAll": true, "replace_all": true, "append": true,
+		"prepend": true, "insertBefore": true, "insertAfter": true,
+		"batch_edit": true, "replace_section": true, "format_text": true, "revert_last_edit": true, "list_edit_history": true,
+		"search_document": true, "get_outline": true,
+	}
+)
+
+// methodLabel returns method for supported methods and "unknown" otherwise
+func methodLabel(method string) string {
+	if knownMethods[method] {
+		return method
+	}
+	return "unknown"
+}
+
+// toolLabel returns name for registered tools and "unknown" otherwise
+func toolLabel(name string) string {
+	if knownTools[name] {
+		return name
+	}
+	return "unknown"
+}
+
+// toolOutcome classifies a tools/call response for metrics
+func toolOutcome(response MCPMessage) string {
+	if response.Error != nil {
+		return "rpc_error"
+	}
+	if result, ok := response.Result.(map[string]interface{}); ok && result["isError"] == true {
+		return "tool_error"
+	}
+	return "success"
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestORPHAN_MetricLabels_UnknownNames_CollapseToUnknown(t *testing.T) {
+	// Act
+	method := methodLabel("tools/call")
+	unknownMethod := methodLabel("resources/list")
+	tool := toolLabel("format_text")
+	unknownTool := toolLabel("drop_database")
+
+	// Assert
+	assert.Equal(t, "tools/call", method)
+	assert.Equal(t, "unknown", unknownMethod)
+	assert.Equal(t, "format_text", tool)
+	assert.Equal(t, "unknown", unknownTool)
+}
+
+func TestORPHAN_ToolOutcome_ClassifiesResponses(t *testing.T) {
+	// Arrange
+	rpcError := MCPMessage{Error: &MCPError{Code: -32602, Message: "Invalid params"}}
+	toolError := MCPMessage{Result: map[string]interface{}{"isError": true}}
+	success := MCPMessage{Result: map[string]interface{}{"content": []interface{}{}}}
+
+	// Act & Assert
+	assert.Equal(t, "rpc_error", toolOutcome(rpcError))
+	assert.Equal(t, "tool_error", toolOutcome(toolError))
+	assert.Equal(t, "success", toolOutcome(success))
+}
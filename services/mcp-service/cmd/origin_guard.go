@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/origin"
+)
+
+// originGuard rejects requests whose Origin header is not on the
+// allowlist with 403, so a page reaching the server through DNS
+// rebinding cannot use it. Requests without Origin come from clients
+// other than browsers and pass.
+func originGuard(allowed *origin.Allowlist) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestOrigin := c.Get(fiber.HeaderOrigin)
+		if requestOrigin == "" || allowed.Allowed(requestOrigin) {
+			return c.Next()
+		}
+
+		log.Warn().
+			Str("origin", requestOrigin).
+			Str("path", c.Path()).
+			Msg("Rejected request from disallowed origin")
+
+		return c.Status(fiber.StatusForbidden).JSON(MCPMessage{
+			JSONRPC: "2.0",
+			Error: &MCPError{
+				Code:    -32600,
+				Message: fmt.Sprintf("Forbidden - origin not allowed: %s", requestOrigin),
+			},
+		})
+	}
+}
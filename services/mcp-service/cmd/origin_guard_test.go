@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/origin"
+)
+
+// newGuardedApp answers POST /mcp with 200 behind the default local
+// allowlist
+func newGuardedApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	allowed, err := origin.Parse(origin.DefaultLocal)
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Post("/mcp", originGuard(allowed), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	return app
+}
+
+// postWithOrigin posts to /mcp, with an Origin header unless it is empty
+func postWithOrigin(t *testing.T, app *fiber.App, requestOrigin string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/mcp", nil)
+	if requestOrigin != "" {
+		req.Header.Set(fiber.HeaderOrigin, requestOrigin)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestORPHAN_OriginGuard_RebindingOrigin_Returns403(t *testing.T) {
+	// Arrange
+	app := newGuardedApp(t)
+
+	// Act
+	foreign := postWithOrigin(t, app, "http://attacker.example")
+	lookalike := postWithOrigin(t, app, "http://localhost.attacker.example:8081")
+
+	// Assert
+	assert.Equal(t, fiber.StatusForbidden, foreign.StatusCode)
+	assert.Equal(t, fiber.StatusForbidden, lookalike.StatusCode)
+
+	var body MCPMessage
+	require.NoError(t, json.NewDecoder(foreign.Body).Decode(&body))
+	require.NotNil(t, body.Error)
+	assert.Equal(t, -32600, body.Error.Code)
+	assert.Contains(t, body.Error.Message, "http://attacker.example")
+}
+
+func TestORPHAN_OriginGuard_AllowedOrMissingOrigin_Passes(t *testing.T) {
+	// Arrange
+	app := newGuardedApp(t)
+
+	// Act
+	local := postWithOrigin(t, app, "http://localhost:3000")
+	missing := postWithOrigin(t, app, "")
+
+	// Assert
+	assert.Equal(t, fiber.StatusOK, local.StatusCode)
+	assert.Equal(t, fiber.StatusOK, missing.StatusCode)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/health"
+)
+
+// healthCheckHandler reports connection counts and every dependency check
+func healthCheckHandler(c *fiber.Ctx) error {
+	report := healthRegistry.Run(c.Context())
+
+	body := map[string]interface{}{
+		"status": report.Status,
+		"connections": map[string]interface{}{
+			"active":     pool.activeCount.Load(),
+			"total":      pool.totalCount.Load(),
+			"sseStreams": pool.sseStreams.Load(),
+		},
+		"dependencies": report.Checks,
+		"timestamp":    time.Now().Format(time.RFC3339),
+	}
+
+	return c.Status(statusCodeFor(report.Status)).JSON(body)
+}
+
+// livezHandler answers the liveness probe. It deliberately checks no
+// dependencies: restarting the process will not fix a Redis outage.
+func livezHandler(c *fiber.Ctx) error {
+	return c.JSON(map[string]interface{}{
+		"status":    "alive",
+		"uptime":    time.Since(startedAt).Round(time.Second).String(),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// readyzHandler answers the readiness probe: 503 while shutting down,
+// draining or when a critical dependency is unhealthy, 200 when healthy
+// or degraded
+func readyzHandler(c *fiber.Ctx) error {
+	if shuttingDown.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(map[string]interface{}{
+			"status": health.StatusUnhealthy,
+			"reason": "shutting down",
+		})
+	}
+
+	if draining.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(map[string]interface{}{
+			"status": health.StatusUnhealthy,
+			"reason": "draining",
+		})
+	}
+
+	report := healthRegistry.Run(c.Context())
+	return c.Status(statusCodeFor(report.Status)).JSON(report)
+}
+
+// statusCodeFor maps a health status onto the probe HTTP status
+func statusCodeFor(status health.Status) int {
+	if status == health.StatusUnhealthy {
+		return fiber.StatusServiceUnavailable
+	}
+	return fiber.StatusOK
+}
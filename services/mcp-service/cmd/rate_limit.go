@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+)
+
+// errCodeRateLimited is the JSON-RPC error code returned when a rate
+// limit bucket is exhausted: the generic server error, with the scope and
+// retryAfter in error.data (mirrors HTTP 429).
+const errCodeRateLimited = -32000
+
+// RateLimitErrorData is the error.data payload of a rate limited request
+type RateLimitErrorData struct {
+	Scope        string  `json:"scope"`
+	Key          string  `json:"key"`
+	RetryAfter   int     `json:"retryAfter"`
+	RetryAfterMs int64   `json:"retryAfterMs"`
+	Rate         float64 `json:"rate"`
+	Burst        int     `json:"burst"`
+}
+
+// rateLimitedResponse converts a limiter error into a JSON-RPC error with
+// retry hints in error.data
+func rateLimitedResponse(requestID interface{}, err error) MCPMessage {
+	var exceeded *ratelimit.ExceededError
+	if !errors.As(err, &exceeded) {
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      requestID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: fmt.Sprintf("Internal error - rate limiter failed: %v", err),
+			},
+		}
+	}
+
+	return MCPMessage{
+		JSONRPC: "2.0",
+		ID:      requestID,
+		Error: &MCPError{
+			Code:    errCodeRateLimited,
+			Message: fmt.Sprintf("Rate limit exceeded - too many requests for %s, retry after %ds", exceeded.Scope, exceeded.RetryAfterSeconds()),
+			Data: RateLimitErrorData{
+				Scope:        string(exceeded.Scope),
+				Key:          exceeded.Key,
+				RetryAfter:   exceeded.RetryAfterSeconds(),
+				RetryAfterMs: exceeded.RetryAfter.Milliseconds(),
+				Rate:         exceeded.Rule.Rate,
+				Burst:        exceeded.Rule.Burst,
+			},
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// GetOutlineArgs represents the arguments for the get_outline tool
+type GetOutlineArgs struct {
+	DocumentID string `json:"documentId"`
+}
+
+// SearchDocumentArgs represents the arguments for the search_document tool
+type SearchDocumentArgs struct {
+	DocumentID    string `json:"documentId"`
+	Query         string `json:"query"`
+	Regex         bool   `json:"regex,omitempty"`
+	CaseSensitive bool   `json:"caseSensitive,omitempty"`
+	Section       string `json:"section,omitempty"`
+	Offset        int    `json:"offset,omitempty"`
+	Limit         int    `json:"limit,omitempty"`
+}
+
+// handleGetOutline handles the get_outline tool execution
+func handleGetOutline(ctx context.Context, call toolCall, args GetOutlineArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Msg("Executing get_outline tool")
+
+	result, err := editor.Outline(ctx, args.DocumentID)
+	if err != nil {
+		return editErrorResponse(call.RequestID, false, err)
+	}
+
+	return toolResult(call.RequestID,
+		fmt.Sprintf("success: %d headings in document %s", len(result.Headings), args.DocumentID),
+		result)
+}
+
+// handleSearchDocument handles the search_document tool execution
+func handleSearchDocument(ctx context.Context, call toolCall, args SearchDocumentArgs) MCPMessage {
+	args.DocumentID = call.Document.DocumentID
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", call.SessionID).
+		Str("document_id", args.DocumentID).
+		Bool("regex", args.Regex).
+		Msg("Executing search_document tool")
+
+	result, err := editor.Search(ctx, args.DocumentID, operations.SearchQuery{
+		Query:         args.Query,
+		Regex:         args.Regex,
+		CaseSensitive: args.CaseSensitive,
+		Section:       call.scope(args.Section),
+		Offset:        args.Offset,
+		Limit:         args.Limit,
+		TabID:         call.Document.TabID,
+	})
+	if err != nil {
+		return editErrorResponse(call.RequestID, false, err)
+	}
+
+	return toolResult(call.RequestID,
+		fmt.Sprintf("success: %d of %d matches in document %s", len(result.Matches), result.Total, args.DocumentID),
+		result)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SessionPool manages HTTP+SSE sessions
+type SessionPool struct {
+	sessions    sync.Map
+	activeCount atomic.Int64
+	totalCount  atomic.Int64
+	sseStreams  atomic.Int64
+}
+
+// SessionInfo stores session metadata. UserID is the key derived from
+// the caller's bearer token, empty for anonymous sessions. Elicitation
+// records whether the client declared the elicitation capability under a
+// protocol version that defines it. done is closed by Close to end the
+// session's SSE stream.
+type SessionInfo struct {
+	ID           string
+	UserID       string
+	CreatedAt    time.Time
+	LastActive   time.Time
+	MessageCount atomic.Int64
+	SSEChannel   chan []byte
+	SSEConnected atomic.Bool
+	Elicitation  atomic.Bool
+	mu           sync.Mutex
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// Close ends the session's SSE stream, if one is open. It is safe to
+// call more than once and from any goroutine.
+func (s *SessionInfo) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// pool holds every live session, whichever transport opened it
+var pool = &SessionPool{}
+
+// getOrCreateSession returns existing session or creates new one. While
+// the service drains it only returns existing sessions and reports false
+// instead of creating one.
+func getOrCreateSession(sessionID string) (*SessionInfo, bool) {
+	// Try to load existing session
+	if existing, ok := pool.sessions.Load(sessionID); ok {
+		return existing.(*SessionInfo), true
+	}
+
+	if draining.Load() {
+		return nil, false
+	}
+
+	// Create new session
+	session := &SessionInfo{
+		ID:         sessionID,
+		CreatedAt:  time.Now(),
+		LastActive: time.Now(),
+		SSEChannel: make(chan []byte, 100),
+		done:       make(chan struct{}),
+	}
+
+	// Store session (use LoadOrStore to handle race condition)
+	actual, loaded := pool.sessions.LoadOrStore(sessionID, session)
+	if loaded {
+		// Another goroutine created the session first
+		return actual.(*SessionInfo), true
+	}
+
+	pool.activeCount.Add(1)
+	pool.totalCount.Add(1)
+	telemetry.SessionCreated()
+
+	log.Info().
+		Str("session_id", sessionID).
+		Msg("New MCP session created")
+
+	return session, true
+}
+
+// touchSession records activity on session and the user behind it
+func touchSession(session *SessionInfo, userID string) {
+	session.mu.Lock()
+	session.LastActive = time.Now()
+	if userID != "" {
+		session.UserID = userID
+	}
+	session.mu.Unlock()
+	session.MessageCount.Add(1)
+}
+
+// sendSSEMessage sends a message to a session's SSE channel (for server-initiated messages)
+func sendSSEMessage(sessionID string, msg MCPMessage) error {
+	sessionVal, ok := pool.sessions.Load(sessionID)
+	if !ok {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	session := sessionVal.(*SessionInfo)
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	select {
+	case session.SSEChannel <- data:
+		return nil
+	default:
+		telemetry.SSEMessageDropped()
+		return fmt.Errorf("SSE channel full for session: %s", sessionID)
+	}
+}
+
+// sessionIdleTimeout reads SESSION_IDLE_TIMEOUT (a Go duration string)
+func sessionIdleTimeout() time.Duration {
+	if raw := os.Getenv("SESSION_IDLE_TIMEOUT"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+		log.Warn().Str("value", raw).Msg("Invalid SESSION_IDLE_TIMEOUT, using default")
+	}
+	return defaultSessionIdleTimeout
+}
+
+// runSessionReaper periodically removes sessions that have been idle for
+// longer than idleTimeout. Sessions with an open SSE stream are kept: the
+// stream itself is activity.
+func runSessionReaper(ctx context.Context, idleTimeout time.Duration) {
+	ticker := time.NewTicker(sessionReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expireIdleSessions(now, idleTimeout)
+		}
+	}
+}
+
+// expireIdleSessions drops every idle session without an SSE stream
+func expireIdleSessions(now time.Time, idleTimeout time.Duration) {
+	pool.sessions.Range(func(key, value interface{}) bool {
+		session := value.(*SessionInfo)
+		if session.SSEConnected.Load() {
+			return true
+		}
+
+		session.mu.Lock()
+		idle := now.Sub(session.LastActive)
+		session.mu.Unlock()
+
+		if idle > idleTimeout {
+			removeSession(key, session)
+			telemetry.SessionExpired()
+
+			log.Info().
+				Str("session_id", session.ID).
+				Dur("idle", idle).
+				Msg("MCP session expired")
+		}
+		return true
+	})
+}
+
+// removeSession drops session from the pool along with its edit history
+func removeSession(key interface{}, session *SessionInfo) {
+	if _, loaded := pool.sessions.LoadAndDelete(key); !loaded {
+		return
+	}
+	pool.activeCount.Add(-1)
+	editHistory.Forget(session.ID)
+	elicitations.CloseSession(session.ID)
+}
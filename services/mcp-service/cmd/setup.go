@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/cache"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/editlock"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/elicitation"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/health"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/history"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/metrics"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/origin"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// Document store backends selectable with DOCS_BACKEND
+const (
+	docsBackendGoogle = "google"
+	docsBackendMemory = "memory"
+)
+
+// Google credentials selectable with GOOGLE_CREDENTIALS
+const (
+	googleCredentialsCaller = "caller"
+	googleCredentialsShared = "shared"
+)
+
+// Configuration errors reported by setupDocsStore
+var (
+	errMissingDocsBackend       = errors.New("DOCS_BACKEND is not set; use google, or memory for local development")
+	errUnknownDocsBackend       = errors.New("unknown DOCS_BACKEND")
+	errUnknownGoogleCredentials = errors.New("unknown GOOGLE_CREDENTIALS")
+	errMissingGoogleCredentials = errors.New("shared google credentials need GOOGLE_ACCESS_TOKEN or GOOGLE_REFRESH_TOKEN")
+)
+
+// setupRedis connects to REDIS_URL when it is set. Redis is optional:
+// components that need it fail at startup if it is missing.
+func setupRedis(ctx context.Context) (*redis.Client, error) {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		return nil, nil
+	}
+
+	return cache.NewRedisClient(ctx, url)
+}
+
+// setupRateLimiter builds the limiter from RATE_LIMIT_* configuration,
+// using Redis as the bucket store when the redis backend is selected
+func setupRateLimiter(redisClient *redis.Client) (*ratelimit.Limiter, error) {
+	cfg, err := ratelimit.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.Enabled && cfg.Backend == ratelimit.BackendRedis {
+		if redisClient == nil {
+			return nil, cache.ErrRedisURLMissing
+		}
+		store = ratelimit.NewRedisStore(redisClient)
+	}
+
+	log.Info().
+		Bool("enabled", cfg.Enabled).
+		Str("backend", cfg.Backend).
+		Msg("Rate limiting configured")
+
+	return ratelimit.NewLimiter(cfg, store), nil
+}
+
+// setupEditLocks builds the per-document edit lock from EDIT_LOCK_*
+// configuration. Without EDIT_LOCK_BACKEND, locks are shared through
+// Redis whenever REDIS_URL is configured, since that is how replicas
+// share state, and kept in process otherwise.
+func setupEditLocks(redisClient *redis.Client) (editlock.Locker, error) {
+	cfg, err := editlock.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Backend == "" {
+		cfg.Backend = editlock.BackendMemory
+		if redisClient != nil {
+			cfg.Backend = editlock.BackendRedis
+		}
+	}
+
+	log.Info().
+		Str("backend", cfg.Backend).
+		Dur("wait_timeout", cfg.WaitTimeout).
+		Int("max_queue", cfg.MaxQueue).
+		Msg("Edit locks configured")
+
+	if cfg.Backend == editlock.BackendRedis {
+		if redisClient == nil {
+			return nil, cache.ErrRedisURLMissing
+		}
+		return editlock.NewRedisLocker(redisClient, cfg), nil
+	}
+
+	return editlock.NewMemoryLocker(cfg), nil
+}
+
+// setupDocsStore selects where edits are applied. DOCS_BACKEND=google
+// calls the Docs API with each caller's own bearer token; with
+// GOOGLE_CREDENTIALS=shared, a single-tenant development mode, every
+// caller shares GOOGLE_ACCESS_TOKEN or the access tokens minted from
+// GOOGLE_REFRESH_TOKEN instead. memory keeps documents in process and
+// creates unknown IDs on first use. There is no default: a deploy
+// that forgot DOCS_BACKEND must not report edits that never reach Google.
+func setupDocsStore() (docs.Store, error) {
+	accessToken := os.Getenv("GOOGLE_ACCESS_TOKEN")
+	refreshToken := os.Getenv("GOOGLE_REFRESH_TOKEN")
+
+	switch backend := os.Getenv("DOCS_BACKEND"); backend {
+	case "":
+		return nil, errMissingDocsBackend
+
+	case docsBackendMemory:
+		log.Warn().Msg("Using in-memory document store - edits are not sent to Google Docs")
+		return docs.NewMemoryStore(true), nil
+
+	case docsBackendGoogle:
+		httpClient := &http.Client{Timeout: googleAPITimeout, Transport: newGoogleTransport()}
+
+		var tokens docs.TokenSource
+		switch credentials := os.Getenv("GOOGLE_CREDENTIALS"); {
+		case credentials == "" || credentials == googleCredentialsCaller:
+			tokens = docs.CallerTokenSource{}
+		case credentials != googleCredentialsShared:
+			return nil, fmt.Errorf("%w: %q", errUnknownGoogleCredentials, credentials)
+		case accessToken != "":
+			log.Warn().Msg("Using shared Google credentials - every caller edits as the same Google identity")
+			tokens = docs.StaticTokenSource(accessToken)
+		case refreshToken != "":
+			log.Warn().Msg("Using shared Google credentials - every caller edits as the same Google identity")
+			tokens = &docs.RefreshTokenSource{
+				TokenURL:     envOrDefault("GOOGLE_TOKEN_URL", defaultGoogleTokenURL),
+				ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+				ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+				RefreshToken: refreshToken,
+				HTTPClient:   httpClient,
+			}
+		default:
+			return nil, errMissingGoogleCredentials
+		}
+
+		baseURL := envOrDefault("GOOGLE_DOCS_API_URL", docs.DefaultBaseURL)
+		log.Info().Str("base_url", baseURL).Msg("Using Google Docs API document store")
+
+		return docs.NewClient(baseURL, httpClient, tokens), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownDocsBackend, backend)
+	}
+}
+
+// setupEditor builds the edit pipeline over store. Edits too large for
+// one batchUpdate are split by DOCS_BATCH_MAX_REQUESTS (requests per
+// batchUpdate) and DOCS_BATCH_MAX_BYTES (their encoded size).
+func setupEditor(store docs.Store) *operations.Editor {
+	limits := operations.ChunkLimits{
+		MaxRequests: envInt("DOCS_BATCH_MAX_REQUESTS", operations.DefaultChunkLimits.MaxRequests),
+		MaxBytes:    envInt("DOCS_BATCH_MAX_BYTES", operations.DefaultChunkLimits.MaxBytes),
+	}
+
+	log.Info().
+		Int("max_requests", limits.MaxRequests).
+		Int("max_bytes", limits.MaxBytes).
+		Msg("Edit batching configured")
+
+	e := operations.NewEditor(store)
+	e.SetChunkLimits(limits)
+
+	return e
+}
+
+// setupAllowedOrigins reads the browser origins allowed to call the MCP
+// endpoints from CORS_ALLOWED_ORIGINS, a comma-separated list like the
+// backend's. Unset, only pages served from this machine are allowed.
+func setupAllowedOrigins() (*origin.Allowlist, error) {
+	raw := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if raw == "" {
+		raw = origin.DefaultLocal
+	}
+
+	allowed, err := origin.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == "*" {
+		log.Warn().Msg("Accepting every Origin - MCP endpoints are open to DNS rebinding")
+	} else {
+		log.Info().Str("allowed_origins", allowed.String()).Msg("Origin allowlist configured")
+	}
+
+	return allowed, nil
+}
+
+// setupDocumentRefs admits IDs matching DOCUMENT_ID_TEST_PATTERN besides
+// real Google document IDs. Only test environments should set it.
+func setupDocumentRefs() (*docs.ReferenceParser, error) {
+	pattern := os.Getenv("DOCUMENT_ID_TEST_PATTERN")
+	if pattern == "" {
+		return &docs.ReferenceParser{}, nil
+	}
+
+	testIDs, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Warn().Str("pattern", pattern).Msg("Accepting test document IDs")
+
+	return &docs.ReferenceParser{TestIDs: testIDs}, nil
+}
+
+// setupEditHistory bounds the edit history with EDIT_HISTORY_LIMIT (edits
+// kept per session and document, 0 disables it) and
+// EDIT_HISTORY_MAX_DOCUMENTS (session and document pairs kept)
+func setupEditHistory() *history.Store {
+	limit := envInt("EDIT_HISTORY_LIMIT", history.DefaultLimit)
+	maxDocuments := envInt("EDIT_HISTORY_MAX_DOCUMENTS", history.DefaultMaxDocuments)
+
+	log.Info().
+		Int("limit", limit).
+		Int("max_documents", maxDocuments).
+		Msg("Edit history configured")
+
+	return history.NewStore(limit, maxDocuments)
+}
+
+// setupElicitations bounds how long an edit waits for the user to choose
+// a match with ELICITATION_TIMEOUT
+func setupElicitations() *elicitation.Broker {
+	timeout := elicitation.DefaultTimeout
+	if raw := os.Getenv("ELICITATION_TIMEOUT"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			timeout = parsed
+		} else {
+			log.Warn().Str("value", raw).Msg("Invalid ELICITATION_TIMEOUT, using default")
+		}
+	}
+
+	log.Info().Dur("timeout", timeout).Msg("Elicitation configured")
+
+	return elicitation.NewBroker(timeout)
+}
+
+// setupHealth registers the dependency checkers reported by /health and
+// /readyz. Redis is critical when configured; Google reachability and
+// SSE stream pressure only degrade the service.
+func setupHealth(redisClient *redis.Client) *health.Registry {
+	registry := health.NewRegistry(health.DefaultTimeout, health.DefaultCacheTTL)
+
+	if redisClient != nil {
+		registry.Register(health.NewRedisChecker(redisClient), true)
+	}
+
+	if os.Getenv("HEALTH_CHECK_GOOGLE") != "false" {
+		registry.Register(health.NewHTTPChecker(
+			"googleAPI",
+			&http.Client{
+				Timeout:   health.DefaultTimeout,
+				Transport: newGoogleTransport(),
+			},
+			envOrDefault("GOOGLE_DISCOVERY_URL", defaultGoogleDiscoveryURL),
+			envOrDefault("GOOGLE_TOKEN_URL", defaultGoogleTokenURL),
+		), false)
+	}
+
+	maxStreams := int64(defaultMaxSSEStreams)
+	if raw := os.Getenv("SSE_MAX_STREAMS"); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			maxStreams = parsed
+		}
+	}
+	registry.Register(health.NewGoroutineChecker(pool.sseStreams.Load, maxStreams), false)
+
+	return registry
+}
+
+// newGoogleTransport returns the HTTP transport used for every Google API
+// call: traced (span + traceparent) and timed for metrics
+func newGoogleTransport() http.RoundTripper {
+	return tracing.NewTransport(metrics.NewRoundTripper(nil, telemetry))
+}
+
+// envOrDefault returns the environment variable or fallback when unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envInt parses a non-negative integer environment variable, falling
+// back when it is unset or invalid
+func envInt(key string, fallback int) int {
+	if raw := os.Getenv(key); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			return parsed
+		}
+		log.Warn().Str("key", key).Str("value", raw).Msg("Invalid integer setting, using default")
+	}
+	return fallback
+}
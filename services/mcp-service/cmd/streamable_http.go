@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// mcpPostHandler handles POST /mcp for JSON-RPC messages
+func mcpPostHandler(c *fiber.Ctx) error {
+	// Get or create session ID
+	sessionID := c.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	// Get or create session
+	session, ok := getOrCreateSession(sessionID)
+	if !ok {
+		return drainingResponse(c)
+	}
+	touchSession(session, userIDFromRequest(c))
+
+	// Set session ID header in response
+	c.Set("Mcp-Session-Id", sessionID)
+
+	mcpMsg, parseErr := parseMCPBody(c, sessionID)
+	if parseErr != "" {
+		return c.Status(400).SendString(parseErr)
+	}
+
+	response := processMCPMessage(c, mcpMsg, sessionID)
+	if response == nil {
+		// Notifications return 204 No Content
+		return c.SendStatus(204)
+	}
+
+	return sendMCPResponse(c, *response)
+}
+
+// drainingResponse refuses a new session while the service drains
+func drainingResponse(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderRetryAfter, "5")
+	return c.Status(fiber.StatusServiceUnavailable).SendString("Service is draining - no new sessions are accepted")
+}
+
+// parseMCPBody decodes the JSON-RPC message in the request body. On
+// failure it returns the text of the 400 response instead.
+func parseMCPBody(c *fiber.Ctx, sessionID string) (MCPMessage, string) {
+	var mcpMsg MCPMessage
+
+	body := c.Body()
+	if len(body) == 0 {
+		telemetry.IncJSONRPCError(-32700)
+		return mcpMsg, "Parse error - empty body"
+	}
+
+	if err := json.Unmarshal(body, &mcpMsg); err != nil {
+		log.Error().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("body", string(body)).
+			Msg("Failed to parse MCP message")
+
+		telemetry.IncJSONRPCError(-32700)
+		return mcpMsg, "Parse error - invalid JSON"
+	}
+
+	return mcpMsg, ""
+}
+
+// processMCPMessage runs one JSON-RPC message through the pipeline both
+// transports share: request span, validation, rate limits and method
+// dispatch. It returns nil for notifications, which get no response.
+func processMCPMessage(c *fiber.Ctx, mcpMsg MCPMessage, sessionID string) (response *MCPMessage) {
+	// Continue the caller's trace (W3C traceparent) with a span per request
+	ctx, span := startRequestSpan(c, mcpMsg.Method, sessionID)
+	defer func() {
+		if response != nil && response.Error != nil {
+			telemetry.IncJSONRPCError(response.Error.Code)
+			span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", response.Error.Code))
+			span.SetStatus(codes.Error, response.Error.Message)
+		}
+		span.End()
+	}()
+
+	// Validate JSON-RPC version
+	if mcpMsg.JSONRPC != "2.0" {
+		// JSON-RPC spec: return 200 with error in body
+		return &MCPMessage{
+			JSONRPC: "2.0",
+			ID:      mcpMsg.ID,
+			Error: &MCPError{
+				Code:    -32600,
+				Message: "Invalid Request - jsonrpc must be '2.0'",
+			},
+		}
+	}
+
+	// Responses answer the server's own requests, such as elicitations,
+	// and are not subject to request budgets
+	if mcpMsg.Method == "" && mcpMsg.ID != nil && (mcpMsg.Result != nil || mcpMsg.Error != nil) {
+		handleClientResponse(ctx, mcpMsg, sessionID)
+		return nil
+	}
+
+	// Google calls made for this request use the caller's own token
+	ctx = docs.WithCallerToken(ctx, bearerToken(c))
+
+	// Enforce per-user and per-session request budgets. Tool calls are
+	// charged in dispatchToolCall instead, together with the budget of the
+	// document they edit, so a call denied in one scope costs no other.
+	subject := ratelimit.Subject{UserID: userIDFromRequest(c), SessionID: sessionID}
+	ctx = ratelimit.WithSubject(ctx, subject)
+	if mcpMsg.Method != "tools/call" {
+		if err := limiter.Allow(ctx, subject); err != nil {
+			limited := rateLimitedResponse(mcpMsg.ID, err)
+			return &limited
+		}
+	}
+
+	// Handle notifications (no ID, no response needed)
+	if mcpMsg.Method != "" && mcpMsg.ID == nil {
+		log.Info().
+			Ctx(ctx).
+			Str("session_id", sessionID).
+			Str("method", mcpMsg.Method).
+			Msg("Received MCP notification")
+		return nil
+	}
+
+	// Validate method field for requests
+	if mcpMsg.Method == "" && mcpMsg.Result == nil && mcpMsg.Error == nil {
+		// JSON-RPC spec: return 200 with error in body
+		return &MCPMessage{
+			JSONRPC: "2.0",
+			ID:      mcpMsg.ID,
+			Error: &MCPError{
+				Code:    -32600,
+				Message: "Invalid Request - method field is required",
+			},
+		}
+	}
+
+	log.Info().
+		Ctx(ctx).
+		Str("session_id", sessionID).
+		Str("method", mcpMsg.Method).
+		Interface("id", mcpMsg.ID).
+		Msg("Processing MCP request")
+
+	// Handle MCP methods
+	start := time.Now()
+	handled := handleMCPMethod(ctx, mcpMsg, sessionID)
+	telemetry.ObserveRequest(methodLabel(mcpMsg.Method), time.Since(start))
+
+	return &handled
+}
+
+// startRequestSpan extracts the W3C trace context from the request
+// headers and opens the server span for one JSON-RPC message
+func startRequestSpan(c *fiber.Ctx, method, sessionID string) (context.Context, trace.Span) {
+	headers := http.Header{}
+	for key, values := range c.GetReqHeaders() {
+		for _, value := range values {
+			headers.Add(key, value)
+		}
+	}
+	ctx := tracing.Extract(c.UserContext(), headers)
+
+	spanName := method
+	if spanName == "" {
+		spanName = "jsonrpc"
+	}
+
+	return tracing.Tracer().Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("mcp.session_id", sessionID),
+		),
+	)
+}
+
+// sendMCPResponse writes a JSON-RPC response, mapping rate limit errors
+// onto HTTP 429 with a Retry-After header
+func sendMCPResponse(c *fiber.Ctx, response MCPMessage) error {
+	if response.Error != nil && response.Error.Code == errCodeRateLimited {
+		if data, ok := response.Error.Data.(RateLimitErrorData); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(data.RetryAfter))
+			c.Status(fiber.StatusTooManyRequests)
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// userIDFromRequest derives a stable, non-reversible user key from the
+// bearer token. Anonymous requests return "" and are only limited per
+// session and per document.
+func userIDFromRequest(c *fiber.Ctx) string {
+	token := bearerToken(c)
+	if token == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return "user-" + hex.EncodeToString(sum[:8])
+}
+
+// bearerToken returns the caller's bearer token, "" when there is none
+func bearerToken(c *fiber.Ctx) string {
+	const bearerPrefix = "Bearer "
+
+	auth := c.Get(fiber.HeaderAuthorization)
+	if len(auth) <= len(bearerPrefix) || auth[:len(bearerPrefix)] != bearerPrefix {
+		return ""
+	}
+
+	return auth[len(bearerPrefix):]
+}
+
+// mcpSSEHandler handles GET /mcp for SSE stream
+func mcpSSEHandler(c *fiber.Ctx) error {
+	// Get session ID from header
+	sessionID := c.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	// Get or create session
+	session, ok := getOrCreateSession(sessionID)
+	if !ok {
+		return drainingResponse(c)
+	}
+
+	log.Info().
+		Str("session_id", sessionID).
+		Msg("SSE stream requested")
+
+	// Set SSE headers
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("Mcp-Session-Id", sessionID)
+
+	// Use streaming response
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		pool.sseStreams.Add(1)
+		session.SSEConnected.Store(true)
+		defer func() {
+			session.SSEConnected.Store(false)
+			pool.sseStreams.Add(-1)
+		}()
+
+		// Send initial ping to establish connection
+		fmt.Fprintf(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		w.Flush()
+
+		// Listen for messages on session channel until the session is closed
+		for {
+			select {
+			case msg := <-session.SSEChannel:
+				fmt.Fprintf(w, "data: %s\n\n", msg)
+			case <-session.done:
+				return
+			}
+
+			if err := w.Flush(); err != nil {
+				log.Warn().
+					Err(err).
+					Str("session_id", sessionID).
+					Msg("SSE write error, closing stream")
+				return
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/schema"
+)
+
+// documentIDProperty is the input schema of the documentId accepted by
+// every tool
+var documentIDProperty = map[string]interface{}{
+	"type":        "string",
+	"minLength":   1,
+	"description": "Google Docs document ID",
+}
+
+// dryRunProperty and requiredRevisionProperty are the input schemas of
+// the options shared by every edit tool
+var (
+	dryRunProperty = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Preview the edit without applying it: return the Docs API requests, matched ranges and a before/after diff",
+	}
+	requiredRevisionProperty = map[string]interface{}{
+		"type":        "string",
+		"description": "Only apply the edit if the document is still at this revisionId (as returned by a previous edit)",
+	}
+)
+
+// segmentProperty is the input schema of the document segment accepted
+// by the edit tools
+var segmentProperty = map[string]interface{}{
+	"type":        "string",
+	"minLength":   1,
+	"description": "Part of the document to edit: \"body\" (the default), \"header\", \"footer\", or a header, footer or footnote ID",
+}
+
+// sectionProperty is the input schema of the heading-path scope accepted
+// by the edit tools
+var sectionProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Heading path such as \"Installation > Linux\" limiting the edit to the body under that heading, up to the next heading of the same or a higher level",
+}
+
+// fuzzyProperty and minSimilarityProperty are the input schemas of the
+// fuzzy matching options accepted by the anchor-based edit tools
+var (
+	fuzzyProperty = map[string]interface{}{
+		"type":        "boolean",
+		"description": "If the anchor is not found as written, accept text similar to it; each match reports its confidence",
+	}
+	minSimilarityProperty = map[string]interface{}{
+		"type":        "number",
+		"minimum":     0,
+		"maximum":     1,
+		"description": "Lowest similarity a fuzzy match may have (default 0.8)",
+	}
+)
+
+// occurrenceProperty is the input schema of the match selection accepted
+// by the anchor-based edit tools
+var occurrenceProperty = map[string]interface{}{
+	"description": "Which anchor matches to act on: \"all\" (default), \"first\", \"last\", a 1-based index or a list of indexes as numbered in the result's matches",
+	"oneOf": []interface{}{
+		map[string]interface{}{"type": "string", "enum": []string{"all", "first", "last"}},
+		map[string]interface{}{"type": "integer", "minimum": 1},
+		map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer", "minimum": 1}, "minItems": 1},
+	},
+}
+
+// toolCatalog is the tools/list result. Each inputSchema is also what
+// tools/call arguments are validated against before dispatch.
+var toolCatalog = []interface{}{
+	map[string]interface{}{
+		"name":        "replaceAll",
+		"description": "Replace entire content of a Google Doc",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "New content to replace document with",
+				},
+			},
+			"required":             []string{"documentId", "content"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "replace_all",
+		"description": "Replace entire content of a Google Doc",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "New content to replace document with",
+				},
+			},
+			"required":             []string{"documentId", "content"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "append",
+		"description": "Append content to a Google Doc at the end or after specified anchor text",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Content to append to the document",
+				},
+				"anchorText": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional text to find and append after",
+				},
+				"occurrence":    occurrenceProperty,
+				"fuzzy":         fuzzyProperty,
+				"minSimilarity": minSimilarityProperty,
+			},
+			"required":             []string{"documentId", "content"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "prepend",
+		"description": "Prepend content to the beginning of a Google Doc",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Content to prepend to the document",
+				},
+			},
+			"required":             []string{"documentId", "content"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "insertBefore",
+		"description": "Insert content before specified anchor text in a Google Doc",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Content to insert",
+				},
+				"anchorText": map[string]interface{}{
+					"type":        "string",
+					"minLength":   1,
+					"description": "Text to find and insert before",
+				},
+				"occurrence":    occurrenceProperty,
+				"fuzzy":         fuzzyProperty,
+				"minSimilarity": minSimilarityProperty,
+			},
+			"required":             []string{"documentId", "content", "anchorText"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "insertAfter",
+		"description": "Insert content after specified anchor text in a Google Doc",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Content to insert",
+				},
+				"anchorText": map[string]interface{}{
+					"type":        "string",
+					"minLength":   1,
+					"description": "Text to find and insert after",
+				},
+				"occurrence":    occurrenceProperty,
+				"fuzzy":         fuzzyProperty,
+				"minSimilarity": minSimilarityProperty,
+			},
+			"required":             []string{"documentId", "content", "anchorText"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "batch_edit",
+		"description": "Apply several edits to a Google Doc as one atomic update. Anchors are resolved against the document as it was before any operation, and either every operation is applied or none is; batches too large for one update are refused with EDIT_TOO_LARGE and footnotes cannot be added",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"documentId":         documentIDProperty,
+				"operations": map[string]interface{}{
+					"type":        "array",
+					"description": "Edits to apply, in order",
+					"minItems":    1,
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"mode": map[string]interface{}{
+								"type":        "string",
+								"enum":        operations.Modes(),
+								"description": "Edit mode",
+							},
+							"content": map[string]interface{}{
+								"type":        "string",
+								"description": "Markdown content to insert or replace with",
+							},
+							"anchorText": map[string]interface{}{
+								"type":        "string",
+								"description": "Text locating the target; required by replace_match, insert_before and insert_after",
+							},
+							"caseSensitive": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Match anchorText case-sensitively (default false)",
+							},
+							"occurrence":    occurrenceProperty,
+							"section":       sectionProperty,
+							"fuzzy":         fuzzyProperty,
+							"minSimilarity": minSimilarityProperty,
+						},
+						"required":             []string{"mode", "content"},
+						"additionalProperties": false,
+					},
+				},
+			},
+			"required":             []string{"documentId", "operations"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "replace_section",
+		"description": "Replace the body under a heading of a Google Doc, up to the next heading of the same or a higher level; the heading itself is kept",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"documentId":         documentIDProperty,
+				"section": map[string]interface{}{
+					"type":        "string",
+					"pattern":     `\S`,
+					"description": "Heading path such as \"Installation > Linux\"; each step matches a heading nested below the previous one, ignoring case",
+				},
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "Markdown content for the section body",
+				},
+			},
+			"required":             []string{"documentId", "section", "content"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "format_text",
+		"description": "Restyle text of a Google Doc without rewriting it: the matches of anchorText, or the body of a section. Text styles apply to the matched text; namedStyle, alignment and bullets to the paragraphs it touches",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":            dryRunProperty,
+				"requiredRevisionId": requiredRevisionProperty,
+				"segment":            segmentProperty,
+				"section":            sectionProperty,
+				"documentId":         documentIDProperty,
+				"anchorText": map[string]interface{}{
+					"type":        "string",
+					"description": "Text to restyle; without it the whole section body is restyled",
+				},
+				"caseSensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Match anchorText case-sensitively (default false)",
+				},
+				"occurrence":    occurrenceProperty,
+				"fuzzy":         fuzzyProperty,
+				"minSimilarity": minSimilarityProperty,
+				"bold":          map[string]interface{}{"type": "boolean"},
+				"italic":        map[string]interface{}{"type": "boolean"},
+				"underline":     map[string]interface{}{"type": "boolean"},
+				"strikethrough": map[string]interface{}{"type": "boolean"},
+				"link": map[string]interface{}{
+					"type":        "string",
+					"description": "URL to link the text to; an empty string removes the link",
+				},
+				"fontFamily": map[string]interface{}{
+					"type":        "string",
+					"description": "Font name such as \"Roboto\"; an empty string restores the default",
+				},
+				"fontSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Font size in points",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Text color as #RRGGBB; an empty string restores the default",
+				},
+				"namedStyle": map[string]interface{}{
+					"type":        "string",
+					"enum":        operations.NamedStyles(),
+					"description": "Paragraph style, e.g. HEADING_2 or NORMAL_TEXT",
+				},
+				"alignment": map[string]interface{}{
+					"type": "string",
+					"enum": []string{"START", "CENTER", "END", "JUSTIFIED"},
+				},
+				"bullets": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{operations.BulletsDisc, operations.BulletsNumbered, operations.BulletsNone},
+					"description": "Turn the paragraphs into a bulleted or numbered list, or remove their bullets",
+				},
+			},
+			"required": []string{"documentId"},
+			"anyOf": []interface{}{
+				map[string]interface{}{"required": []string{"anchorText"}, "description": "anchorText restyles its matches"},
+				map[string]interface{}{"required": []string{"section"}, "description": "section restyles a whole section body"},
+			},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "revert_last_edit",
+		"description": "Undo the most recent edit this session made to a Google Doc by restoring the content it had before. Refused if the document changed since that edit, unless force is set",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"dry_run":    dryRunProperty,
+				"documentId": documentIDProperty,
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Revert even if the document changed after the edit, discarding those later changes in the affected range",
+				},
+			},
+			"required":             []string{"documentId"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "get_outline",
+		"description": "Read the heading tree of a Google Doc without changing it: each heading's level, text, path, headingId, index range and the word, table and image counts of its section, plus the same per document tab. Paths are the ones section-scoped edits accept",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"documentId": documentIDProperty,
+			},
+			"required":             []string{"documentId"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "search_document",
+		"description": "Find text in a Google Doc without changing it. Returns each match with its index range, paragraph, heading path and surrounding context, and an occurrence number that selects the same match in an edit with the same anchor and section",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"documentId": documentIDProperty,
+				"query": map[string]interface{}{
+					"type":        "string",
+					"minLength":   1,
+					"description": "Text to find, or a regular expression when regex is set",
+				},
+				"regex": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Treat query as an RE2 regular expression; paragraphs end with a newline",
+				},
+				"caseSensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Match letter case exactly; by default case is ignored",
+				},
+				"section": map[string]interface{}{
+					"type":        "string",
+					"description": "Heading path such as \"Installation > Linux\" limiting the search to the body under that heading",
+				},
+				"offset": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     0,
+					"description": "Number of matches to skip, as returned in next_offset",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     1,
+					"maximum":     operations.MaxSearchLimit,
+					"description": fmt.Sprintf("Maximum number of matches to return, %d by default", operations.DefaultSearchLimit),
+				},
+			},
+			"required":             []string{"documentId", "query"},
+			"additionalProperties": false,
+		},
+	},
+	map[string]interface{}{
+		"name":        "list_edit_history",
+		"description": "List the edits this session made to a Google Doc that can still be reverted, newest first",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"documentId": documentIDProperty,
+			},
+			"required":             []string{"documentId"},
+			"additionalProperties": false,
+		},
+	},
+}
+
+// toolInputSchemas maps each tool name to its inputSchema
+var toolInputSchemas = inputSchemas(toolCatalog)
+
+// inputSchemas collects the catalog's inputSchemas, panicking at startup
+// on a schema that could not validate anything, such as a broken pattern
+func inputSchemas(catalog []interface{}) map[string]schema.Schema {
+	schemas := make(map[string]schema.Schema, len(catalog))
+	for _, item := range catalog {
+		tool, _ := item.(map[string]interface{})
+		name, _ := tool["name"].(string)
+		if inputSchema, ok := tool["inputSchema"].(map[string]interface{}); ok {
+			if err := schema.Check(inputSchema); err != nil {
+				panic(fmt.Sprintf("tool %s: %v", name, err))
+			}
+			schemas[name] = inputSchema
+		}
+	}
+
+	return schemas
+}
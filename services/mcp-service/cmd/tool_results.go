@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/editlock"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/elicitation"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// ToolErrorResult is the structuredContent of a failed edit, following
+// the output schema of the design document
+type ToolErrorResult struct {
+	Type      string                    `json:"type"`
+	Code      string                    `json:"code"`
+	Message   string                    `json:"message"`
+	Operation *int                      `json:"operation,omitempty"`
+	Hints     []operations.Hint         `json:"hints,omitempty"`
+	Outline   []string                  `json:"outline,omitempty"`
+	Matches   []operations.MatchPreview `json:"matches,omitempty"`
+	Segments  []string                  `json:"segments,omitempty"`
+	Chunks    []operations.Chunk        `json:"chunks,omitempty"`
+
+	RequiredRevisionID string `json:"requiredRevisionId,omitempty"`
+	CurrentRevisionID  string `json:"currentRevisionId,omitempty"`
+	RevisionID         string `json:"revisionId,omitempty"`
+}
+
+// toolResult wraps a successful tool outcome: text for display plus the
+// structured result
+func toolResult(requestID interface{}, text string, structured interface{}) MCPMessage {
+	return MCPMessage{
+		JSONRPC: "2.0",
+		ID:      requestID,
+		Result: map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{
+					"type": "text",
+					"text": text,
+				},
+			},
+			"structuredContent": structured,
+			"isError":           false,
+		},
+	}
+}
+
+// toolErrorResponse wraps a structured tool error with isError set
+func toolErrorResponse(requestID interface{}, body ToolErrorResult) MCPMessage {
+	return MCPMessage{
+		JSONRPC: "2.0",
+		ID:      requestID,
+		Result: map[string]interface{}{
+			"content": []interface{}{
+				map[string]interface{}{
+					"type": "text",
+					"text": fmt.Sprintf("error: %s: %s", body.Code, body.Message),
+				},
+			},
+			"structuredContent": body,
+			"isError":           true,
+		},
+	}
+}
+
+// editErrorResponse maps an edit failure onto a tool error result.
+// Invalid operations are reported as JSON-RPC invalid params instead,
+// since the request itself is malformed.
+func editErrorResponse(requestID interface{}, batch bool, err error) MCPMessage {
+	if errors.Is(err, operations.ErrInvalidOperation) {
+		return MCPMessage{
+			JSONRPC: "2.0",
+			ID:      requestID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: fmt.Sprintf("Invalid params - %v", err),
+			},
+		}
+	}
+
+	body := ToolErrorResult{Type: "error", Code: "GOOGLE_API_ERROR", Message: err.Error()}
+
+	var opErr *operations.OperationError
+	var mismatch *operations.RevisionMismatchError
+	var partial *operations.PartialEditError
+	switch {
+	case errors.As(err, &partial):
+		body.Code = "PARTIALLY_APPLIED"
+		body.Message = fmt.Sprintf("The edit was split into %d batches and only the first %d were applied before: %v",
+			len(partial.Chunks), partial.Applied, partial.Err)
+		body.Hints = []operations.Hint{
+			{Action: "revert", Label: "Revert the applied part with revert_last_edit"},
+			{Action: "ask_user", Label: "Ask the user"},
+		}
+		body.Chunks = partial.Chunks
+		body.RevisionID = partial.RevisionID
+	case errors.As(err, &mismatch):
+		body.Code = "REVISION_MISMATCH"
+		body.Message = "The document changed since the required revision; re-read it before editing again"
+		body.RequiredRevisionID = mismatch.Required
+		body.CurrentRevisionID = mismatch.Current
+	case errors.Is(err, errEditCancelled), errors.Is(err, elicitation.ErrSessionClosed):
+		body.Code = "EDIT_CANCELLED"
+		body.Hints = []operations.Hint{{Action: "ask_user", Label: "Ask the user"}}
+	case errors.Is(err, editlock.ErrTimeout), errors.Is(err, editlock.ErrQueueFull):
+		body.Code = "DOCUMENT_BUSY"
+		body.Message = fmt.Sprintf("Other edits to the document kept it busy after the user chose a match (%v); retry shortly", err)
+	case errors.Is(err, elicitation.ErrTimeout):
+		body.Code = "ELICITATION_TIMEOUT"
+		body.Message = fmt.Sprintf("The user did not choose which match to change: %v", err)
+		body.Hints = []operations.Hint{
+			{Action: "set_occurrence", Label: "Retry with an explicit occurrence"},
+			{Action: "ask_user", Label: "Ask the user"},
+		}
+	case errors.Is(err, operations.ErrEditTooLarge):
+		body.Code = "EDIT_TOO_LARGE"
+		body.Message = fmt.Sprintf("The edit is too large to apply all-or-nothing (%v); nothing was applied", err)
+		body.Hints = []operations.Hint{
+			{Action: "split_edit", Label: "Send the operations in several smaller batch_edit calls"},
+			{Action: "ask_user", Label: "Ask the user"},
+		}
+	case errors.As(err, &opErr):
+		body.Code = opErr.Code
+		body.Message = opErr.Message
+		body.Hints = opErr.Hints
+		body.Outline = opErr.Outline
+		body.Matches = opErr.Matches
+		body.Segments = opErr.Segments
+		if batch {
+			body.Operation = &opErr.Operation
+		}
+	case errors.Is(err, docs.ErrDocumentNotFound):
+		body.Code = "DOCUMENT_NOT_FOUND"
+	case errors.Is(err, docs.ErrPermissionDenied):
+		body.Code = "PERMISSION_DENIED"
+	case errors.Is(err, docs.ErrUnauthenticated):
+		body.Code = "UNAUTHENTICATED"
+	case errors.Is(err, docs.ErrNotRestorable):
+		body.Code = "NOT_RESTORABLE"
+	}
+
+	return toolErrorResponse(requestID, body)
+}
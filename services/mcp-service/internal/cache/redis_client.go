@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedisURLMissing signals that a Redis-backed component was enabled
+// without REDIS_URL being configured.
+var ErrRedisURLMissing = errors.New("REDIS_URL is required for the redis backend")
+
+const connectTimeout = 5 * time.Second
+
+// NewRedisClient parses a redis:// or rediss:// URL and returns a client
+// that has answered a PING. Failing here keeps a misconfigured replica
+// from silently degrading to per-process state.
+func NewRedisClient(ctx context.Context, url string) (*redis.Client, error) {
+	if url == "" {
+		return nil, ErrRedisURLMissing
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse REDIS_URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	pingCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,165 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the public Google Docs API endpoint.
+const DefaultBaseURL = "https://docs.googleapis.com"
+
+// maxErrorBody bounds how much of a failed response is read for the
+// error message.
+const maxErrorBody = 64 << 10
+
+// Client is a Store backed by the Google Docs REST API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	tokens  TokenSource
+}
+
+// NewClient returns a Client for baseURL (DefaultBaseURL when empty)
+// authenticating every call with a token from tokens.
+func NewClient(baseURL string, httpClient *http.Client, tokens TokenSource) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), http: httpClient, tokens: tokens}
+}
+
+// Get implements Store.
+func (c *Client) Get(ctx context.Context, documentID string) (*Document, error) {
+	var doc Document
+
+	if err := c.do(ctx, http.MethodGet, c.documentURL(documentID)+"?includeTabsContent=true", nil, &doc); err != nil {
+		return nil, err
+	}
+
+	// With tab contents the API leaves body empty; the first tab is the
+	// one edits without a tab ID apply to
+	if doc.Body == nil && len(doc.Tabs) > 0 && doc.Tabs[0].DocumentTab != nil {
+		tab := doc.Tabs[0].DocumentTab
+		doc.Body, doc.DocumentStyle = tab.Body, tab.DocumentStyle
+		doc.Headers, doc.Footers, doc.Footnotes = tab.Headers, tab.Footers, tab.Footnotes
+	}
+
+	return &doc, nil
+}
+
+// BatchUpdate implements Store.
+func (c *Client) BatchUpdate(ctx context.Context, documentID string, requests []Request, writeControl *WriteControl) (*BatchUpdateResponse, error) {
+	body := BatchUpdateRequest{Requests: requests, WriteControl: writeControl}
+
+	var resp BatchUpdateResponse
+
+	if err := c.do(ctx, http.MethodPost, c.documentURL(documentID)+":batchUpdate", body, &resp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && writeControl != nil {
+			apiErr.RequiredRevision = writeControl.RequiredRevisionID != ""
+		}
+
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) documentURL(documentID string) string {
+	return c.baseURL + "/v1/documents/" + url.PathEscape(documentID)
+}
+
+func (c *Client) do(ctx context.Context, method, target string, in, out interface{}) error {
+	var body io.Reader
+
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("obtain access token: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeAPIError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// decodeAPIError turns a Google error envelope into an *APIError,
+// falling back to the raw body when it is not JSON.
+func decodeAPIError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	var envelope struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				FieldViolations []struct {
+					Field string `json:"field"`
+				} `json:"fieldViolations"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Status: http.StatusText(resp.StatusCode)}
+
+	if err := json.Unmarshal(raw, &envelope); err == nil && envelope.Error.Message != "" {
+		apiErr.Message = envelope.Error.Message
+		if envelope.Error.Status != "" {
+			apiErr.Status = envelope.Error.Status
+		}
+		for _, detail := range envelope.Error.Details {
+			for _, violation := range detail.FieldViolations {
+				apiErr.Fields = append(apiErr.Fields, violation.Field)
+			}
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(raw))
+	}
+
+	return apiErr
+}
@@ -0,0 +1,185 @@
+package docs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+func TestORPHAN_Client_BatchUpdate_PostsRequestsWithBearerToken(t *testing.T) {
+	// Arrange
+	var got docs.BatchUpdateRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/documents/abc:batchUpdate", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"documentId":"abc"}`))
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.StaticTokenSource("secret"))
+
+	// Act
+	resp, err := client.BatchUpdate(context.Background(), "abc", []docs.Request{{
+		InsertText: &docs.InsertTextRequest{Text: "hi", Location: &docs.Location{Index: 1}},
+	}}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "abc", resp.DocumentID)
+	require.Len(t, got.Requests, 1)
+	assert.Equal(t, "hi", got.Requests[0].InsertText.Text)
+}
+
+func TestORPHAN_Client_Get_MapsGoogleErrorEnvelope(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND"}}`))
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.StaticTokenSource("secret"))
+
+	// Act
+	_, err := client.Get(context.Background(), "missing")
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrDocumentNotFound)
+
+	var apiErr *docs.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "NOT_FOUND", apiErr.Status)
+	assert.Equal(t, "Requested entity was not found.", apiErr.Message)
+}
+
+func TestORPHAN_Client_BatchUpdate_MapsRevisionMismatchByStatusOrField(t *testing.T) {
+	// Arrange
+	bodies := []string{
+		`{"error":{"code":400,"message":"Revision is stale.","status":"FAILED_PRECONDITION"}}`,
+		`{"error":{"code":400,"message":"Invalid value.","status":"INVALID_ARGUMENT","details":[` +
+			`{"@type":"type.googleapis.com/google.rpc.BadRequest",` +
+			`"fieldViolations":[{"field":"writeControl.requiredRevisionId"}]}]}}`,
+		`{"error":{"code":400,"message":"Invalid revision of the inline object.","status":"INVALID_ARGUMENT"}}`,
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(bodies[calls]))
+		calls++
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.StaticTokenSource("secret"))
+
+	// Act
+	errs := make([]error, len(bodies))
+	for i := range bodies {
+		_, errs[i] = client.BatchUpdate(context.Background(), "doc-1", nil, &docs.WriteControl{RequiredRevisionID: "rev-1"})
+	}
+
+	// Assert
+	require.ErrorIs(t, errs[0], docs.ErrRevisionMismatch)
+	require.ErrorIs(t, errs[1], docs.ErrRevisionMismatch)
+	require.ErrorIs(t, errs[2], docs.ErrInvalidRequest)
+	assert.NotErrorIs(t, errs[2], docs.ErrRevisionMismatch)
+}
+
+func TestORPHAN_Client_BatchUpdate_FailedPreconditionWithoutRevision_IsNotMismatch(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Precondition check failed.","status":"FAILED_PRECONDITION"}}`))
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.StaticTokenSource("secret"))
+
+	// Act
+	_, err := client.BatchUpdate(context.Background(), "doc-1", nil, nil)
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrInvalidRequest)
+	assert.NotErrorIs(t, err, docs.ErrRevisionMismatch)
+}
+
+func TestORPHAN_RefreshTokenSource_CachesTokenUntilExpiry(t *testing.T) {
+	// Arrange
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		_, _ = w.Write([]byte(`{"access_token":"fresh","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	source := &docs.RefreshTokenSource{TokenURL: server.URL, RefreshToken: "r", HTTPClient: server.Client()}
+
+	// Act
+	first, err := source.Token(context.Background())
+	require.NoError(t, err)
+	second, err := source.Token(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "fresh", first)
+	assert.Equal(t, "fresh", second)
+	assert.Equal(t, 1, calls)
+}
+
+func TestORPHAN_Client_CallerTokenSource_SendsEachCallersToken(t *testing.T) {
+	// Arrange
+	var got []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"documentId":"abc"}`))
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.CallerTokenSource{})
+
+	// Act
+	_, aliceErr := client.Get(docs.WithCallerToken(context.Background(), "alice"), "abc")
+	_, bobErr := client.Get(docs.WithCallerToken(context.Background(), "bob"), "abc")
+	_, anonymousErr := client.Get(context.Background(), "abc")
+
+	// Assert
+	require.NoError(t, aliceErr)
+	require.NoError(t, bobErr)
+	require.ErrorIs(t, anonymousErr, docs.ErrUnauthenticated)
+	assert.Equal(t, []string{"Bearer alice", "Bearer bob"}, got)
+}
+
+func TestORPHAN_Client_Get_ReadsTabsAndFirstTabAsBody(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("includeTabsContent"))
+		_, _ = w.Write([]byte(`{"documentId":"abc","tabs":[` +
+			`{"tabProperties":{"tabId":"t.0","title":"Notes"},"documentTab":{"body":{"content":[` +
+			`{"startIndex":1,"endIndex":4,"paragraph":{"elements":[{"startIndex":1,"endIndex":4,"textRun":{"content":"Hi\n"}}]}}]}},` +
+			`"childTabs":[{"tabProperties":{"tabId":"t.1","title":"Draft","nestingLevel":1}}]}]}`))
+	}))
+	defer server.Close()
+
+	client := docs.NewClient(server.URL, server.Client(), docs.StaticTokenSource("secret"))
+
+	// Act
+	doc, err := client.Get(context.Background(), "abc")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Hi\n", doc.Text())
+	require.Len(t, doc.Tabs, 1)
+	assert.Equal(t, "Notes", doc.Tabs[0].TabProperties.Title)
+	assert.Equal(t, "Draft", doc.Tabs[0].ChildTabs[0].TabProperties.Title)
+}
@@ -0,0 +1,311 @@
+package docs
+
+import "strings"
+
+// Document is the subset of the Google Docs REST resource that the
+// service reads. Field names and JSON tags follow the public API so the
+// same types decode real responses and the in-memory store's output.
+type Document struct {
+	DocumentID    string              `json:"documentId"`
+	Title         string              `json:"title,omitempty"`
+	RevisionID    string              `json:"revisionId,omitempty"`
+	Body          *Body               `json:"body,omitempty"`
+	DocumentStyle *DocumentStyle      `json:"documentStyle,omitempty"`
+	Headers       map[string]Header   `json:"headers,omitempty"`
+	Footers       map[string]Footer   `json:"footers,omitempty"`
+	Footnotes     map[string]Footnote `json:"footnotes,omitempty"`
+	Tabs          []Tab               `json:"tabs,omitempty"`
+}
+
+// Tab is one tab of a document read with includeTabsContent. Edits
+// without a tab ID address the first tab, whose body is also Body.
+type Tab struct {
+	TabProperties TabProperties `json:"tabProperties"`
+	ChildTabs     []Tab         `json:"childTabs,omitempty"`
+	DocumentTab   *DocumentTab  `json:"documentTab,omitempty"`
+}
+
+// TabProperties names a tab and places it in the tab tree.
+type TabProperties struct {
+	TabID        string `json:"tabId"`
+	Title        string `json:"title,omitempty"`
+	Index        int    `json:"index,omitempty"`
+	NestingLevel int    `json:"nestingLevel,omitempty"`
+}
+
+// DocumentTab is the content of a tab.
+type DocumentTab struct {
+	Body          *Body               `json:"body,omitempty"`
+	DocumentStyle *DocumentStyle      `json:"documentStyle,omitempty"`
+	Headers       map[string]Header   `json:"headers,omitempty"`
+	Footers       map[string]Footer   `json:"footers,omitempty"`
+	Footnotes     map[string]Footnote `json:"footnotes,omitempty"`
+}
+
+// DocumentStyle names the header and footer shown on most pages.
+type DocumentStyle struct {
+	DefaultHeaderID string `json:"defaultHeaderId,omitempty"`
+	DefaultFooterID string `json:"defaultFooterId,omitempty"`
+}
+
+// Header is a header segment. Its indexes start at 0.
+type Header struct {
+	HeaderID string              `json:"headerId"`
+	Content  []StructuralElement `json:"content"`
+}
+
+// Footer is a footer segment. Its indexes start at 0.
+type Footer struct {
+	FooterID string              `json:"footerId"`
+	Content  []StructuralElement `json:"content"`
+}
+
+// Footnote is the content of a footnote. Its indexes start at 0.
+type Footnote struct {
+	FootnoteID string              `json:"footnoteId"`
+	Content    []StructuralElement `json:"content"`
+}
+
+// Body is the main segment of a document.
+type Body struct {
+	Content []StructuralElement `json:"content"`
+}
+
+// StructuralElement is one block-level element. Exactly one of the
+// element pointers is set.
+type StructuralElement struct {
+	StartIndex      int              `json:"startIndex,omitempty"`
+	EndIndex        int              `json:"endIndex"`
+	Paragraph       *Paragraph       `json:"paragraph,omitempty"`
+	SectionBreak    *SectionBreak    `json:"sectionBreak,omitempty"`
+	Table           *Table           `json:"table,omitempty"`
+	TableOfContents *TableOfContents `json:"tableOfContents,omitempty"`
+}
+
+// SectionBreak marks the start of a section. Only its position matters
+// to the service.
+type SectionBreak struct{}
+
+// TableOfContents is an opaque table of contents block.
+type TableOfContents struct {
+	Content []StructuralElement `json:"content,omitempty"`
+}
+
+// Table is a table block.
+type Table struct {
+	Rows      int        `json:"rows"`
+	Columns   int        `json:"columns"`
+	TableRows []TableRow `json:"tableRows,omitempty"`
+}
+
+// TableRow is one table row.
+type TableRow struct {
+	StartIndex int         `json:"startIndex"`
+	EndIndex   int         `json:"endIndex"`
+	TableCells []TableCell `json:"tableCells,omitempty"`
+}
+
+// TableCell holds the content of one cell.
+type TableCell struct {
+	StartIndex int                 `json:"startIndex"`
+	EndIndex   int                 `json:"endIndex"`
+	Content    []StructuralElement `json:"content,omitempty"`
+}
+
+// Paragraph is a run of elements terminated by a newline.
+type Paragraph struct {
+	Elements       []ParagraphElement `json:"elements"`
+	ParagraphStyle *ParagraphStyle    `json:"paragraphStyle,omitempty"`
+	Bullet         *Bullet            `json:"bullet,omitempty"`
+}
+
+// ParagraphElement is one inline element of a paragraph.
+type ParagraphElement struct {
+	StartIndex          int                  `json:"startIndex,omitempty"`
+	EndIndex            int                  `json:"endIndex"`
+	TextRun             *TextRun             `json:"textRun,omitempty"`
+	InlineObjectElement *InlineObjectElement `json:"inlineObjectElement,omitempty"`
+	FootnoteReference   *FootnoteReference   `json:"footnoteReference,omitempty"`
+	PageBreak           *PageBreak           `json:"pageBreak,omitempty"`
+	HorizontalRule      *HorizontalRule      `json:"horizontalRule,omitempty"`
+}
+
+// TextRun is a run of text sharing one TextStyle.
+type TextRun struct {
+	Content   string     `json:"content"`
+	TextStyle *TextStyle `json:"textStyle,omitempty"`
+}
+
+// InlineObjectElement references an embedded object such as an image.
+type InlineObjectElement struct {
+	InlineObjectID string `json:"inlineObjectId"`
+}
+
+// FootnoteReference marks where a footnote is referenced in the body.
+type FootnoteReference struct {
+	FootnoteID     string `json:"footnoteId"`
+	FootnoteNumber string `json:"footnoteNumber,omitempty"`
+}
+
+// PageBreak is an inline page break.
+type PageBreak struct{}
+
+// HorizontalRule is an inline horizontal rule.
+type HorizontalRule struct{}
+
+// Bullet marks a paragraph as a list item.
+type Bullet struct {
+	ListID       string `json:"listId"`
+	NestingLevel int    `json:"nestingLevel,omitempty"`
+}
+
+// Named paragraph styles used by the service.
+const (
+	StyleNormalText = "NORMAL_TEXT"
+	StyleTitle      = "TITLE"
+	StyleSubtitle   = "SUBTITLE"
+	StyleHeading1   = "HEADING_1"
+	StyleHeading2   = "HEADING_2"
+	StyleHeading3   = "HEADING_3"
+	StyleHeading4   = "HEADING_4"
+	StyleHeading5   = "HEADING_5"
+	StyleHeading6   = "HEADING_6"
+)
+
+// ParagraphStyle is the subset of paragraph styling the service sets.
+type ParagraphStyle struct {
+	NamedStyleType string     `json:"namedStyleType,omitempty"`
+	HeadingID      string     `json:"headingId,omitempty"`
+	Alignment      string     `json:"alignment,omitempty"`
+	IndentStart    *Dimension `json:"indentStart,omitempty"`
+}
+
+// TextStyle is the subset of character styling the service reads and
+// writes.
+type TextStyle struct {
+	Bold               bool                `json:"bold,omitempty"`
+	Italic             bool                `json:"italic,omitempty"`
+	Underline          bool                `json:"underline,omitempty"`
+	Strikethrough      bool                `json:"strikethrough,omitempty"`
+	FontSize           *Dimension          `json:"fontSize,omitempty"`
+	WeightedFontFamily *WeightedFontFamily `json:"weightedFontFamily,omitempty"`
+	ForegroundColor    *OptionalColor      `json:"foregroundColor,omitempty"`
+	Link               *Link               `json:"link,omitempty"`
+}
+
+// Dimension is a magnitude in the given unit (always "PT" here).
+type Dimension struct {
+	Magnitude float64 `json:"magnitude"`
+	Unit      string  `json:"unit"`
+}
+
+// WeightedFontFamily names a font.
+type WeightedFontFamily struct {
+	FontFamily string `json:"fontFamily"`
+	Weight     int    `json:"weight,omitempty"`
+}
+
+// OptionalColor wraps a color; a nil Color means transparent.
+type OptionalColor struct {
+	Color *Color `json:"color,omitempty"`
+}
+
+// Color holds an RGB color.
+type Color struct {
+	RGBColor *RGBColor `json:"rgbColor,omitempty"`
+}
+
+// RGBColor components are in the range [0, 1].
+type RGBColor struct {
+	Red   float64 `json:"red,omitempty"`
+	Green float64 `json:"green,omitempty"`
+	Blue  float64 `json:"blue,omitempty"`
+}
+
+// Link is a hyperlink target.
+type Link struct {
+	URL string `json:"url,omitempty"`
+}
+
+// EndIndex returns the end index of the body, i.e. one past the final
+// newline. An empty document has an end index of 2.
+func (d *Document) EndIndex() int {
+	if d.Body == nil || len(d.Body.Content) == 0 {
+		return 1
+	}
+
+	return d.Body.Content[len(d.Body.Content)-1].EndIndex
+}
+
+// PreviewURL returns the browser URL of the document.
+func (d *Document) PreviewURL() string {
+	return PreviewURL(d.DocumentID)
+}
+
+// PreviewURL returns the browser URL for documentID.
+func PreviewURL(documentID string) string {
+	return "https://docs.google.com/document/d/" + documentID
+}
+
+// Text returns the plain text of the body, table cells included, with
+// one newline per paragraph.
+func (d *Document) Text() string {
+	return d.SegmentText("")
+}
+
+// SegmentText returns the plain text of segmentID the way Text does for
+// the body, or "" if there is no such segment.
+func (d *Document) SegmentText(segmentID string) string {
+	content, _ := d.Segment(segmentID)
+
+	var b strings.Builder
+
+	writeContent(&b, content)
+
+	return b.String()
+}
+
+// Segment returns the content of segmentID: the body when it is empty,
+// otherwise the header, footer or footnote with that ID.
+func (d *Document) Segment(segmentID string) ([]StructuralElement, bool) {
+	if segmentID == "" {
+		if d.Body == nil {
+			return nil, true
+		}
+
+		return d.Body.Content, true
+	}
+
+	if h, ok := d.Headers[segmentID]; ok {
+		return h.Content, true
+	}
+
+	if f, ok := d.Footers[segmentID]; ok {
+		return f.Content, true
+	}
+
+	if f, ok := d.Footnotes[segmentID]; ok {
+		return f.Content, true
+	}
+
+	return nil, false
+}
+
+func writeContent(b *strings.Builder, content []StructuralElement) {
+	for _, el := range content {
+		switch {
+		case el.Paragraph != nil:
+			for _, pe := range el.Paragraph.Elements {
+				if pe.TextRun != nil {
+					b.WriteString(pe.TextRun.Content)
+				}
+			}
+		case el.Table != nil:
+			for _, row := range el.Table.TableRows {
+				for _, cell := range row.TableCells {
+					writeContent(b, cell.Content)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Sentinel errors callers can match with errors.Is regardless of which
+// Store produced them.
+var (
+	ErrDocumentNotFound = errors.New("document not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidRequest   = errors.New("invalid request")
+	ErrUnauthenticated  = errors.New("unauthenticated")
+	ErrUnavailable      = errors.New("google docs api unavailable")
+	ErrRevisionMismatch = errors.New("document revision does not match requiredRevisionId")
+	ErrNotRestorable    = errors.New("content cannot be restored")
+)
+
+// APIError is a failed Google Docs API call. Fields lists the request
+// fields named by the envelope's field violations. RequiredRevision is
+// set when the failed request carried writeControl.requiredRevisionId.
+type APIError struct {
+	StatusCode       int
+	Status           string
+	Message          string
+	Fields           []string
+	RequiredRevision bool
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("google docs api: %d %s: %s", e.StatusCode, e.Status, e.Message)
+}
+
+// Unwrap maps the HTTP status onto a sentinel error. A rejected
+// writeControl surfaces as a 400, told apart from other invalid requests
+// by a field violation on the required revision, or by its
+// FAILED_PRECONDITION status when the request required a revision; never
+// by the wording of the message.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest &&
+		(e.violates(requiredRevisionFields...) || e.RequiredRevision && e.Status == "FAILED_PRECONDITION"):
+		return ErrRevisionMismatch
+	case e.StatusCode == http.StatusNotFound:
+		return ErrDocumentNotFound
+	case e.StatusCode == http.StatusForbidden:
+		return ErrPermissionDenied
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthenticated
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrInvalidRequest
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// requiredRevisionFields are the names a field violation of the
+// writeControl revision is reported under.
+var requiredRevisionFields = []string{"writeControl.requiredRevisionId", "write_control.required_revision_id"}
+
+func (e *APIError) violates(fields ...string) bool {
+	for _, field := range e.Fields {
+		if slices.Contains(fields, field) {
+			return true
+		}
+	}
+
+	return false
+}
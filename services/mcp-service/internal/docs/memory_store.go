@@ -0,0 +1,726 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// bodyBaseIndex is the index of the first character of the body. Index 0
+// is occupied by the implicit section break every document starts with.
+const bodyBaseIndex = 1
+
+// paragraphInfo is stored on the newline that terminates a paragraph.
+type paragraphInfo struct {
+	style  ParagraphStyle
+	bullet *Bullet
+}
+
+// segment is a flat UTF-16 model of one document segment, the same unit
+// the Docs API counts indexes in. Styles are kept per code unit so
+// requests can split and merge runs without bookkeeping; Get folds them
+// back into runs.
+type segment struct {
+	base   int
+	units  []uint16
+	styles []TextStyle
+	paras  []paragraphInfo
+}
+
+// memoryDocument is one document. Headers, footers and footnotes are
+// segments of their own, indexed from 0; created counts the segment IDs
+// handed out.
+type memoryDocument struct {
+	title     string
+	revision  int
+	body      *segment
+	lists     int
+	headers   map[string]*segment
+	footers   map[string]*segment
+	footnotes map[string]*segment
+	style     DocumentStyle
+	created   int
+}
+
+// MemoryStore is an in-process Store that follows the Docs API index
+// rules closely enough for the edit pipeline to be tested without
+// Google. With auto-create enabled, unknown IDs resolve to an empty
+// document instead of ErrDocumentNotFound.
+type MemoryStore struct {
+	mu         sync.Mutex
+	documents  map[string]*memoryDocument
+	autoCreate bool
+}
+
+// NewMemoryStore returns an empty store.
+func NewMemoryStore(autoCreate bool) *MemoryStore {
+	return &MemoryStore{documents: make(map[string]*memoryDocument), autoCreate: autoCreate}
+}
+
+// Create adds an empty document, replacing any existing one with the same ID.
+func (s *MemoryStore) Create(documentID, title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documents[documentID] = newMemoryDocument(title)
+}
+
+// Load adds doc under its DocumentID, replacing any existing document,
+// so fixtures captured from documents.get can be served again. Indexes
+// follow doc's own, with elements the flat model does not represent
+// kept as Placeholder characters.
+func (s *MemoryStore) Load(doc *Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documents[doc.DocumentID] = loadDocument(doc)
+}
+
+// Delete removes a document; later lookups fail or auto-create it again.
+func (s *MemoryStore) Delete(documentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.documents, documentID)
+}
+
+// Reset removes every document.
+func (s *MemoryStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.documents)
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, documentID string) (*Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.lookup(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.document(documentID), nil
+}
+
+// BatchUpdate implements Store. Requests are applied to a copy of the
+// document, which only replaces the original when all of them succeed.
+// A RequiredRevisionID other than the latest revision rejects the batch.
+func (s *MemoryStore) BatchUpdate(_ context.Context, documentID string, requests []Request, writeControl *WriteControl) (*BatchUpdateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.lookup(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if writeControl != nil && writeControl.RequiredRevisionID != "" &&
+		writeControl.RequiredRevisionID != revisionID(doc.revision) {
+		return nil, fmt.Errorf("%w: latest revision is %s", ErrRevisionMismatch, revisionID(doc.revision))
+	}
+
+	draft := doc.clone()
+	replies := make([]map[string]interface{}, len(requests))
+
+	for i, req := range requests {
+		if replies[i], err = draft.apply(req); err != nil {
+			return nil, fmt.Errorf("%w: requests[%d]: %w", ErrInvalidRequest, i, err)
+		}
+	}
+
+	draft.revision++
+	s.documents[documentID] = draft
+
+	return &BatchUpdateResponse{
+		DocumentID:   documentID,
+		Replies:      replies,
+		WriteControl: &WriteControl{RequiredRevisionID: revisionID(draft.revision)},
+	}, nil
+}
+
+func (s *MemoryStore) lookup(documentID string) (*memoryDocument, error) {
+	doc, ok := s.documents[documentID]
+	if ok {
+		return doc, nil
+	}
+
+	if !s.autoCreate {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotFound, documentID)
+	}
+
+	doc = newMemoryDocument("Untitled document")
+	s.documents[documentID] = doc
+
+	return doc, nil
+}
+
+func revisionID(revision int) string {
+	return "rev-" + strconv.Itoa(revision)
+}
+
+func newMemoryDocument(title string) *memoryDocument {
+	return &memoryDocument{title: title, revision: 1, body: newSegment(bodyBaseIndex)}
+}
+
+func newSegment(base int) *segment {
+	return &segment{
+		base:   base,
+		units:  []uint16{'\n'},
+		styles: []TextStyle{{}},
+		paras:  []paragraphInfo{{style: ParagraphStyle{NamedStyleType: StyleNormalText}}},
+	}
+}
+
+func (d *memoryDocument) document(documentID string) *Document {
+	doc := &Document{
+		DocumentID: documentID,
+		Title:      d.title,
+		RevisionID: revisionID(d.revision),
+		Body:       &Body{Content: d.body.structuralElements(true)},
+	}
+
+	if d.style != (DocumentStyle{}) {
+		style := d.style
+		doc.DocumentStyle = &style
+	}
+
+	for id, seg := range d.headers {
+		if doc.Headers == nil {
+			doc.Headers = make(map[string]Header)
+		}
+
+		doc.Headers[id] = Header{HeaderID: id, Content: seg.structuralElements(false)}
+	}
+
+	for id, seg := range d.footers {
+		if doc.Footers == nil {
+			doc.Footers = make(map[string]Footer)
+		}
+
+		doc.Footers[id] = Footer{FooterID: id, Content: seg.structuralElements(false)}
+	}
+
+	for id, seg := range d.footnotes {
+		if doc.Footnotes == nil {
+			doc.Footnotes = make(map[string]Footnote)
+		}
+
+		doc.Footnotes[id] = Footnote{FootnoteID: id, Content: seg.structuralElements(false)}
+	}
+
+	return doc
+}
+
+func (d *memoryDocument) clone() *memoryDocument {
+	out := *d
+	out.body = d.body.clone()
+	out.headers = cloneSegments(d.headers)
+	out.footers = cloneSegments(d.footers)
+	out.footnotes = cloneSegments(d.footnotes)
+
+	return &out
+}
+
+func cloneSegments(segments map[string]*segment) map[string]*segment {
+	if segments == nil {
+		return nil
+	}
+
+	out := make(map[string]*segment, len(segments))
+	for id, seg := range segments {
+		out[id] = seg.clone()
+	}
+
+	return out
+}
+
+func (s *segment) clone() *segment {
+	out := &segment{
+		base:   s.base,
+		units:  append([]uint16(nil), s.units...),
+		styles: append([]TextStyle(nil), s.styles...),
+		paras:  append([]paragraphInfo(nil), s.paras...),
+	}
+
+	return out
+}
+
+func (d *memoryDocument) segment(segmentID string) (*segment, error) {
+	if segmentID == "" {
+		return d.body, nil
+	}
+
+	for _, segments := range []map[string]*segment{d.headers, d.footers, d.footnotes} {
+		if seg, ok := segments[segmentID]; ok {
+			return seg, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown segment %q", segmentID)
+}
+
+// apply runs req and returns its reply, nil for requests without one.
+func (d *memoryDocument) apply(req Request) (map[string]interface{}, error) {
+	switch {
+	case req.InsertText != nil:
+		r := req.InsertText
+		if r.Location == nil {
+			return nil, fmt.Errorf("insertText: location is required")
+		}
+
+		seg, err := d.segment(r.Location.SegmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, seg.insertText(r.Location.Index, r.Text)
+	case req.DeleteContentRange != nil:
+		seg, pos, end, err := d.resolveRange("deleteContentRange", req.DeleteContentRange.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, seg.deleteRange(pos, end)
+	case req.UpdateTextStyle != nil:
+		r := req.UpdateTextStyle
+
+		seg, pos, end, err := d.resolveRange("updateTextStyle", r.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, seg.updateTextStyle(pos, end, r.TextStyle, r.Fields)
+	case req.UpdateParagraphStyle != nil:
+		r := req.UpdateParagraphStyle
+
+		seg, pos, end, err := d.resolveRange("updateParagraphStyle", r.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, seg.updateParagraphStyle(pos, end, r.ParagraphStyle, r.Fields)
+	case req.CreateParagraphBullets != nil:
+		seg, pos, end, err := d.resolveRange("createParagraphBullets", req.CreateParagraphBullets.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		d.lists++
+		seg.createBullets(pos, end, "kix.list."+strconv.Itoa(d.lists))
+
+		return nil, nil
+	case req.DeleteParagraphBullets != nil:
+		seg, pos, end, err := d.resolveRange("deleteParagraphBullets", req.DeleteParagraphBullets.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		seg.deleteBullets(pos, end)
+
+		return nil, nil
+	case req.CreateFootnote != nil:
+		return d.createFootnote(req.CreateFootnote.Location)
+	case req.CreateHeader != nil:
+		if d.style.DefaultHeaderID != "" {
+			return nil, fmt.Errorf("createHeader: the document already has a default header")
+		}
+
+		d.style.DefaultHeaderID = d.addSegment(&d.headers, "kix.hdr.")
+
+		return map[string]interface{}{"createHeader": map[string]interface{}{"headerId": d.style.DefaultHeaderID}}, nil
+	case req.CreateFooter != nil:
+		if d.style.DefaultFooterID != "" {
+			return nil, fmt.Errorf("createFooter: the document already has a default footer")
+		}
+
+		d.style.DefaultFooterID = d.addSegment(&d.footers, "kix.ftr.")
+
+		return map[string]interface{}{"createFooter": map[string]interface{}{"footerId": d.style.DefaultFooterID}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported request")
+	}
+}
+
+// createFootnote inserts a reference at location, kept as a Placeholder
+// like other non-text elements, and adds a footnote holding " \n".
+func (d *memoryDocument) createFootnote(location *Location) (map[string]interface{}, error) {
+	if location == nil {
+		return nil, fmt.Errorf("createFootnote: location is required")
+	}
+
+	if location.SegmentID != "" {
+		return nil, fmt.Errorf("createFootnote: footnotes can only be inserted in the body")
+	}
+
+	if err := d.body.insertText(location.Index, string(Placeholder)); err != nil {
+		return nil, err
+	}
+
+	id := d.addSegment(&d.footnotes, "kix.fn.")
+	if err := d.footnotes[id].insertText(0, " "); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"createFootnote": map[string]interface{}{"footnoteId": id}}, nil
+}
+
+// addSegment adds an empty segment with a new ID starting with prefix
+// to segments and returns the ID.
+func (d *memoryDocument) addSegment(segments *map[string]*segment, prefix string) string {
+	if *segments == nil {
+		*segments = make(map[string]*segment)
+	}
+
+	// Loaded documents may already use IDs of this form
+	var id string
+	for {
+		d.created++
+		id = prefix + strconv.Itoa(d.created)
+
+		if _, err := d.segment(id); err != nil {
+			break
+		}
+	}
+
+	(*segments)[id] = newSegment(0)
+
+	return id
+}
+
+// resolveRange validates r and converts it to unit offsets within its
+// segment. Ranges may not be empty and may not extend past the final
+// newline of the segment.
+func (d *memoryDocument) resolveRange(kind string, r *Range) (*segment, int, int, error) {
+	if r == nil {
+		return nil, 0, 0, fmt.Errorf("%s: range is required", kind)
+	}
+
+	seg, err := d.segment(r.SegmentID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	pos, end := r.StartIndex-seg.base, r.EndIndex-seg.base
+	if pos < 0 || end <= pos || end > len(seg.units) {
+		return nil, 0, 0, fmt.Errorf("%s: range [%d, %d) is outside the segment [%d, %d)",
+			kind, r.StartIndex, r.EndIndex, seg.base, seg.base+len(seg.units))
+	}
+
+	return seg, pos, end, nil
+}
+
+func (s *segment) insertText(index int, text string) error {
+	pos := index - s.base
+	if pos < 0 || pos >= len(s.units) {
+		return fmt.Errorf("insertText: index %d must be inside the segment [%d, %d)",
+			index, s.base, s.base+len(s.units)-1)
+	}
+
+	inserted := utf16.Encode([]rune(text))
+	if len(inserted) == 0 {
+		return fmt.Errorf("insertText: text is empty")
+	}
+
+	// Like the Docs editor, inserted text continues the style of the
+	// character before it, unless it starts a paragraph.
+	var style TextStyle
+
+	switch {
+	case pos > 0 && s.units[pos-1] != '\n':
+		style = s.styles[pos-1]
+	case s.units[pos] != '\n':
+		style = s.styles[pos]
+	}
+
+	para := s.paras[s.paragraphEnd(pos)]
+
+	styles := make([]TextStyle, len(inserted))
+	paras := make([]paragraphInfo, len(inserted))
+
+	for i, unit := range inserted {
+		styles[i] = style
+		if unit == '\n' {
+			paras[i] = para
+		}
+	}
+
+	s.units = splice(s.units, pos, pos, inserted)
+	s.styles = splice(s.styles, pos, pos, styles)
+	s.paras = splice(s.paras, pos, pos, paras)
+
+	return nil
+}
+
+func (s *segment) deleteRange(pos, end int) error {
+	if end >= len(s.units) {
+		return fmt.Errorf("deleteContentRange: the range cannot include the newline at the end of the segment")
+	}
+
+	// Joining paragraphs keeps the style of the first one, which is what
+	// the Docs editor does when a paragraph break is deleted.
+	first := s.paras[s.paragraphEnd(pos)]
+	joined := false
+
+	for i := pos; i < end; i++ {
+		if s.units[i] == '\n' {
+			joined = true
+		}
+	}
+
+	s.units = splice(s.units, pos, end, nil)
+	s.styles = splice(s.styles, pos, end, nil)
+	s.paras = splice(s.paras, pos, end, nil)
+
+	if joined {
+		s.paras[s.paragraphEnd(pos)] = first
+	}
+
+	return nil
+}
+
+func (s *segment) updateTextStyle(pos, end int, style *TextStyle, fields string) error {
+	if style == nil {
+		return fmt.Errorf("updateTextStyle: textStyle is required")
+	}
+
+	for i := pos; i < end; i++ {
+		if err := mergeTextStyle(&s.styles[i], style, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *segment) updateParagraphStyle(pos, end int, style *ParagraphStyle, fields string) error {
+	if style == nil {
+		return fmt.Errorf("updateParagraphStyle: paragraphStyle is required")
+	}
+
+	for _, nl := range s.paragraphEnds(pos, end) {
+		if err := mergeParagraphStyle(&s.paras[nl].style, style, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createBullets mirrors the API: leading tabs of each paragraph set its
+// nesting level and are removed. Paragraphs are processed back to front
+// so removing tabs does not move the ones still to be visited.
+func (s *segment) createBullets(pos, end int, listID string) {
+	ends := s.paragraphEnds(pos, end)
+
+	for i := len(ends) - 1; i >= 0; i-- {
+		start := s.paragraphStart(ends[i])
+
+		tabs := 0
+		for start+tabs < ends[i] && s.units[start+tabs] == '\t' {
+			tabs++
+		}
+
+		nl := ends[i]
+		if tabs > 0 {
+			s.units = splice(s.units, start, start+tabs, nil)
+			s.styles = splice(s.styles, start, start+tabs, nil)
+			s.paras = splice(s.paras, start, start+tabs, nil)
+			nl -= tabs
+		}
+
+		s.paras[nl].bullet = &Bullet{ListID: listID, NestingLevel: tabs}
+	}
+}
+
+func (s *segment) deleteBullets(pos, end int) {
+	for _, nl := range s.paragraphEnds(pos, end) {
+		s.paras[nl].bullet = nil
+	}
+}
+
+// paragraphEnd returns the offset of the newline terminating the
+// paragraph that contains pos.
+func (s *segment) paragraphEnd(pos int) int {
+	for i := pos; i < len(s.units); i++ {
+		if s.units[i] == '\n' {
+			return i
+		}
+	}
+
+	return len(s.units) - 1
+}
+
+// paragraphStart returns the offset of the first unit of the paragraph
+// terminated by the newline at nl.
+func (s *segment) paragraphStart(nl int) int {
+	for i := nl - 1; i >= 0; i-- {
+		if s.units[i] == '\n' {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// paragraphEnds returns the newline offsets of every paragraph
+// overlapping [pos, end).
+func (s *segment) paragraphEnds(pos, end int) []int {
+	var ends []int
+
+	for i := pos; i < len(s.units); i++ {
+		if s.units[i] != '\n' {
+			continue
+		}
+
+		ends = append(ends, i)
+
+		if i >= end-1 {
+			break
+		}
+	}
+
+	return ends
+}
+
+// structuralElements folds the flat model back into API elements.
+// Bodies start with a section break occupying index 0.
+func (s *segment) structuralElements(sectionBreak bool) []StructuralElement {
+	var out []StructuralElement
+
+	if sectionBreak {
+		out = append(out, StructuralElement{EndIndex: s.base, SectionBreak: &SectionBreak{}})
+	}
+
+	start := 0
+
+	for i, unit := range s.units {
+		if unit != '\n' {
+			continue
+		}
+
+		info := s.paras[i]
+		style := info.style
+
+		para := &Paragraph{ParagraphStyle: &style}
+		if info.bullet != nil {
+			bullet := *info.bullet
+			para.Bullet = &bullet
+		}
+
+		runStart := start
+		for j := start; j <= i; j++ {
+			if j < i && reflect.DeepEqual(s.styles[j+1], s.styles[runStart]) {
+				continue
+			}
+
+			runStyle := s.styles[runStart]
+			para.Elements = append(para.Elements, ParagraphElement{
+				StartIndex: s.base + runStart,
+				EndIndex:   s.base + j + 1,
+				TextRun: &TextRun{
+					Content:   string(utf16.Decode(s.units[runStart : j+1])),
+					TextStyle: &runStyle,
+				},
+			})
+			runStart = j + 1
+		}
+
+		out = append(out, StructuralElement{
+			StartIndex: s.base + start,
+			EndIndex:   s.base + i + 1,
+			Paragraph:  para,
+		})
+		start = i + 1
+	}
+
+	return out
+}
+
+func splice[T any](items []T, start, end int, insert []T) []T {
+	out := make([]T, 0, len(items)-(end-start)+len(insert))
+	out = append(out, items[:start]...)
+	out = append(out, insert...)
+
+	return append(out, items[end:]...)
+}
+
+// parseFields splits a field mask. "*" selects every field.
+func parseFields(fields string) (map[string]bool, bool) {
+	set := make(map[string]bool)
+
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "*" {
+			return nil, true
+		}
+
+		if field != "" {
+			set[field] = true
+		}
+	}
+
+	return set, false
+}
+
+func mergeTextStyle(dst, src *TextStyle, fields string) error {
+	set, all := parseFields(fields)
+	if !all && len(set) == 0 {
+		return fmt.Errorf("updateTextStyle: fields is required")
+	}
+
+	apply := map[string]func(){
+		"bold":               func() { dst.Bold = src.Bold },
+		"italic":             func() { dst.Italic = src.Italic },
+		"underline":          func() { dst.Underline = src.Underline },
+		"strikethrough":      func() { dst.Strikethrough = src.Strikethrough },
+		"fontSize":           func() { dst.FontSize = src.FontSize },
+		"weightedFontFamily": func() { dst.WeightedFontFamily = src.WeightedFontFamily },
+		"foregroundColor":    func() { dst.ForegroundColor = src.ForegroundColor },
+		"link":               func() { dst.Link = src.Link },
+	}
+
+	return applyFields("updateTextStyle", apply, set, all)
+}
+
+func mergeParagraphStyle(dst, src *ParagraphStyle, fields string) error {
+	set, all := parseFields(fields)
+	if !all && len(set) == 0 {
+		return fmt.Errorf("updateParagraphStyle: fields is required")
+	}
+
+	apply := map[string]func(){
+		"namedStyleType": func() { dst.NamedStyleType = src.NamedStyleType },
+		"alignment":      func() { dst.Alignment = src.Alignment },
+		"indentStart":    func() { dst.IndentStart = src.IndentStart },
+	}
+
+	return applyFields("updateParagraphStyle", apply, set, all)
+}
+
+func applyFields(kind string, apply map[string]func(), set map[string]bool, all bool) error {
+	if all {
+		for _, fn := range apply {
+			fn()
+		}
+
+		return nil
+	}
+
+	for field := range set {
+		fn, ok := apply[field]
+		if !ok {
+			return fmt.Errorf("%s: unsupported field %q", kind, field)
+		}
+
+		fn()
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package docs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+func insert(index int, text string) docs.Request {
+	return docs.Request{InsertText: &docs.InsertTextRequest{Text: text, Location: &docs.Location{Index: index}}}
+}
+
+func deleteRange(start, end int) docs.Request {
+	return docs.Request{DeleteContentRange: &docs.DeleteContentRangeRequest{
+		Range: &docs.Range{StartIndex: start, EndIndex: end},
+	}}
+}
+
+func TestORPHAN_MemoryStore_NewDocument_HasSectionBreakAndEmptyParagraph(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+
+	// Act
+	doc, err := store.Get(context.Background(), "new-doc")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, doc.Body.Content, 2)
+	assert.NotNil(t, doc.Body.Content[0].SectionBreak)
+	assert.Equal(t, 1, doc.Body.Content[1].StartIndex)
+	assert.Equal(t, 2, doc.EndIndex())
+	assert.Equal(t, "\n", doc.Text())
+}
+
+func TestORPHAN_MemoryStore_UnknownDocument_ReturnsNotFound(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(false)
+
+	// Act
+	_, err := store.Get(context.Background(), "missing")
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrDocumentNotFound)
+}
+
+func TestORPHAN_MemoryStore_Indexes_CountUTF16CodeUnits(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+
+	// Act
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "a😀b\nc")}, nil)
+	require.NoError(t, err)
+	doc, _ := store.Get(context.Background(), "d")
+
+	// Assert
+	require.Len(t, doc.Body.Content, 3)
+	assert.Equal(t, 1, doc.Body.Content[1].StartIndex)
+	assert.Equal(t, 6, doc.Body.Content[1].EndIndex)
+	assert.Equal(t, 6, doc.Body.Content[2].StartIndex)
+	assert.Equal(t, 8, doc.EndIndex())
+}
+
+func TestORPHAN_MemoryStore_FailingRequest_RollsBackWholeBatch(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "keep")}, nil)
+	require.NoError(t, err)
+	before, _ := store.Get(context.Background(), "d")
+
+	// Act
+	_, err = store.BatchUpdate(context.Background(), "d", []docs.Request{
+		insert(1, "lost "),
+		deleteRange(1, 11),
+	}, nil)
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrInvalidRequest)
+
+	after, _ := store.Get(context.Background(), "d")
+	assert.Equal(t, before, after)
+}
+
+func TestORPHAN_MemoryStore_DeleteAcrossParagraphs_KeepsFirstParagraphStyle(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{
+		insert(1, "Title\nbody"),
+		{UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Range:          &docs.Range{StartIndex: 1, EndIndex: 2},
+			ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: docs.StyleHeading1},
+			Fields:         "namedStyleType",
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	// Act
+	_, err = store.BatchUpdate(context.Background(), "d", []docs.Request{deleteRange(3, 8)}, nil)
+	require.NoError(t, err)
+	doc, _ := store.Get(context.Background(), "d")
+
+	// Assert
+	assert.Equal(t, "Tiody\n", doc.Text())
+	assert.Equal(t, docs.StyleHeading1, doc.Body.Content[1].Paragraph.ParagraphStyle.NamedStyleType)
+}
+
+func TestORPHAN_MemoryStore_CreateBullets_ConsumesLeadingTabsAsNesting(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "top\n\tnested")}, nil)
+	require.NoError(t, err)
+
+	// Act
+	_, err = store.BatchUpdate(context.Background(), "d", []docs.Request{
+		{CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			Range:        &docs.Range{StartIndex: 1, EndIndex: 11},
+			BulletPreset: docs.BulletPresetDisc,
+		}},
+	}, nil)
+	require.NoError(t, err)
+	doc, _ := store.Get(context.Background(), "d")
+
+	// Assert
+	assert.Equal(t, "top\nnested\n", doc.Text())
+	assert.Equal(t, 0, doc.Body.Content[1].Paragraph.Bullet.NestingLevel)
+	assert.Equal(t, 1, doc.Body.Content[2].Paragraph.Bullet.NestingLevel)
+}
+
+func TestORPHAN_MemoryStore_BatchUpdate_BumpsRevision(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+	before, _ := store.Get(context.Background(), "d")
+
+	// Act
+	resp, err := store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "x")}, nil)
+	require.NoError(t, err)
+	after, _ := store.Get(context.Background(), "d")
+
+	// Assert
+	assert.NotEqual(t, before.RevisionID, after.RevisionID)
+	assert.Equal(t, after.RevisionID, resp.WriteControl.RequiredRevisionID)
+}
+
+func TestORPHAN_MemoryStore_StaleWriteControl_RejectsBatch(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(true)
+	before, _ := store.Get(context.Background(), "d")
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "x")}, nil)
+	require.NoError(t, err)
+
+	// Act
+	_, err = store.BatchUpdate(context.Background(), "d", []docs.Request{insert(1, "y")},
+		&docs.WriteControl{RequiredRevisionID: before.RevisionID})
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrRevisionMismatch)
+
+	after, _ := store.Get(context.Background(), "d")
+	assert.Equal(t, "x\n", after.Text())
+}
@@ -0,0 +1,137 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Bounds on the length of a Google document ID. Docs IDs are usually 44
+// characters, but Drive has issued shorter IDs over the years.
+const (
+	MinDocumentIDLength = 25
+	MaxDocumentIDLength = 100
+)
+
+// ErrInvalidReference signals a document reference that is neither a
+// document ID nor a Google Docs or Drive URL.
+var ErrInvalidReference = errors.New("invalid document reference")
+
+// documentIDPattern is the character set of Google document IDs.
+var documentIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Reference is a parsed document reference. TabID and HeadingID are
+// only set when a URL points into a tab or at a heading.
+type Reference struct {
+	DocumentID string `json:"documentId"`
+	TabID      string `json:"tabId,omitempty"`
+	HeadingID  string `json:"headingId,omitempty"`
+}
+
+// ReferenceParser turns what a user pastes into a Reference. IDs must
+// look like Google document IDs; TestIDs admits others, such as the
+// fixture IDs of a test environment, and should only be set there.
+type ReferenceParser struct {
+	TestIDs *regexp.Regexp
+}
+
+// Parse accepts a raw document ID or a URL of the forms
+// docs.google.com/document/d/{id}/edit?tab={tab}#heading={heading},
+// drive.google.com/file/d/{id}/view and drive.google.com/open?id={id}.
+func (p *ReferenceParser) Parse(raw string) (Reference, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Reference{}, fmt.Errorf("%w: documentId is required", ErrInvalidReference)
+	}
+
+	ref := Reference{DocumentID: raw}
+
+	if strings.Contains(raw, "/") || strings.Contains(raw, "?") {
+		var err error
+		if ref, err = parseURL(raw); err != nil {
+			return Reference{}, err
+		}
+	}
+
+	if err := p.validateID(ref.DocumentID); err != nil {
+		return Reference{}, err
+	}
+
+	return ref, nil
+}
+
+func (p *ReferenceParser) validateID(id string) error {
+	if !documentIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: documentId contains invalid characters - only alphanumeric, hyphens, and underscores allowed",
+			ErrInvalidReference)
+	}
+
+	if p.TestIDs != nil && p.TestIDs.MatchString(id) {
+		return nil
+	}
+
+	if len(id) < MinDocumentIDLength || len(id) > MaxDocumentIDLength {
+		return fmt.Errorf("%w: documentId format invalid - expected a Google Docs ID of %d to %d characters",
+			ErrInvalidReference, MinDocumentIDLength, MaxDocumentIDLength)
+	}
+
+	return nil
+}
+
+// parseURL extracts the reference from a Docs or Drive URL. The scheme
+// may be left out, as it often is when links are copied as text.
+func parseURL(raw string) (Reference, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Reference{}, fmt.Errorf("%w: %w", ErrInvalidReference, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "docs.google.com" && host != "drive.google.com" {
+		return Reference{}, fmt.Errorf("%w: %s is not a Google Docs or Drive URL", ErrInvalidReference, u.Hostname())
+	}
+
+	ref := Reference{
+		DocumentID: pathID(u.Path),
+		TabID:      u.Query().Get("tab"),
+	}
+
+	if ref.DocumentID == "" {
+		ref.DocumentID = u.Query().Get("id")
+	}
+
+	if ref.DocumentID == "" {
+		return Reference{}, fmt.Errorf("%w: no document ID in URL", ErrInvalidReference)
+	}
+
+	if fragment, err := url.ParseQuery(u.Fragment); err == nil {
+		ref.HeadingID = fragment.Get("heading")
+		if ref.TabID == "" {
+			ref.TabID = fragment.Get("tab")
+		}
+	}
+
+	return ref, nil
+}
+
+// pathID returns the segment following "d" in paths such as
+// /document/d/{id}/edit, /document/u/0/d/{id} and /file/d/{id}/view.
+// Published documents (/d/e/{id}) carry a publishing key, not a
+// document ID, and are not matched.
+func pathID(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "d" && parts[i+1] != "e" {
+			return parts[i+1]
+		}
+	}
+
+	return ""
+}
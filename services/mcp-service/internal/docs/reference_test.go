@@ -0,0 +1,117 @@
+package docs_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+const realID = "1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms"
+
+func TestORPHAN_ReferenceParser_RawID_IsAccepted(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	ref, err := parser.Parse("  " + realID + " ")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, docs.Reference{DocumentID: realID}, ref)
+}
+
+func TestORPHAN_ReferenceParser_ShorterDriveID_IsAccepted(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	ref, err := parser.Parse("0B4fk8L6brI_eX1U5Ui1Lb1FpVG8")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "0B4fk8L6brI_eX1U5Ui1Lb1FpVG8", ref.DocumentID)
+}
+
+func TestORPHAN_ReferenceParser_DocsURL_ExtractsTabAndHeading(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	ref, err := parser.Parse("https://docs.google.com/document/d/" + realID + "/edit?tab=t.abc123#heading=h.4f2k9")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, docs.Reference{DocumentID: realID, TabID: "t.abc123", HeadingID: "h.4f2k9"}, ref)
+}
+
+func TestORPHAN_ReferenceParser_AccountScopedURLWithoutScheme_IsAccepted(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	ref, err := parser.Parse("docs.google.com/document/u/1/d/" + realID + "/edit")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, realID, ref.DocumentID)
+}
+
+func TestORPHAN_ReferenceParser_DriveURLs_AreAccepted(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	file, fileErr := parser.Parse("https://drive.google.com/file/d/" + realID + "/view?usp=sharing")
+	open, openErr := parser.Parse("https://drive.google.com/open?id=" + realID)
+
+	// Assert
+	require.NoError(t, fileErr)
+	require.NoError(t, openErr)
+	assert.Equal(t, realID, file.DocumentID)
+	assert.Equal(t, realID, open.DocumentID)
+}
+
+func TestORPHAN_ReferenceParser_ForeignOrPublishedURL_IsRejected(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{}
+
+	// Act
+	_, foreignErr := parser.Parse("https://example.com/document/d/" + realID + "/edit")
+	_, publishedErr := parser.Parse("https://docs.google.com/document/d/e/2PACX-1vQ" + realID + "/pub")
+
+	// Assert
+	require.ErrorIs(t, foreignErr, docs.ErrInvalidReference)
+	require.ErrorIs(t, publishedErr, docs.ErrInvalidReference)
+}
+
+func TestORPHAN_ReferenceParser_ShortID_RequiresTestPattern(t *testing.T) {
+	// Arrange
+	strict := &docs.ReferenceParser{}
+	permissive := &docs.ReferenceParser{TestIDs: regexp.MustCompile(`^test-`)}
+
+	// Act
+	_, strictErr := strict.Parse("test-doc-123")
+	ref, testErr := permissive.Parse("test-doc-123")
+	_, otherErr := permissive.Parse("invalid-id-format")
+
+	// Assert
+	require.ErrorIs(t, strictErr, docs.ErrInvalidReference)
+	require.NoError(t, testErr)
+	assert.Equal(t, "test-doc-123", ref.DocumentID)
+	require.ErrorIs(t, otherErr, docs.ErrInvalidReference)
+}
+
+func TestORPHAN_ReferenceParser_InvalidCharacters_AreRejected(t *testing.T) {
+	// Arrange
+	parser := &docs.ReferenceParser{TestIDs: regexp.MustCompile(`.*`)}
+
+	// Act
+	_, err := parser.Parse("doc id with spaces")
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrInvalidReference)
+}
@@ -0,0 +1,158 @@
+package docs
+
+// Request is one entry of a documents.batchUpdate call. Exactly one of
+// the request pointers is set.
+type Request struct {
+	InsertText             *InsertTextRequest             `json:"insertText,omitempty"`
+	DeleteContentRange     *DeleteContentRangeRequest     `json:"deleteContentRange,omitempty"`
+	UpdateTextStyle        *UpdateTextStyleRequest        `json:"updateTextStyle,omitempty"`
+	UpdateParagraphStyle   *UpdateParagraphStyleRequest   `json:"updateParagraphStyle,omitempty"`
+	CreateParagraphBullets *CreateParagraphBulletsRequest `json:"createParagraphBullets,omitempty"`
+	DeleteParagraphBullets *DeleteParagraphBulletsRequest `json:"deleteParagraphBullets,omitempty"`
+	CreateFootnote         *CreateFootnoteRequest         `json:"createFootnote,omitempty"`
+	CreateHeader           *CreateHeaderRequest           `json:"createHeader,omitempty"`
+	CreateFooter           *CreateFooterRequest           `json:"createFooter,omitempty"`
+}
+
+// Location is a position inside a segment. An empty SegmentID is the body.
+type Location struct {
+	SegmentID string `json:"segmentId,omitempty"`
+	Index     int    `json:"index"`
+}
+
+// Range is a half-open [StartIndex, EndIndex) span inside a segment.
+type Range struct {
+	SegmentID  string `json:"segmentId,omitempty"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+}
+
+// InsertTextRequest inserts Text at Location.
+type InsertTextRequest struct {
+	Text     string    `json:"text"`
+	Location *Location `json:"location"`
+}
+
+// DeleteContentRangeRequest deletes Range.
+type DeleteContentRangeRequest struct {
+	Range *Range `json:"range"`
+}
+
+// UpdateTextStyleRequest applies the TextStyle fields listed in Fields
+// (comma separated, "*" for all) to Range.
+type UpdateTextStyleRequest struct {
+	Range     *Range     `json:"range"`
+	TextStyle *TextStyle `json:"textStyle"`
+	Fields    string     `json:"fields"`
+}
+
+// UpdateParagraphStyleRequest applies the ParagraphStyle fields listed
+// in Fields to every paragraph overlapping Range.
+type UpdateParagraphStyleRequest struct {
+	Range          *Range          `json:"range"`
+	ParagraphStyle *ParagraphStyle `json:"paragraphStyle"`
+	Fields         string          `json:"fields"`
+}
+
+// Bullet presets used by the service.
+const (
+	BulletPresetDisc    = "BULLET_DISC_CIRCLE_SQUARE"
+	BulletPresetNumbers = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+)
+
+// CreateParagraphBulletsRequest turns every paragraph overlapping Range
+// into a list item. Leading tabs set the nesting level and are removed.
+type CreateParagraphBulletsRequest struct {
+	Range        *Range `json:"range"`
+	BulletPreset string `json:"bulletPreset"`
+}
+
+// DeleteParagraphBulletsRequest removes bullets from every paragraph
+// overlapping Range.
+type DeleteParagraphBulletsRequest struct {
+	Range *Range `json:"range"`
+}
+
+// CreateFootnoteRequest inserts a footnote reference at Location, which
+// must be in the body, and creates the footnote it refers to. The new
+// footnote holds a space followed by a newline.
+type CreateFootnoteRequest struct {
+	Location *Location `json:"location"`
+}
+
+// HeaderFooterDefault is the type of the header or footer shown on
+// most pages.
+const HeaderFooterDefault = "DEFAULT"
+
+// CreateHeaderRequest creates a header of Type, which must not exist yet.
+type CreateHeaderRequest struct {
+	Type string `json:"type"`
+}
+
+// CreateFooterRequest creates a footer of Type, which must not exist yet.
+type CreateFooterRequest struct {
+	Type string `json:"type"`
+}
+
+// InSegment points every range and location of requests at segmentID.
+func InSegment(requests []Request, segmentID string) {
+	for _, req := range requests {
+		switch {
+		case req.InsertText != nil && req.InsertText.Location != nil:
+			req.InsertText.Location.SegmentID = segmentID
+		case req.DeleteContentRange != nil && req.DeleteContentRange.Range != nil:
+			req.DeleteContentRange.Range.SegmentID = segmentID
+		case req.UpdateTextStyle != nil && req.UpdateTextStyle.Range != nil:
+			req.UpdateTextStyle.Range.SegmentID = segmentID
+		case req.UpdateParagraphStyle != nil && req.UpdateParagraphStyle.Range != nil:
+			req.UpdateParagraphStyle.Range.SegmentID = segmentID
+		case req.CreateParagraphBullets != nil && req.CreateParagraphBullets.Range != nil:
+			req.CreateParagraphBullets.Range.SegmentID = segmentID
+		case req.DeleteParagraphBullets != nil && req.DeleteParagraphBullets.Range != nil:
+			req.DeleteParagraphBullets.Range.SegmentID = segmentID
+		}
+	}
+}
+
+// WriteControl guards a batchUpdate against concurrent edits.
+type WriteControl struct {
+	RequiredRevisionID string `json:"requiredRevisionId,omitempty"`
+	TargetRevisionID   string `json:"targetRevisionId,omitempty"`
+}
+
+// BatchUpdateRequest is the documents.batchUpdate request body.
+type BatchUpdateRequest struct {
+	Requests     []Request     `json:"requests"`
+	WriteControl *WriteControl `json:"writeControl,omitempty"`
+}
+
+// BatchUpdateResponse is the documents.batchUpdate response body.
+// Replies holds one entry per request, empty for requests without a
+// reply.
+type BatchUpdateResponse struct {
+	DocumentID   string                   `json:"documentId"`
+	Replies      []map[string]interface{} `json:"replies,omitempty"`
+	WriteControl *WriteControl            `json:"writeControl,omitempty"`
+}
+
+// CreatedIDs returns the IDs the replies of kind report in request
+// order: "createFootnote" replies carry a footnoteId, "createHeader" a
+// headerId and "createFooter" a footerId.
+func (r *BatchUpdateResponse) CreatedIDs(kind string) []string {
+	field := map[string]string{
+		"createFootnote": "footnoteId",
+		"createHeader":   "headerId",
+		"createFooter":   "footerId",
+	}[kind]
+
+	var ids []string
+
+	for _, reply := range r.Replies {
+		if created, ok := reply[kind].(map[string]interface{}); ok {
+			id, _ := created[field].(string)
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
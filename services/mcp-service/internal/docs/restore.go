@@ -0,0 +1,209 @@
+package docs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf16"
+)
+
+// Field masks covering every style property the restore writes back.
+const (
+	restoreTextFields      = "bold,italic,underline,strikethrough,fontSize,weightedFontFamily,foregroundColor,link"
+	restoreParagraphFields = "namedStyleType,alignment,indentStart"
+)
+
+// RestoreRequests returns the requests that turn current's body back
+// into target's. Only the range between the longest common prefix and
+// suffix is rewritten, so content the two share, including tables and
+// images, is left untouched. The rewritten range itself must consist of
+// text: ErrNotRestorable is returned when it holds other elements.
+// Restored lists are recreated as bulleted lists at their original
+// nesting; their glyphs are not preserved.
+func RestoreRequests(current, target *Document) ([]Request, error) {
+	return RestoreSegmentRequests(current, target, "")
+}
+
+// RestoreSegmentRequests is RestoreRequests for segmentID, a header,
+// footer or footnote present in both documents, or the body when empty.
+func RestoreSegmentRequests(current, target *Document, segmentID string) ([]Request, error) {
+	cur, err := loadDocument(current).segment(segmentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRestorable, err)
+	}
+
+	tgt, err := loadDocument(target).segment(segmentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRestorable, err)
+	}
+
+	n, m := len(cur.units), len(tgt.units)
+
+	p := 0
+	for p < n-1 && p < m-1 && sameUnit(cur, tgt, p, p) {
+		p++
+	}
+
+	if p == n-1 && n == m && sameUnit(cur, tgt, p, p) {
+		return nil, nil
+	}
+
+	// The final newline can never be deleted, so the suffix always keeps it.
+	q := 1
+	for p+q < n && p+q < m && sameUnit(cur, tgt, n-1-q, m-1-q) {
+		q++
+	}
+
+	// Whole paragraphs are rewritten, so that each of them can be given
+	// back its style, bullet and nesting. The common prefix is identical
+	// in both documents and so are its paragraph starts.
+	p = tgt.paragraphStart(tgt.paragraphEnd(p))
+	deleteEnd, insertEnd := n-q, m-q
+
+	for deleteEnd < n-1 && !(atParagraphStart(cur, deleteEnd) && atParagraphStart(tgt, insertEnd)) {
+		deleteEnd++
+		insertEnd++
+	}
+
+	if strings.ContainsRune(string(utf16.Decode(cur.units[p:deleteEnd])), Placeholder) {
+		return nil, fmt.Errorf("%w: the changed range contains tables or other non-text elements", ErrNotRestorable)
+	}
+
+	if strings.ContainsRune(string(utf16.Decode(tgt.units[p:insertEnd])), Placeholder) {
+		return nil, fmt.Errorf("%w: the range to restore contains tables or other non-text elements", ErrNotRestorable)
+	}
+
+	// Nested list items are inserted with leading tabs, which
+	// createParagraphBullets turns into their nesting level. tabs[i]
+	// counts the tabs inserted in front of target unit i; the paragraph
+	// right after the range gets its tabs at the end of the inserted text.
+	tabs := make([]int, m)
+	var text []uint16
+
+	for i := p; i <= insertEnd; i++ {
+		if atParagraphStart(tgt, i) {
+			if bullet := tgt.paras[tgt.paragraphEnd(i)].bullet; bullet != nil {
+				tabs[i] = bullet.NestingLevel
+			}
+		}
+
+		for t := 0; t < tabs[i]; t++ {
+			text = append(text, '\t')
+		}
+
+		if i < insertEnd {
+			text = append(text, tgt.units[i])
+		}
+	}
+
+	// pos maps a target unit to its index once the text is inserted.
+	shift := make([]int, m)
+	total := 0
+
+	for i := range tgt.units {
+		total += tabs[i]
+		shift[i] = total
+	}
+
+	pos := func(i int) int { return tgt.base + i + shift[i] }
+
+	var requests []Request
+
+	if deleteEnd > p {
+		requests = append(requests, Request{DeleteContentRange: &DeleteContentRangeRequest{
+			Range: &Range{StartIndex: cur.base + p, EndIndex: cur.base + deleteEnd},
+		}})
+	}
+
+	if len(text) > 0 {
+		requests = append(requests, Request{InsertText: &InsertTextRequest{
+			Text:     string(utf16.Decode(text)),
+			Location: &Location{Index: tgt.base + p},
+		}})
+	}
+
+	for start := p; start < insertEnd; {
+		end := start + 1
+		for end < insertEnd && reflect.DeepEqual(tgt.styles[end], tgt.styles[start]) {
+			end++
+		}
+
+		style := tgt.styles[start]
+		requests = append(requests, Request{UpdateTextStyle: &UpdateTextStyleRequest{
+			Range:     &Range{StartIndex: pos(start), EndIndex: pos(end-1) + 1},
+			TextStyle: &style,
+			Fields:    restoreTextFields,
+		}})
+		start = end
+	}
+
+	// Every paragraph in the rewritten range gets its style and bullet
+	// back, and so does the one after it, which deleting paragraph
+	// breaks may have restyled.
+	var ends []int
+
+	for i := p; i < m; i++ {
+		if tgt.units[i] == '\n' {
+			ends = append(ends, i)
+			if i >= insertEnd {
+				break
+			}
+		}
+	}
+
+	for _, nl := range ends {
+		style := tgt.paras[nl].style
+		requests = append(requests, Request{UpdateParagraphStyle: &UpdateParagraphStyleRequest{
+			Range:          &Range{StartIndex: pos(nl), EndIndex: pos(nl) + 1},
+			ParagraphStyle: &style,
+			Fields:         restoreParagraphFields,
+		}})
+	}
+
+	requests = append(requests, Request{DeleteParagraphBullets: &DeleteParagraphBulletsRequest{
+		Range: &Range{StartIndex: tgt.base + p, EndIndex: pos(ends[len(ends)-1]) + 1},
+	}})
+
+	// Bullets are created back to front: each request removes the tabs
+	// of its own paragraph, which would move the paragraphs after it.
+	for i := len(ends) - 1; i >= 0; i-- {
+		nl := ends[i]
+		if tgt.paras[nl].bullet == nil {
+			continue
+		}
+
+		start := tgt.paragraphStart(nl)
+		requests = append(requests, Request{CreateParagraphBullets: &CreateParagraphBulletsRequest{
+			Range:        &Range{StartIndex: pos(start) - tabs[start], EndIndex: pos(nl) + 1},
+			BulletPreset: BulletPresetDisc,
+		}})
+	}
+
+	InSegment(requests, segmentID)
+
+	return requests, nil
+}
+
+// atParagraphStart reports whether unit i of s begins a paragraph.
+func atParagraphStart(s *segment, i int) bool {
+	return i == 0 || s.units[i-1] == '\n'
+}
+
+// sameUnit reports whether unit i of a and unit j of b are identical,
+// including their text style and, for newlines, their paragraph.
+func sameUnit(a, b *segment, i, j int) bool {
+	if a.units[i] != b.units[j] || !reflect.DeepEqual(a.styles[i], b.styles[j]) {
+		return false
+	}
+
+	if a.units[i] != '\n' {
+		return true
+	}
+
+	pa, pb := a.paras[i], b.paras[j]
+	if !reflect.DeepEqual(pa.style, pb.style) || (pa.bullet == nil) != (pb.bullet == nil) {
+		return false
+	}
+
+	return pa.bullet == nil || pa.bullet.NestingLevel == pb.bullet.NestingLevel
+}
@@ -0,0 +1,133 @@
+package docs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// outline flattens a document into comparable paragraph descriptions,
+// ignoring list IDs, which a restore cannot preserve.
+func outline(doc *docs.Document) []string {
+	var out []string
+
+	for _, el := range doc.Body.Content {
+		if el.Paragraph == nil {
+			continue
+		}
+
+		line := el.Paragraph.ParagraphStyle.NamedStyleType + ":"
+		if el.Paragraph.Bullet != nil {
+			line += string(rune('0'+el.Paragraph.Bullet.NestingLevel)) + ":"
+		}
+
+		for _, pe := range el.Paragraph.Elements {
+			if pe.TextRun.TextStyle.Bold {
+				line += "*" + pe.TextRun.Content + "*"
+			} else {
+				line += pe.TextRun.Content
+			}
+		}
+
+		out = append(out, line)
+	}
+
+	return out
+}
+
+// seedStructured creates "d" holding a heading, a paragraph with a bold
+// word and a two-level list.
+func seedStructured(t *testing.T) *docs.MemoryStore {
+	t.Helper()
+
+	store := docs.NewMemoryStore(false)
+	store.Create("d", "Test")
+
+	_, err := store.BatchUpdate(context.Background(), "d", []docs.Request{
+		insert(1, "Title\nsome bold text\none\n\ttwo\nend"),
+		{UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Range:          &docs.Range{StartIndex: 1, EndIndex: 2},
+			ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: docs.StyleHeading1},
+			Fields:         "namedStyleType",
+		}},
+		{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     &docs.Range{StartIndex: 12, EndIndex: 16},
+			TextStyle: &docs.TextStyle{Bold: true},
+			Fields:    "bold",
+		}},
+		{CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			Range:        &docs.Range{StartIndex: 22, EndIndex: 30},
+			BulletPreset: docs.BulletPresetDisc,
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestORPHAN_RestoreRequests_UndoesTextStyleAndListChanges(t *testing.T) {
+	// Arrange
+	store := seedStructured(t)
+	ctx := context.Background()
+	snapshot, err := store.Get(ctx, "d")
+	require.NoError(t, err)
+	require.Equal(t, []string{"HEADING_1:Title\n", "NORMAL_TEXT:some *bold* text\n",
+		"NORMAL_TEXT:0:one\n", "NORMAL_TEXT:1:two\n", "NORMAL_TEXT:end\n"}, outline(snapshot))
+
+	_, err = store.BatchUpdate(ctx, "d", []docs.Request{deleteRange(7, 26), insert(7, "replaced\n")}, nil)
+	require.NoError(t, err)
+	current, err := store.Get(ctx, "d")
+	require.NoError(t, err)
+
+	// Act
+	requests, err := docs.RestoreRequests(current, snapshot)
+	require.NoError(t, err)
+	_, err = store.BatchUpdate(ctx, "d", requests, nil)
+	require.NoError(t, err)
+
+	// Assert
+	restored, err := store.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.Equal(t, outline(snapshot), outline(restored))
+	assert.NotNil(t, requests[0].DeleteContentRange)
+	assert.Equal(t, 7, requests[0].DeleteContentRange.Range.StartIndex, "the unchanged heading is kept")
+}
+
+func TestORPHAN_RestoreRequests_IdenticalDocuments_ReturnsNothing(t *testing.T) {
+	// Arrange
+	store := seedStructured(t)
+	doc, err := store.Get(context.Background(), "d")
+	require.NoError(t, err)
+
+	// Act
+	requests, err := docs.RestoreRequests(doc, doc)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestORPHAN_RestoreRequests_ChangedRangeWithTable_IsNotRestorable(t *testing.T) {
+	// Arrange: the current document has a table where the target has text.
+	target := &docs.Document{Body: &docs.Body{Content: []docs.StructuralElement{
+		{StartIndex: 1, EndIndex: 4, Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{
+			{StartIndex: 1, EndIndex: 4, TextRun: &docs.TextRun{Content: "ab\n"}},
+		}}},
+	}}}
+	current := &docs.Document{Body: &docs.Body{Content: []docs.StructuralElement{
+		{StartIndex: 1, EndIndex: 3, Table: &docs.Table{}},
+		{StartIndex: 3, EndIndex: 6, Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{
+			{StartIndex: 3, EndIndex: 6, TextRun: &docs.TextRun{Content: "ab\n"}},
+		}}},
+	}}}
+
+	// Act
+	_, err := docs.RestoreRequests(current, target)
+
+	// Assert
+	require.ErrorIs(t, err, docs.ErrNotRestorable)
+}
@@ -0,0 +1,146 @@
+package docs
+
+import (
+	"fmt"
+	"unicode/utf16"
+)
+
+// Placeholder fills indexes held by elements the flat model does not
+// represent, such as table boundaries and inline objects, so that every
+// index in a simulated document matches the original.
+const Placeholder = '\uFFFC'
+
+// Simulate applies requests to a copy of doc with the in-memory model and
+// returns the outcome; doc itself is not modified. It lets dry runs show
+// the effect of an edit without writing it.
+func Simulate(doc *Document, requests []Request) (*Document, error) {
+	m := loadDocument(doc)
+
+	for i, req := range requests {
+		if _, err := m.apply(req); err != nil {
+			return nil, fmt.Errorf("%w: requests[%d]: %w", ErrInvalidRequest, i, err)
+		}
+	}
+
+	return m.document(doc.DocumentID), nil
+}
+
+// loadDocument builds the flat model of doc's body, headers, footers
+// and footnotes.
+func loadDocument(doc *Document) *memoryDocument {
+	var body []StructuralElement
+	if doc.Body != nil {
+		body = doc.Body.Content
+	}
+
+	m := &memoryDocument{title: doc.Title, revision: 1, body: loadSegment(bodyBaseIndex, body)}
+
+	if doc.DocumentStyle != nil {
+		m.style = *doc.DocumentStyle
+	}
+
+	for id, h := range doc.Headers {
+		m.loadSegment(&m.headers, id, h.Content)
+	}
+
+	for id, f := range doc.Footers {
+		m.loadSegment(&m.footers, id, f.Content)
+	}
+
+	for id, f := range doc.Footnotes {
+		m.loadSegment(&m.footnotes, id, f.Content)
+	}
+
+	return m
+}
+
+func (d *memoryDocument) loadSegment(segments *map[string]*segment, id string, content []StructuralElement) {
+	if *segments == nil {
+		*segments = make(map[string]*segment)
+	}
+
+	(*segments)[id] = loadSegment(0, content)
+}
+
+func loadSegment(base int, content []StructuralElement) *segment {
+	seg := &segment{base: base}
+	seg.load(content)
+
+	if n := len(seg.units); n == 0 || seg.units[n-1] != '\n' {
+		seg.push('\n', TextStyle{}, paragraphInfo{style: ParagraphStyle{NamedStyleType: StyleNormalText}})
+	}
+
+	return seg
+}
+
+func (s *segment) load(content []StructuralElement) {
+	for _, el := range content {
+		switch {
+		case el.Paragraph != nil:
+			s.loadParagraph(el)
+		case el.Table != nil:
+			for _, row := range el.Table.TableRows {
+				for _, cell := range row.TableCells {
+					s.load(cell.Content)
+				}
+			}
+
+			s.pad(el.EndIndex)
+		default:
+			s.pad(el.EndIndex)
+		}
+	}
+}
+
+func (s *segment) loadParagraph(el StructuralElement) {
+	s.pad(el.StartIndex)
+
+	for _, pe := range el.Paragraph.Elements {
+		s.pad(pe.StartIndex)
+
+		if pe.TextRun == nil {
+			continue
+		}
+
+		var style TextStyle
+		if pe.TextRun.TextStyle != nil {
+			style = *pe.TextRun.TextStyle
+		}
+
+		for _, unit := range utf16.Encode([]rune(pe.TextRun.Content)) {
+			s.push(unit, style, paragraphInfo{})
+		}
+	}
+
+	s.pad(el.EndIndex)
+
+	n := len(s.units)
+	if n == 0 || s.units[n-1] != '\n' {
+		return
+	}
+
+	info := paragraphInfo{style: ParagraphStyle{NamedStyleType: StyleNormalText}}
+	if el.Paragraph.ParagraphStyle != nil {
+		info.style = *el.Paragraph.ParagraphStyle
+	}
+
+	if el.Paragraph.Bullet != nil {
+		bullet := *el.Paragraph.Bullet
+		info.bullet = &bullet
+	}
+
+	s.paras[n-1] = info
+}
+
+// pad appends placeholders until the segment reaches index.
+func (s *segment) pad(index int) {
+	for s.base+len(s.units) < index {
+		s.push(Placeholder, TextStyle{}, paragraphInfo{})
+	}
+}
+
+func (s *segment) push(unit uint16, style TextStyle, info paragraphInfo) {
+	s.units = append(s.units, unit)
+	s.styles = append(s.styles, style)
+	s.paras = append(s.paras, info)
+}
@@ -0,0 +1,37 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+func TestORPHAN_Simulate_KeepsIndexesAroundUnmodelledElements(t *testing.T) {
+	// Arrange: "ab\n", a two-unit table placeholder, then "cd\n".
+	doc := &docs.Document{
+		DocumentID: "d",
+		Body: &docs.Body{Content: []docs.StructuralElement{
+			{EndIndex: 1, SectionBreak: &docs.SectionBreak{}},
+			{StartIndex: 1, EndIndex: 4, Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{
+				{StartIndex: 1, EndIndex: 4, TextRun: &docs.TextRun{Content: "ab\n"}},
+			}}},
+			{StartIndex: 4, EndIndex: 6, Table: &docs.Table{}},
+			{StartIndex: 6, EndIndex: 9, Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{
+				{StartIndex: 6, EndIndex: 9, TextRun: &docs.TextRun{Content: "cd\n"}},
+			}}},
+		}},
+	}
+
+	// Act
+	out, err := docs.Simulate(doc, []docs.Request{{InsertText: &docs.InsertTextRequest{
+		Text: "X", Location: &docs.Location{Index: 7},
+	}}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ab\n\uFFFC\uFFFCcXd\n", out.Text())
+	assert.Equal(t, "ab\ncd\n", doc.Text())
+}
@@ -0,0 +1,13 @@
+package docs
+
+import "context"
+
+// Store reads and edits documents. The Google Docs REST client and the
+// in-memory fake both implement it, so the edit pipeline is exercised
+// the same way in tests and in production.
+//
+// BatchUpdate is atomic: either every request is applied or none is.
+type Store interface {
+	Get(ctx context.Context, documentID string) (*Document, error)
+	BatchUpdate(ctx context.Context, documentID string, requests []Request, writeControl *WriteControl) (*BatchUpdateResponse, error)
+}
@@ -0,0 +1,152 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// DefaultTokenURL is Google's OAuth 2.0 token endpoint.
+const DefaultTokenURL = "https://oauth2.googleapis.com/token"
+
+// tokenExpiryLeeway refreshes tokens slightly before they expire so a
+// request never goes out with a token that dies in flight.
+const tokenExpiryLeeway = time.Minute
+
+// ErrNoAccessToken signals a token response without an access token.
+var ErrNoAccessToken = errors.New("token response has no access_token")
+
+// ErrNoCallerToken signals a request made without the caller's own
+// Google access token.
+var ErrNoCallerToken = fmt.Errorf("%w: request carries no Google access token", ErrUnauthenticated)
+
+// TokenSource supplies OAuth access tokens for Google API calls.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticTokenSource always returns the same token. It suits short-lived
+// local runs with a token minted elsewhere.
+type StaticTokenSource string
+
+// Token implements TokenSource.
+func (s StaticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+type callerTokenKey struct{}
+
+// WithCallerToken returns ctx carrying the Google access token of the
+// caller a request is made for.
+func WithCallerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, callerTokenKey{}, token)
+}
+
+// CallerTokenSource returns the access token WithCallerToken put in the
+// context, so every caller edits as their own Google identity.
+type CallerTokenSource struct{}
+
+// Token implements TokenSource.
+func (CallerTokenSource) Token(ctx context.Context) (string, error) {
+	if token, _ := ctx.Value(callerTokenKey{}).(string); token != "" {
+		return token, nil
+	}
+
+	return "", ErrNoCallerToken
+}
+
+// RefreshTokenSource exchanges a long-lived refresh token for access
+// tokens and caches each one until shortly before it expires.
+type RefreshTokenSource struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	HTTPClient   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Token implements TokenSource.
+func (s *RefreshTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+
+	ctx, span := tracing.StartStage(ctx, "token_refresh")
+	token, expiresIn, err := s.refresh(ctx)
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.expiry = time.Now().Add(expiresIn - tokenExpiryLeeway)
+
+	return token, nil
+}
+
+func (s *RefreshTokenSource) refresh(ctx context.Context) (string, time.Duration, error) {
+	tokenURL := s.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {s.ClientID},
+		"client_secret": {s.ClientSecret},
+		"refresh_token": {s.RefreshToken},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("build token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("refresh access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("refresh access token: %w", decodeAPIError(resp))
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("decode token response: %w", err)
+	}
+
+	if body.AccessToken == "" {
+		return "", 0, ErrNoAccessToken
+	}
+
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,85 @@
+package editlock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Backend names accepted by Config.Backend.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// ErrUnknownBackend signals a Config.Backend value that has no Locker.
+var ErrUnknownBackend = errors.New("unknown edit lock backend")
+
+// Config bounds how long and how many edits may wait for one document.
+// Backend is left empty when not configured, so the caller can pick
+// redis whenever Redis is available.
+type Config struct {
+	Backend string
+	// WaitTimeout is the longest an edit waits for the lock.
+	WaitTimeout time.Duration
+	// MaxQueue is the number of edits that may wait behind the one
+	// holding the lock; further edits fail at once. 0 means unbounded.
+	MaxQueue int
+	// LeaseTTL is how long a Redis lock outlives a replica that died
+	// while holding it. The holder renews it well before then.
+	LeaseTTL time.Duration
+}
+
+// DefaultConfig lets a handful of edits queue per document, each waiting
+// about as long as a slow batchUpdate takes.
+func DefaultConfig() Config {
+	return Config{
+		WaitTimeout: 30 * time.Second,
+		MaxQueue:    16,
+		LeaseTTL:    30 * time.Second,
+	}
+}
+
+// LoadConfig starts from DefaultConfig and applies the environment
+// overrides:
+//
+//	EDIT_LOCK_BACKEND, EDIT_LOCK_TIMEOUT, EDIT_LOCK_MAX_QUEUE, EDIT_LOCK_TTL
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+	cfg.Backend = os.Getenv("EDIT_LOCK_BACKEND")
+
+	switch cfg.Backend {
+	case "", BackendMemory, BackendRedis:
+	default:
+		return cfg, fmt.Errorf("%w: %q", ErrUnknownBackend, cfg.Backend)
+	}
+
+	durations := map[string]*time.Duration{
+		"EDIT_LOCK_TIMEOUT": &cfg.WaitTimeout,
+		"EDIT_LOCK_TTL":     &cfg.LeaseTTL,
+	}
+
+	for key, target := range durations {
+		if raw := os.Getenv(key); raw != "" {
+			parsed, err := time.ParseDuration(raw)
+			if err != nil || parsed <= 0 {
+				return cfg, fmt.Errorf("parse %s: must be a positive duration, got %q", key, raw)
+			}
+
+			*target = parsed
+		}
+	}
+
+	if raw := os.Getenv("EDIT_LOCK_MAX_QUEUE"); raw != "" {
+		maxQueue, err := strconv.Atoi(raw)
+		if err != nil || maxQueue < 0 {
+			return cfg, fmt.Errorf("parse EDIT_LOCK_MAX_QUEUE: must be a non-negative integer, got %q", raw)
+		}
+
+		cfg.MaxQueue = maxQueue
+	}
+
+	return cfg, nil
+}
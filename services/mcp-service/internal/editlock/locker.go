@@ -0,0 +1,29 @@
+// Package editlock serializes edits to the same document. Every edit
+// reads the document, computes indexes against that revision and then
+// writes, so two edits that overlap would interleave their inserts and
+// corrupt each other's positions. Edits to different documents still
+// run in parallel.
+package editlock
+
+import (
+	"context"
+	"errors"
+)
+
+// Sentinel errors for an edit that did not get the lock.
+var (
+	ErrTimeout   = errors.New("timed out waiting for the document edit lock")
+	ErrQueueFull = errors.New("too many edits are waiting for the document")
+)
+
+// Locker hands out one lock per key, in the order callers asked for it.
+// Lock blocks until the caller holds the lock, the wait times out
+// (ErrTimeout), the queue is full (ErrQueueFull) or ctx ends. The
+// returned release func must be called exactly once. Implementations
+// must be safe for concurrent use.
+type Locker interface {
+	Lock(ctx context.Context, key string) (release func(), err error)
+	// Depth counts the edits holding or waiting for a lock in this
+	// process, across all keys.
+	Depth() int
+}
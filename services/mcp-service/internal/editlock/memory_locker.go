@@ -0,0 +1,108 @@
+package editlock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// queue is the lock of one key. slot holds a token while the lock is
+// taken; blocked senders are woken in arrival order. pending counts the
+// holder and the waiters, and the queue is dropped when it reaches 0.
+type queue struct {
+	slot    chan struct{}
+	pending int
+}
+
+// MemoryLocker serializes edits within one process. It is the default
+// and is only correct when a single replica serves all traffic.
+type MemoryLocker struct {
+	waitTimeout time.Duration
+	maxQueue    int
+
+	mu     sync.Mutex
+	queues map[string]*queue
+	depth  atomic.Int64
+}
+
+// NewMemoryLocker returns a locker using cfg's WaitTimeout and MaxQueue.
+func NewMemoryLocker(cfg Config) *MemoryLocker {
+	return &MemoryLocker{
+		waitTimeout: cfg.WaitTimeout,
+		maxQueue:    cfg.MaxQueue,
+		queues:      make(map[string]*queue),
+	}
+}
+
+// Lock implements Locker.
+func (l *MemoryLocker) Lock(ctx context.Context, key string) (func(), error) {
+	ctx, cancel := withWaitTimeout(ctx, l.waitTimeout)
+	defer cancel()
+
+	return l.lock(ctx, key)
+}
+
+// lock waits for key until ctx ends, without a timeout of its own.
+func (l *MemoryLocker) lock(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+
+	q, ok := l.queues[key]
+	if !ok {
+		q = &queue{slot: make(chan struct{}, 1)}
+		l.queues[key] = q
+	}
+
+	if l.maxQueue > 0 && q.pending > l.maxQueue {
+		l.mu.Unlock()
+
+		return nil, ErrQueueFull
+	}
+
+	q.pending++
+	l.depth.Add(1)
+	l.mu.Unlock()
+
+	select {
+	case q.slot <- struct{}{}:
+		var once sync.Once
+
+		return func() {
+			once.Do(func() {
+				<-q.slot
+				l.leave(key, q)
+			})
+		}, nil
+	case <-ctx.Done():
+		l.leave(key, q)
+
+		return nil, context.Cause(ctx)
+	}
+}
+
+func (l *MemoryLocker) leave(key string, q *queue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	q.pending--
+	l.depth.Add(-1)
+
+	if q.pending == 0 {
+		delete(l.queues, key)
+	}
+}
+
+// Depth implements Locker.
+func (l *MemoryLocker) Depth() int {
+	return int(l.depth.Load())
+}
+
+// withWaitTimeout bounds ctx by timeout, reporting expiry as ErrTimeout
+// through context.Cause. A non-positive timeout leaves ctx unbounded.
+func withWaitTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeoutCause(ctx, timeout, ErrTimeout)
+}
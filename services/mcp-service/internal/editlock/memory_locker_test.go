@@ -0,0 +1,118 @@
+package editlock_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/editlock"
+)
+
+func TestORPHAN_MemoryLocker_SameDocument_RunsInArrivalOrder(t *testing.T) {
+	// Arrange
+	locker := editlock.NewMemoryLocker(editlock.DefaultConfig())
+	release, err := locker.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+
+	// Act
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			next, err := locker.Lock(context.Background(), "doc-1")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			next()
+		}()
+
+		require.Eventually(t, func() bool { return locker.Depth() == i+1 }, time.Second, time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, []int{1, 2, 3}, order)
+	assert.Equal(t, 0, locker.Depth())
+}
+
+func TestORPHAN_MemoryLocker_DifferentDocuments_DoNotWait(t *testing.T) {
+	// Arrange
+	locker := editlock.NewMemoryLocker(editlock.DefaultConfig())
+	releaseA, err := locker.Lock(context.Background(), "doc-a")
+	require.NoError(t, err)
+	defer releaseA()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	releaseB, err := locker.Lock(ctx, "doc-b")
+
+	// Assert
+	require.NoError(t, err)
+	releaseB()
+	assert.Equal(t, 1, locker.Depth())
+}
+
+func TestORPHAN_MemoryLocker_HeldTooLong_TimesOut(t *testing.T) {
+	// Arrange
+	cfg := editlock.DefaultConfig()
+	cfg.WaitTimeout = 20 * time.Millisecond
+	locker := editlock.NewMemoryLocker(cfg)
+	release, err := locker.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+	defer release()
+
+	// Act
+	_, err = locker.Lock(context.Background(), "doc-1")
+
+	// Assert
+	require.ErrorIs(t, err, editlock.ErrTimeout)
+	assert.Equal(t, 1, locker.Depth())
+}
+
+func TestORPHAN_MemoryLocker_QueueFull_FailsImmediately(t *testing.T) {
+	// Arrange
+	cfg := editlock.DefaultConfig()
+	cfg.MaxQueue = 1
+	locker := editlock.NewMemoryLocker(cfg)
+	release, err := locker.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+	defer release()
+
+	waiting := make(chan struct{})
+	go func() {
+		defer close(waiting)
+
+		if next, err := locker.Lock(context.Background(), "doc-1"); err == nil {
+			next()
+		}
+	}()
+	require.Eventually(t, func() bool { return locker.Depth() == 2 }, time.Second, time.Millisecond)
+
+	// Act
+	_, err = locker.Lock(context.Background(), "doc-1")
+
+	// Assert
+	require.ErrorIs(t, err, editlock.ErrQueueFull)
+	release()
+	<-waiting
+}
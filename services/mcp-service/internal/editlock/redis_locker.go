@@ -0,0 +1,161 @@
+package editlock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "mcp:editlock:"
+
+// pollInterval is how often a replica retries a lock held elsewhere.
+const pollInterval = 50 * time.Millisecond
+
+// releaseTimeout bounds the call that gives a lock back, which runs
+// after the edit's own context may have ended.
+const releaseTimeout = 2 * time.Second
+
+// releaseScript and renewScript only touch the lock while it still
+// carries the caller's token, so a replica whose lease lapsed can never
+// release or extend a lock another replica has since taken.
+var (
+	releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+	renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// RedisLocker serializes edits across replicas. Edits first queue in
+// process, so each replica has at most one caller polling Redis per
+// document, then take a lease in Redis that is renewed while the edit
+// runs and expires on its own if the replica dies.
+type RedisLocker struct {
+	client      redis.Cmdable
+	local       *MemoryLocker
+	waitTimeout time.Duration
+	leaseTTL    time.Duration
+}
+
+// NewRedisLocker returns a locker sharing locks through client.
+func NewRedisLocker(client redis.Cmdable, cfg Config) *RedisLocker {
+	return &RedisLocker{
+		client:      client,
+		local:       NewMemoryLocker(cfg),
+		waitTimeout: cfg.WaitTimeout,
+		leaseTTL:    cfg.LeaseTTL,
+	}
+}
+
+// Lock implements Locker.
+func (l *RedisLocker) Lock(ctx context.Context, key string) (func(), error) {
+	ctx, cancel := withWaitTimeout(ctx, l.waitTimeout)
+	defer cancel()
+
+	releaseLocal, err := l.local.lock(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	redisKey := redisKeyPrefix + key
+	token := uuid.NewString()
+
+	if err := l.acquire(ctx, redisKey, token); err != nil {
+		releaseLocal()
+
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go l.renew(redisKey, token, stop, done)
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+
+			l.release(redisKey, token)
+			releaseLocal()
+		})
+	}, nil
+}
+
+// acquire polls until the lease is taken or ctx ends.
+func (l *RedisLocker) acquire(ctx context.Context, redisKey, token string) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := l.client.SetNX(ctx, redisKey, token, l.leaseTTL).Result()
+
+		switch {
+		case ok && ctx.Err() != nil:
+			// Taken just as ctx ended: the caller gives up, so the lease must
+			// not block other editors until it expires
+			l.release(redisKey, token)
+
+			return context.Cause(ctx)
+		case ok:
+			return nil
+		case ctx.Err() != nil:
+			return context.Cause(ctx)
+		case err != nil:
+			return fmt.Errorf("acquire edit lock: %w", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// release gives the lease back if it still carries token.
+func (l *RedisLocker) release(redisKey, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	// A failed release only delays the next edit until the lease expires
+	_ = releaseScript.Run(ctx, l.client, []string{redisKey}, token).Err()
+}
+
+// renew extends the lease at a third of its TTL until stop is closed.
+func (l *RedisLocker) renew(redisKey, token string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(l.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+			_ = renewScript.Run(ctx, l.client, []string{redisKey}, token, l.leaseTTL.Milliseconds()).Err()
+			cancel()
+		}
+	}
+}
+
+// Depth implements Locker.
+func (l *RedisLocker) Depth() int {
+	return l.local.Depth()
+}
@@ -0,0 +1,130 @@
+package editlock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/editlock"
+)
+
+func newRedisLockers(t *testing.T, cfg editlock.Config) (*miniredis.Miniredis, *editlock.RedisLocker, *editlock.RedisLocker) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return server, editlock.NewRedisLocker(client, cfg), editlock.NewRedisLocker(client, cfg)
+}
+
+func TestORPHAN_RedisLocker_SecondReplica_WaitsForRelease(t *testing.T) {
+	// Arrange
+	server, replicaA, replicaB := newRedisLockers(t, editlock.DefaultConfig())
+	release, err := replicaA.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+
+	acquired := make(chan error, 1)
+
+	// Act
+	go func() {
+		next, err := replicaB.Lock(context.Background(), "doc-1")
+		if err == nil {
+			next()
+		}
+		acquired <- err
+	}()
+
+	// Assert
+	select {
+	case err := <-acquired:
+		t.Fatalf("second replica acquired a held lock: %v", err)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	assert.True(t, server.Exists("mcp:editlock:doc-1"))
+	release()
+	require.NoError(t, <-acquired)
+	assert.False(t, server.Exists("mcp:editlock:doc-1"))
+}
+
+func TestORPHAN_RedisLocker_HeldElsewhere_TimesOut(t *testing.T) {
+	// Arrange
+	cfg := editlock.DefaultConfig()
+	cfg.WaitTimeout = 120 * time.Millisecond
+	_, replicaA, replicaB := newRedisLockers(t, cfg)
+	release, err := replicaA.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+	defer release()
+
+	// Act
+	_, err = replicaB.Lock(context.Background(), "doc-1")
+
+	// Assert
+	require.ErrorIs(t, err, editlock.ErrTimeout)
+	assert.Equal(t, 0, replicaB.Depth())
+}
+
+func TestORPHAN_RedisLocker_LapsedLease_IsNotReleasedByOldHolder(t *testing.T) {
+	// Arrange
+	server, replicaA, replicaB := newRedisLockers(t, editlock.DefaultConfig())
+	releaseA, err := replicaA.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+	server.FastForward(time.Minute)
+	releaseB, err := replicaB.Lock(context.Background(), "doc-1")
+	require.NoError(t, err)
+	defer releaseB()
+
+	// Act
+	releaseA()
+
+	// Assert
+	assert.True(t, server.Exists("mcp:editlock:doc-1"))
+}
+
+// cancelAfterSetNX cancels the caller's context once SETNX has run, as
+// when the wait deadline passes just after the lease was taken.
+type cancelAfterSetNX struct {
+	cancel context.CancelFunc
+}
+
+func (h cancelAfterSetNX) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h cancelAfterSetNX) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if cmd.Name() == "set" {
+			h.cancel()
+		}
+
+		return err
+	}
+}
+
+func (h cancelAfterSetNX) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestORPHAN_RedisLocker_ContextEndsAsLeaseIsTaken_ReleasesLease(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	client.AddHook(cancelAfterSetNX{cancel: cancel})
+	locker := editlock.NewRedisLocker(client, editlock.DefaultConfig())
+
+	// Act
+	_, err := locker.Lock(ctx, "doc-1")
+
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, server.Exists("mcp:editlock:doc-1"))
+}
@@ -0,0 +1,174 @@
+// Package elicitation tracks the elicitation/create requests the server
+// sends to clients, which declared the elicitation capability, to ask
+// their user for input in the middle of a tool call. The request goes
+// out on the session's SSE stream and the answer comes back as a
+// JSON-RPC response posted by the client, which Resolve hands to the
+// waiting tool call.
+package elicitation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Method is the JSON-RPC method of the server's request.
+const Method = "elicitation/create"
+
+// Actions a user can take on an elicitation.
+const (
+	ActionAccept  = "accept"
+	ActionDecline = "decline"
+	ActionCancel  = "cancel"
+)
+
+// DefaultTimeout is how long Ask waits for the user when no timeout is
+// configured.
+const DefaultTimeout = 60 * time.Second
+
+var (
+	// ErrTimeout is returned when the client does not answer in time.
+	ErrTimeout = errors.New("elicitation timed out")
+	// ErrSessionClosed is returned when the session ends while waiting.
+	ErrSessionClosed = errors.New("session closed during elicitation")
+	// ErrRejected is returned when the client answers with a JSON-RPC
+	// error instead of a result.
+	ErrRejected = errors.New("client rejected the elicitation")
+)
+
+// Request is the params of an elicitation/create request. The requested
+// schema is a flat object schema of primitive properties.
+type Request struct {
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+}
+
+// Response is the client's answer. Content is only set when the user
+// accepted.
+type Response struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// Sender delivers an elicitation/create request with the given JSON-RPC
+// id to the client.
+type Sender func(id string, request Request) error
+
+// Broker pairs outgoing requests with the responses clients post back.
+type Broker struct {
+	timeout time.Duration
+
+	mu      sync.Mutex
+	next    uint64
+	pending map[string]*waiter
+}
+
+type waiter struct {
+	sessionID string
+	reply     chan answer
+}
+
+type answer struct {
+	response Response
+	err      error
+}
+
+// NewBroker returns a Broker whose requests wait at most timeout.
+func NewBroker(timeout time.Duration) *Broker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Broker{timeout: timeout, pending: make(map[string]*waiter)}
+}
+
+// Timeout returns how long Ask waits for an answer.
+func (b *Broker) Timeout() time.Duration {
+	return b.timeout
+}
+
+// Ask sends request to the client of sessionID with send and waits for
+// its answer, the timeout or the end of ctx, whichever comes first.
+func (b *Broker) Ask(ctx context.Context, sessionID string, request Request, send Sender) (Response, error) {
+	b.mu.Lock()
+	b.next++
+	id := "elicitation-" + strconv.FormatUint(b.next, 10)
+	w := &waiter{sessionID: sessionID, reply: make(chan answer, 1)}
+	b.pending[id] = w
+	b.mu.Unlock()
+
+	defer b.forget(id)
+
+	if err := send(id, request); err != nil {
+		return Response{}, err
+	}
+
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+
+	select {
+	case a := <-w.reply:
+		return a.response, a.err
+	case <-timer.C:
+		return Response{}, fmt.Errorf("%w after %s", ErrTimeout, b.timeout)
+	case <-ctx.Done():
+		return Response{}, ctx.Err()
+	}
+}
+
+// Resolve hands the client's response to request id to the waiting Ask.
+// rpcErr is the message of a JSON-RPC error response, empty otherwise.
+// It reports false when no request with that id is pending for
+// sessionID, so the caller can tell a stray response apart.
+func (b *Broker) Resolve(sessionID, id string, result json.RawMessage, rpcErr string) bool {
+	b.mu.Lock()
+	w, ok := b.pending[id]
+	if ok && w.sessionID == sessionID {
+		delete(b.pending, id)
+	}
+	b.mu.Unlock()
+
+	if !ok || w.sessionID != sessionID {
+		return false
+	}
+
+	var a answer
+
+	switch {
+	case rpcErr != "":
+		a.err = fmt.Errorf("%w: %s", ErrRejected, rpcErr)
+	default:
+		if err := json.Unmarshal(result, &a.response); err != nil {
+			a.err = fmt.Errorf("%w: invalid result: %v", ErrRejected, err)
+		} else if a.response.Action == "" {
+			a.err = fmt.Errorf("%w: result has no action", ErrRejected)
+		}
+	}
+
+	w.reply <- a
+
+	return true
+}
+
+// CloseSession fails every request still waiting on sessionID.
+func (b *Broker) CloseSession(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, w := range b.pending {
+		if w.sessionID == sessionID {
+			delete(b.pending, id)
+			w.reply <- answer{err: ErrSessionClosed}
+		}
+	}
+}
+
+func (b *Broker) forget(id string) {
+	b.mu.Lock()
+	delete(b.pending, id)
+	b.mu.Unlock()
+}
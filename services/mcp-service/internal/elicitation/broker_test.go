@@ -0,0 +1,99 @@
+package elicitation_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/elicitation"
+)
+
+var question = elicitation.Request{
+	Message:         "Which match?",
+	RequestedSchema: map[string]interface{}{"type": "object"},
+}
+
+// answerWith returns a Sender that posts result back to broker as the
+// client of session would.
+func answerWith(broker *elicitation.Broker, session, result, rpcErr string) elicitation.Sender {
+	return func(id string, _ elicitation.Request) error {
+		go broker.Resolve(session, id, json.RawMessage(result), rpcErr)
+
+		return nil
+	}
+}
+
+func TestORPHAN_Broker_Accept_ReturnsContent(t *testing.T) {
+	// Arrange
+	broker := elicitation.NewBroker(time.Second)
+	send := answerWith(broker, "s1", `{"action":"accept","content":{"occurrence":"2"}}`, "")
+
+	// Act
+	response, err := broker.Ask(context.Background(), "s1", question, send)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, elicitation.ActionAccept, response.Action)
+	assert.Equal(t, "2", response.Content["occurrence"])
+}
+
+func TestORPHAN_Broker_ErrorResponse_IsRejected(t *testing.T) {
+	// Arrange
+	broker := elicitation.NewBroker(time.Second)
+	send := answerWith(broker, "s1", "", "Method not found")
+
+	// Act
+	_, err := broker.Ask(context.Background(), "s1", question, send)
+
+	// Assert
+	require.ErrorIs(t, err, elicitation.ErrRejected)
+}
+
+func TestORPHAN_Broker_OtherSessionsResponse_IsIgnored(t *testing.T) {
+	// Arrange
+	broker := elicitation.NewBroker(50 * time.Millisecond)
+	var resolved bool
+	send := func(id string, _ elicitation.Request) error {
+		resolved = broker.Resolve("s2", id, json.RawMessage(`{"action":"accept"}`), "")
+
+		return nil
+	}
+
+	// Act
+	_, err := broker.Ask(context.Background(), "s1", question, send)
+
+	// Assert
+	require.ErrorIs(t, err, elicitation.ErrTimeout)
+	assert.False(t, resolved)
+}
+
+func TestORPHAN_Broker_CloseSession_FailsPendingRequests(t *testing.T) {
+	// Arrange
+	broker := elicitation.NewBroker(time.Minute)
+	send := func(string, elicitation.Request) error {
+		go broker.CloseSession("s1")
+
+		return nil
+	}
+
+	// Act
+	_, err := broker.Ask(context.Background(), "s1", question, send)
+
+	// Assert
+	require.ErrorIs(t, err, elicitation.ErrSessionClosed)
+}
+
+func TestORPHAN_Broker_UnknownID_IsNotResolved(t *testing.T) {
+	// Arrange
+	broker := elicitation.NewBroker(time.Second)
+
+	// Act
+	resolved := broker.Resolve("s1", "elicitation-99", json.RawMessage(`{"action":"accept"}`), "")
+
+	// Assert
+	assert.False(t, resolved)
+}
@@ -0,0 +1,135 @@
+package fakegdocs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// Drive roles a fixture can grant the caller. RoleNone hides the
+// document as if it were not shared at all.
+const (
+	RoleOwner     = "owner"
+	RoleWriter    = "writer"
+	RoleCommenter = "commenter"
+	RoleReader    = "reader"
+	RoleNone      = "none"
+)
+
+// ErrInvalidFixture rejects a fixture the server cannot seed.
+var ErrInvalidFixture = errors.New("invalid fixture")
+
+// Fixture is a document the server starts with. Its content is either
+// Markdown, converted the way the edit tools convert it, or a Document
+// as documents.get returns it; indexes missing from a hand-written
+// Document are assigned in order. Header and Footer are Markdown for the
+// default header and footer of a Markdown fixture. Role defaults to
+// RoleOwner.
+type Fixture struct {
+	DocumentID string         `json:"documentId"`
+	Title      string         `json:"title,omitempty"`
+	Role       string         `json:"role,omitempty"`
+	Markdown   string         `json:"markdown,omitempty"`
+	Header     string         `json:"header,omitempty"`
+	Footer     string         `json:"footer,omitempty"`
+	Document   *docs.Document `json:"document,omitempty"`
+}
+
+func (f Fixture) validate() error {
+	if f.DocumentID == "" {
+		return fmt.Errorf("%w: no documentId", ErrInvalidFixture)
+	}
+
+	if f.Markdown != "" && f.Document != nil {
+		return fmt.Errorf("%w: %s sets both markdown and document", ErrInvalidFixture, f.DocumentID)
+	}
+
+	if (f.Header != "" || f.Footer != "") && f.Document != nil {
+		return fmt.Errorf("%w: %s sets a header or footer on a document", ErrInvalidFixture, f.DocumentID)
+	}
+
+	switch f.Role {
+	case "", RoleOwner, RoleWriter, RoleCommenter, RoleReader, RoleNone:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s has unknown role %q", ErrInvalidFixture, f.DocumentID, f.Role)
+	}
+}
+
+// LoadFixtures reads the fixtures in dir, in file name order. A .md
+// file is one document named after the file; a .json file holds one
+// Fixture or an array of them, and a fixture without a documentId takes
+// the file name. Other files are ignored.
+func LoadFixtures(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read fixtures: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var fixtures []Fixture
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		if ext != ".md" && ext != ".json" {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read fixture %s: %w", entry.Name(), err)
+		}
+
+		if ext == ".md" {
+			fixtures = append(fixtures, Fixture{DocumentID: name, Title: name, Markdown: string(raw)})
+
+			continue
+		}
+
+		parsed, err := parseFixtures(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse fixture %s: %w", entry.Name(), err)
+		}
+
+		for _, f := range parsed {
+			if f.DocumentID == "" {
+				f.DocumentID = name
+			}
+
+			fixtures = append(fixtures, f)
+		}
+	}
+
+	return fixtures, nil
+}
+
+func parseFixtures(raw []byte) ([]Fixture, error) {
+	var fixtures []Fixture
+
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "[") {
+		err := json.Unmarshal(raw, &fixtures)
+
+		return fixtures, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, err
+	}
+
+	return []Fixture{f}, nil
+}
@@ -0,0 +1,68 @@
+package fakegdocs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/fakegdocs"
+)
+
+func TestORPHAN_LoadFixtures_ReadsMarkdownAndJSONFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test-notes.md"), []byte("# Notes"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared.json"),
+		[]byte(`[{"documentId":"test-a","role":"reader","markdown":"A"},{"markdown":"B"}]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("ignored"), 0o600))
+
+	// Act
+	fixtures, err := fakegdocs.LoadFixtures(dir)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, fixtures, 3)
+	assert.Equal(t, "test-a", fixtures[0].DocumentID)
+	assert.Equal(t, fakegdocs.RoleReader, fixtures[0].Role)
+	assert.Equal(t, "shared", fixtures[1].DocumentID)
+	assert.Equal(t, "test-notes", fixtures[2].DocumentID)
+	assert.Equal(t, "# Notes", fixtures[2].Markdown)
+}
+
+func TestORPHAN_Server_DocumentFixture_AssignsIndexesInOrder(t *testing.T) {
+	// Arrange
+	fixture := fakegdocs.Fixture{DocumentID: "test-captured", Document: &docs.Document{Body: &docs.Body{
+		Content: []docs.StructuralElement{
+			{Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "One\n"}}}}},
+			{Paragraph: &docs.Paragraph{Elements: []docs.ParagraphElement{{TextRun: &docs.TextRun{Content: "Two\n"}}}}},
+		},
+	}}}
+	ts := newFake(t, false, fixture)
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+
+	// Act
+	doc, err := client.Get(context.Background(), "test-captured")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "One\nTwo\n", doc.Text())
+	require.Len(t, doc.Body.Content, 3)
+	assert.Equal(t, 5, doc.Body.Content[2].StartIndex)
+	assert.Equal(t, 9, doc.EndIndex())
+}
+
+func TestORPHAN_Server_FixtureWithUnknownRole_IsRejected(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(false)
+
+	// Act
+	_, err := fakegdocs.NewServer(store, []fakegdocs.Fixture{{DocumentID: "test-x", Role: "editor"}})
+
+	// Assert
+	require.ErrorIs(t, err, fakegdocs.ErrInvalidFixture)
+}
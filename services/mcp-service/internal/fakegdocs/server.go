@@ -0,0 +1,530 @@
+// Package fakegdocs is an offline stand-in for the Google endpoints the
+// service calls: documents.get and documents.batchUpdate with the index
+// rules of docs.MemoryStore, the Drive file and permission lookups, and
+// the OAuth token endpoint. Test stacks point GOOGLE_DOCS_API_URL and
+// GOOGLE_TOKEN_URL at it and read documents back through it to assert
+// on what each tool call actually wrote.
+package fakegdocs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/markdown"
+)
+
+// tokenLifetime is the expires_in of every access token, in seconds.
+const tokenLifetime = 3599
+
+// batchUpdateSuffix follows the document ID in batchUpdate URLs.
+const batchUpdateSuffix = ":batchUpdate"
+
+// fakeUserEmail is the account every token belongs to.
+const fakeUserEmail = "fake-user@example.com"
+
+// Server serves the fake APIs from a MemoryStore. Fixtures passed to
+// NewServer are seeded again on every reset, so test runs can start
+// from a known state without restarting the process.
+type Server struct {
+	store    *docs.MemoryStore
+	fixtures []Fixture
+	tokens   atomic.Int64
+
+	mu    sync.Mutex
+	roles map[string]string
+}
+
+// NewServer seeds fixtures into store and returns a Server for it.
+func NewServer(store *docs.MemoryStore, fixtures []Fixture) (*Server, error) {
+	s := &Server{store: store, fixtures: fixtures, roles: make(map[string]string)}
+
+	for _, f := range fixtures {
+		if err := s.Seed(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Seed adds f, replacing any document with the same ID.
+func (s *Server) Seed(f Fixture) error {
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	title := f.Title
+	if title == "" {
+		title = "Untitled document"
+	}
+
+	switch {
+	case f.Document != nil:
+		doc := *f.Document
+		doc.DocumentID, doc.Title = f.DocumentID, title
+		s.store.Load(&doc)
+	default:
+		s.store.Create(f.DocumentID, title)
+
+		err := s.seedMarkdown(f.DocumentID, "", f.Markdown)
+		if err == nil && f.Header != "" {
+			err = s.seedSegment(f.DocumentID, "createHeader", f.Header)
+		}
+
+		if err == nil && f.Footer != "" {
+			err = s.seedSegment(f.DocumentID, "createFooter", f.Footer)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidFixture, f.DocumentID, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Role == "" || f.Role == RoleOwner {
+		delete(s.roles, f.DocumentID)
+	} else {
+		s.roles[f.DocumentID] = f.Role
+	}
+
+	return nil
+}
+
+// seedSegment creates the default header or footer of documentID, as
+// kind says, and writes content into it.
+func (s *Server) seedSegment(documentID, kind, content string) error {
+	create := docs.Request{CreateHeader: &docs.CreateHeaderRequest{Type: docs.HeaderFooterDefault}}
+	if kind == "createFooter" {
+		create = docs.Request{CreateFooter: &docs.CreateFooterRequest{Type: docs.HeaderFooterDefault}}
+	}
+
+	resp, err := s.store.BatchUpdate(context.Background(), documentID, []docs.Request{create}, nil)
+	if err != nil {
+		return err
+	}
+
+	ids := resp.CreatedIDs(kind)
+	if len(ids) == 0 {
+		return fmt.Errorf("%s created nothing", kind)
+	}
+
+	return s.seedMarkdown(documentID, ids[0], content)
+}
+
+// seedMarkdown writes content into the empty segmentID of documentID,
+// then fills the footnotes it created.
+func (s *Server) seedMarkdown(documentID, segmentID, content string) error {
+	index := 0
+	if segmentID == "" {
+		index = 1
+	}
+
+	fragment := markdown.Parse(content)
+
+	requests, _ := fragment.Requests(segmentID, index, markdown.Placement{})
+	if len(requests) == 0 {
+		return nil
+	}
+
+	resp, err := s.store.BatchUpdate(context.Background(), documentID, requests, nil)
+	if err != nil {
+		return err
+	}
+
+	notes := markdown.FootnoteRequests(resp.CreatedIDs("createFootnote"), fragment.Footnotes())
+	if len(notes) > 0 {
+		_, err = s.store.BatchUpdate(context.Background(), documentID, notes, nil)
+	}
+
+	return err
+}
+
+// Reset drops every document and seeds the startup fixtures again.
+func (s *Server) Reset() error {
+	s.store.Reset()
+
+	s.mu.Lock()
+	clear(s.roles)
+	s.mu.Unlock()
+
+	for _, f := range s.fixtures {
+		if err := s.Seed(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) role(documentID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if role, ok := s.roles[documentID]; ok {
+		return role
+	}
+
+	return RoleOwner
+}
+
+// Handler routes the Docs, Drive and OAuth endpoints under the paths
+// Google serves them at, plus /fixtures for tests to seed and inspect
+// documents and /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/documents/{id}", s.authorized(s.getDocument))
+	mux.HandleFunc("POST /v1/documents/{call}", s.authorized(s.batchUpdate))
+	mux.HandleFunc("GET /drive/v3/files/{id}", s.authorized(s.getFile))
+	mux.HandleFunc("GET /drive/v3/files/{id}/permissions", s.authorized(s.listPermissions))
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /$discovery/rest", s.discovery)
+
+	mux.HandleFunc("PUT /fixtures/documents/{id}", s.putFixture)
+	mux.HandleFunc("GET /fixtures/documents/{id}", s.getFixture)
+	mux.HandleFunc("DELETE /fixtures/documents/{id}", s.deleteFixture)
+	mux.HandleFunc("POST /fixtures/reset", s.resetFixtures)
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	return mux
+}
+
+// authorized rejects calls without a bearer token, as Google does. Any
+// token is accepted, so callers may use a static one.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			writeError(w, http.StatusUnauthorized, "UNAUTHENTICATED",
+				"Request is missing required authentication credential.")
+
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) getDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := r.PathValue("id")
+
+	if s.role(documentID) == RoleNone {
+		writeError(w, http.StatusForbidden, "PERMISSION_DENIED", "The caller does not have permission")
+
+		return
+	}
+
+	doc, err := s.store.Get(r.Context(), documentID)
+	if err != nil {
+		writeStoreError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) batchUpdate(w http.ResponseWriter, r *http.Request) {
+	documentID, ok := strings.CutSuffix(r.PathValue("call"), batchUpdateSuffix)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Unknown method.")
+
+		return
+	}
+
+	switch s.role(documentID) {
+	case RoleNone, RoleReader, RoleCommenter:
+		writeError(w, http.StatusForbidden, "PERMISSION_DENIED", "The caller does not have permission")
+
+		return
+	}
+
+	var body docs.BatchUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid JSON payload received. "+err.Error())
+
+		return
+	}
+
+	resp, err := s.store.BatchUpdate(r.Context(), documentID, body.Requests, body.WriteControl)
+	if err != nil {
+		writeStoreError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// driveFile is the part of a Drive files resource callers read.
+type driveFile struct {
+	Kind         string            `json:"kind"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	MimeType     string            `json:"mimeType"`
+	Capabilities map[string]bool   `json:"capabilities"`
+	Owners       []drivePermission `json:"owners,omitempty"`
+}
+
+type drivePermission struct {
+	Kind         string `json:"kind"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Role         string `json:"role"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// getFile answers files.get. Drive reports files the caller cannot see
+// as not found rather than forbidden.
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	documentID := r.PathValue("id")
+	role := s.role(documentID)
+
+	doc, err := s.store.Get(r.Context(), documentID)
+	if role == RoleNone || errors.Is(err, docs.ErrDocumentNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "File not found: "+documentID+".")
+
+		return
+	}
+
+	if err != nil {
+		writeStoreError(w, err)
+
+		return
+	}
+
+	file := driveFile{
+		Kind:     "drive#file",
+		ID:       documentID,
+		Name:     doc.Title,
+		MimeType: "application/vnd.google-apps.document",
+		Capabilities: map[string]bool{
+			"canEdit":    role == RoleOwner || role == RoleWriter,
+			"canComment": role != RoleReader,
+			"canShare":   role == RoleOwner,
+		},
+	}
+
+	if role == RoleOwner {
+		file.Owners = []drivePermission{permission(role)}
+	}
+
+	writeJSON(w, http.StatusOK, file)
+}
+
+// listPermissions answers permissions.list with the caller's own
+// permission, the only one the fake tracks.
+func (s *Server) listPermissions(w http.ResponseWriter, r *http.Request) {
+	documentID := r.PathValue("id")
+	role := s.role(documentID)
+
+	if _, err := s.store.Get(r.Context(), documentID); role == RoleNone || errors.Is(err, docs.ErrDocumentNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "File not found: "+documentID+".")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":        "drive#permissionList",
+		"permissions": []drivePermission{permission(role)},
+	})
+}
+
+func permission(role string) drivePermission {
+	return drivePermission{
+		Kind: "drive#permission", ID: "fake-user", Type: "user", Role: role, EmailAddress: fakeUserEmail,
+	}
+}
+
+// token exchanges a refresh token or an authorization code for an
+// access token. Failures use the OAuth error body rather than the API
+// envelope, as Google's token endpoint does.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+
+		return
+	}
+
+	var grant string
+
+	switch r.PostForm.Get("grant_type") {
+	case "refresh_token":
+		grant = r.PostForm.Get("refresh_token")
+	case "authorization_code":
+		grant = r.PostForm.Get("code")
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+
+		return
+	}
+
+	if grant == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid_grant", "error_description": "Bad Request",
+		})
+
+		return
+	}
+
+	n := s.tokens.Add(1)
+
+	body := map[string]interface{}{
+		"access_token": "fake-access-token-" + strconv.FormatInt(n, 10),
+		"expires_in":   tokenLifetime,
+		"token_type":   "Bearer",
+		"scope":        "https://www.googleapis.com/auth/documents https://www.googleapis.com/auth/drive.file",
+	}
+
+	if r.PostForm.Get("grant_type") == "authorization_code" {
+		body["refresh_token"] = "fake-refresh-token-" + strconv.FormatInt(n, 10)
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// discovery stands in for the discovery document the health check
+// probes.
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"kind": "discovery#restDescription", "name": "docs", "version": "v1",
+	})
+}
+
+// fixtureView is a document as /fixtures shows it: enough for a test to
+// assert on content without walking the structural elements. Footnotes
+// maps footnote IDs to their text.
+type fixtureView struct {
+	DocumentID string            `json:"documentId"`
+	Title      string            `json:"title"`
+	RevisionID string            `json:"revisionId"`
+	Role       string            `json:"role"`
+	Text       string            `json:"text"`
+	EndIndex   int               `json:"endIndex"`
+	Header     string            `json:"header,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+	Footnotes  map[string]string `json:"footnotes,omitempty"`
+}
+
+func (s *Server) putFixture(w http.ResponseWriter, r *http.Request) {
+	var f Fixture
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid fixture: "+err.Error())
+
+		return
+	}
+
+	f.DocumentID = r.PathValue("id")
+
+	if err := s.Seed(f); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+
+		return
+	}
+
+	s.writeFixture(w, r, http.StatusCreated, f.DocumentID)
+}
+
+func (s *Server) getFixture(w http.ResponseWriter, r *http.Request) {
+	s.writeFixture(w, r, http.StatusOK, r.PathValue("id"))
+}
+
+func (s *Server) deleteFixture(w http.ResponseWriter, r *http.Request) {
+	documentID := r.PathValue("id")
+
+	s.store.Delete(documentID)
+
+	s.mu.Lock()
+	delete(s.roles, documentID)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) resetFixtures(w http.ResponseWriter, _ *http.Request) {
+	if err := s.Reset(); err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeFixture(w http.ResponseWriter, r *http.Request, status int, documentID string) {
+	doc, err := s.store.Get(r.Context(), documentID)
+	if err != nil {
+		writeStoreError(w, err)
+
+		return
+	}
+
+	view := fixtureView{
+		DocumentID: documentID,
+		Title:      doc.Title,
+		RevisionID: doc.RevisionID,
+		Role:       s.role(documentID),
+		Text:       doc.Text(),
+		EndIndex:   doc.EndIndex(),
+	}
+
+	if style := doc.DocumentStyle; style != nil && style.DefaultHeaderID != "" {
+		view.Header = doc.SegmentText(style.DefaultHeaderID)
+	}
+
+	if style := doc.DocumentStyle; style != nil && style.DefaultFooterID != "" {
+		view.Footer = doc.SegmentText(style.DefaultFooterID)
+	}
+
+	for id := range doc.Footnotes {
+		if view.Footnotes == nil {
+			view.Footnotes = make(map[string]string)
+		}
+
+		view.Footnotes[id] = doc.SegmentText(id)
+	}
+
+	writeJSON(w, status, view)
+}
+
+// writeStoreError maps store errors onto the statuses the Docs API
+// answers with, so docs.Client turns them back into the same sentinels.
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, docs.ErrDocumentNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+	case errors.Is(err, docs.ErrRevisionMismatch):
+		writeError(w, http.StatusBadRequest, "FAILED_PRECONDITION", err.Error())
+	case errors.Is(err, docs.ErrInvalidRequest):
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "Invalid requests. "+err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+	}
+}
+
+// writeError writes the Google API error envelope.
+func writeError(w http.ResponseWriter, code int, status, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": message, "status": status},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
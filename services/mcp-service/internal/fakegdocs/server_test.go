@@ -0,0 +1,173 @@
+package fakegdocs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/fakegdocs"
+)
+
+func newFake(t *testing.T, autoCreate bool, fixtures ...fakegdocs.Fixture) *httptest.Server {
+	t.Helper()
+
+	server, err := fakegdocs.NewServer(docs.NewMemoryStore(autoCreate), fixtures)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func insertText(index int, text string) docs.Request {
+	return docs.Request{InsertText: &docs.InsertTextRequest{Text: text, Location: &docs.Location{Index: index}}}
+}
+
+func TestORPHAN_Server_MarkdownFixture_ServedByDocumentsGet(t *testing.T) {
+	// Arrange
+	ts := newFake(t, false, fakegdocs.Fixture{
+		DocumentID: "test-seeded", Title: "Seeded", Markdown: "# Title\n\nFirst paragraph.",
+	})
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+
+	// Act
+	doc, err := client.Get(context.Background(), "test-seeded")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Seeded", doc.Title)
+	assert.Equal(t, "Title\nFirst paragraph.\n", doc.Text())
+	require.Len(t, doc.Body.Content, 3)
+	assert.Equal(t, docs.StyleHeading1, doc.Body.Content[1].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, 1, doc.Body.Content[1].StartIndex)
+}
+
+func TestORPHAN_Server_HeaderFooterAndFootnotes_SeededFromMarkdown(t *testing.T) {
+	// Arrange
+	ts := newFake(t, false, fakegdocs.Fixture{
+		DocumentID: "test-segments", Markdown: "Claim[^1].\n\n[^1]: Source.", Header: "Draft", Footer: "Page",
+	})
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+
+	// Act
+	doc, err := client.Get(context.Background(), "test-segments")
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, doc.DocumentStyle)
+	assert.Equal(t, "Draft\n", doc.SegmentText(doc.DocumentStyle.DefaultHeaderID))
+	assert.Equal(t, "Page\n", doc.SegmentText(doc.DocumentStyle.DefaultFooterID))
+	require.Len(t, doc.Footnotes, 1)
+
+	for id := range doc.Footnotes {
+		assert.Equal(t, " Source.\n", doc.SegmentText(id))
+	}
+}
+
+func TestORPHAN_Server_BatchUpdate_AppliesIndexesAndChecksRevision(t *testing.T) {
+	// Arrange
+	ts := newFake(t, false, fakegdocs.Fixture{DocumentID: "test-edit", Markdown: "Hello"})
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+	before, err := client.Get(context.Background(), "test-edit")
+	require.NoError(t, err)
+
+	// Act
+	_, err = client.BatchUpdate(context.Background(), "test-edit",
+		[]docs.Request{insertText(6, " world")}, &docs.WriteControl{RequiredRevisionID: before.RevisionID})
+	require.NoError(t, err)
+	_, staleErr := client.BatchUpdate(context.Background(), "test-edit",
+		[]docs.Request{insertText(1, "Oh, ")}, &docs.WriteControl{RequiredRevisionID: before.RevisionID})
+
+	// Assert
+	require.ErrorIs(t, staleErr, docs.ErrRevisionMismatch)
+	after, err := client.Get(context.Background(), "test-edit")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world\n", after.Text())
+}
+
+func TestORPHAN_Server_ReaderRole_CannotBatchUpdate(t *testing.T) {
+	// Arrange
+	ts := newFake(t, false,
+		fakegdocs.Fixture{DocumentID: "test-reader", Markdown: "Read only", Role: fakegdocs.RoleReader},
+		fakegdocs.Fixture{DocumentID: "test-hidden", Markdown: "Secret", Role: fakegdocs.RoleNone},
+	)
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+
+	// Act
+	_, readErr := client.Get(context.Background(), "test-reader")
+	_, writeErr := client.BatchUpdate(context.Background(), "test-reader", []docs.Request{insertText(1, "x")}, nil)
+	_, hiddenErr := client.Get(context.Background(), "test-hidden")
+
+	// Assert
+	require.NoError(t, readErr)
+	require.ErrorIs(t, writeErr, docs.ErrPermissionDenied)
+	require.ErrorIs(t, hiddenErr, docs.ErrPermissionDenied)
+}
+
+func TestORPHAN_Server_DrivePermissions_ReportFixtureRole(t *testing.T) {
+	// Arrange
+	ts := newFake(t, false, fakegdocs.Fixture{DocumentID: "test-shared", Markdown: "x", Role: fakegdocs.RoleCommenter})
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/drive/v3/files/test-shared/permissions", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	// Act
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Assert
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Permissions []struct {
+			Role string `json:"role"`
+		} `json:"permissions"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Permissions, 1)
+	assert.Equal(t, fakegdocs.RoleCommenter, body.Permissions[0].Role)
+}
+
+func TestORPHAN_Server_RefreshTokenSource_GetsTokenAndMissingBearerIsRejected(t *testing.T) {
+	// Arrange
+	ts := newFake(t, true)
+	tokens := &docs.RefreshTokenSource{TokenURL: ts.URL + "/token", RefreshToken: "refresh", HTTPClient: ts.Client()}
+	anonymous := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource(""))
+
+	// Act
+	token, err := tokens.Token(context.Background())
+	_, getErr := anonymous.Get(context.Background(), "test-anything")
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	require.ErrorIs(t, getErr, docs.ErrUnauthenticated)
+}
+
+func TestORPHAN_Server_Reset_RestoresStartupFixtures(t *testing.T) {
+	// Arrange
+	server, err := fakegdocs.NewServer(docs.NewMemoryStore(false),
+		[]fakegdocs.Fixture{{DocumentID: "test-reset", Markdown: "Original"}})
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	client := docs.NewClient(ts.URL, ts.Client(), docs.StaticTokenSource("token"))
+	_, err = client.BatchUpdate(context.Background(), "test-reset", []docs.Request{insertText(1, "Changed ")}, nil)
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, server.Reset())
+	doc, err := client.Get(context.Background(), "test-reset")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Original\n", doc.Text())
+}
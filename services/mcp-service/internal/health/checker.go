@@ -0,0 +1,31 @@
+package health
+
+import "context"
+
+// Checker probes one dependency or internal resource. Check must honour
+// ctx cancellation; the Registry enforces a per-check timeout through it.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) Result
+}
+
+// CheckerFunc adapts a plain function into a Checker.
+type CheckerFunc struct {
+	name string
+	fn   func(ctx context.Context) Result
+}
+
+// NewCheckerFunc wraps fn as a Checker called name.
+func NewCheckerFunc(name string, fn func(ctx context.Context) Result) *CheckerFunc {
+	return &CheckerFunc{name: name, fn: fn}
+}
+
+// Name implements Checker.
+func (c *CheckerFunc) Name() string {
+	return c.name
+}
+
+// Check implements Checker.
+func (c *CheckerFunc) Check(ctx context.Context) Result {
+	return c.fn(ctx)
+}
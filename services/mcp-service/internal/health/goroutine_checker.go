@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// GoroutineChecker watches the number of open SSE streams, each of which
+// pins a writer goroutine, together with the process goroutine count.
+// Crossing the limit degrades the service rather than failing it: the
+// process is still serving, but new streams are a leak risk.
+type GoroutineChecker struct {
+	streams func() int64
+	limit   int64
+}
+
+// NewGoroutineChecker returns a checker reading the stream count from
+// streams and degrading above limit. A non-positive limit disables the
+// threshold.
+func NewGoroutineChecker(streams func() int64, limit int64) *GoroutineChecker {
+	return &GoroutineChecker{streams: streams, limit: limit}
+}
+
+// Name implements Checker.
+func (c *GoroutineChecker) Name() string {
+	return "sseStreams"
+}
+
+// Check implements Checker.
+func (c *GoroutineChecker) Check(_ context.Context) Result {
+	streams := c.streams()
+
+	result := Result{
+		Status: StatusHealthy,
+		Details: map[string]interface{}{
+			"streams":    streams,
+			"limit":      c.limit,
+			"goroutines": runtime.NumGoroutine(),
+		},
+	}
+
+	if c.limit > 0 && streams > c.limit {
+		result.Status = StatusDegraded
+		result.Message = fmt.Sprintf("%d SSE streams open, limit %d", streams, c.limit)
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPChecker verifies that a set of upstream endpoints is reachable.
+// Any response below 500 counts as reachable: a token endpoint answering
+// a bare GET with 400 or 405 is alive, which is all this check asserts.
+type HTTPChecker struct {
+	name   string
+	urls   []string
+	client *http.Client
+}
+
+// NewHTTPChecker returns a checker called name that probes every url.
+func NewHTTPChecker(name string, client *http.Client, urls ...string) *HTTPChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPChecker{name: name, urls: urls, client: client}
+}
+
+// Name implements Checker.
+func (c *HTTPChecker) Name() string {
+	return c.name
+}
+
+// Check implements Checker.
+func (c *HTTPChecker) Check(ctx context.Context) Result {
+	result := Result{Status: StatusHealthy, Details: map[string]interface{}{}}
+
+	for _, url := range c.urls {
+		start := time.Now()
+		status, err := c.probe(ctx, url)
+
+		endpoint := map[string]interface{}{"latencyMs": time.Since(start).Milliseconds()}
+		if err != nil {
+			endpoint["error"] = err.Error()
+			result.Status = StatusUnhealthy
+			result.Message = "unreachable: " + url
+		} else {
+			endpoint["httpStatus"] = status
+		}
+
+		result.Details[url] = endpoint
+	}
+
+	return result
+}
+
+func (c *HTTPChecker) probe(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, fmt.Errorf("upstream returned %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,45 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/health"
+)
+
+func TestORPHAN_HTTPChecker_ClientErrorCountsAsReachable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	checker := health.NewHTTPChecker("googleAPI", server.Client(), server.URL+"/token")
+
+	// Act
+	result := checker.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, health.StatusHealthy, result.Status)
+}
+
+func TestORPHAN_HTTPChecker_ServerError_ReportsUnhealthy(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	checker := health.NewHTTPChecker("googleAPI", server.Client(), server.URL)
+
+	// Act
+	result := checker.Check(context.Background())
+
+	// Assert
+	assert.Equal(t, health.StatusUnhealthy, result.Status)
+	assert.Contains(t, result.Message, server.URL)
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisChecker pings the Redis instance that backs the token store and
+// the shared rate limiter.
+type RedisChecker struct {
+	client redis.Cmdable
+}
+
+// NewRedisChecker returns a checker for client.
+func NewRedisChecker(client redis.Cmdable) *RedisChecker {
+	return &RedisChecker{client: client}
+}
+
+// Name implements Checker.
+func (c *RedisChecker) Name() string {
+	return "redis"
+}
+
+// Check implements Checker.
+func (c *RedisChecker) Check(ctx context.Context) Result {
+	start := time.Now()
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return Result{Status: StatusUnhealthy, Message: err.Error()}
+	}
+
+	return Result{
+		Status:  StatusHealthy,
+		Details: map[string]interface{}{"latencyMs": time.Since(start).Milliseconds()},
+	}
+}
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default timings used when a Registry is built with zero values.
+const (
+	DefaultTimeout  = 2 * time.Second
+	DefaultCacheTTL = 10 * time.Second
+)
+
+// Report aggregates every registered check. Status is the worst status
+// across checks, except that failing non-critical checks only degrade
+// the report instead of making it unhealthy.
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type entry struct {
+	checker  Checker
+	critical bool
+
+	mu     sync.Mutex
+	last   Result
+	cached bool
+}
+
+// Registry runs registered checkers concurrently, each under its own
+// timeout, and caches results for a TTL so that frequent probes from
+// Railway or docker-compose do not translate into upstream traffic.
+type Registry struct {
+	timeout time.Duration
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	entries []*entry
+}
+
+// NewRegistry builds an empty Registry. Non-positive durations fall back
+// to DefaultTimeout and DefaultCacheTTL.
+func NewRegistry(timeout, ttl time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Registry{timeout: timeout, ttl: ttl, now: time.Now}
+}
+
+// Register adds a checker. A critical checker that reports unhealthy
+// makes the service not ready; a non-critical one only degrades it.
+func (r *Registry) Register(checker Checker, critical bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, &entry{checker: checker, critical: critical})
+}
+
+// Run executes (or serves from cache) every registered check.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	entries := append([]*entry(nil), r.entries...)
+	r.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+
+	var wg sync.WaitGroup
+
+	for i, e := range entries {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = r.run(ctx, e)
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusHealthy, Checks: make(map[string]Result, len(entries))}
+
+	for i, e := range entries {
+		result := results[i]
+		report.Checks[e.checker.Name()] = result
+
+		status := result.Status
+		if !e.critical && status == StatusUnhealthy {
+			status = StatusDegraded
+		}
+
+		report.Status = worse(report.Status, status)
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := r.now()
+	if e.cached && now.Sub(e.last.CheckedAt) < r.ttl {
+		cached := e.last
+		cached.Cached = true
+
+		return cached
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	done := make(chan Result, 1)
+
+	go func() {
+		done <- e.checker.Check(checkCtx)
+	}()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-checkCtx.Done():
+		result = Result{Status: StatusUnhealthy, Message: "check timed out after " + r.timeout.String()}
+	}
+
+	result.Critical = e.critical
+	result.CheckedAt = now
+	result.Duration = r.now().Sub(now).String()
+
+	e.last = result
+	e.cached = true
+
+	return result
+}
@@ -0,0 +1,88 @@
+package health_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/health"
+)
+
+func staticChecker(name string, status health.Status, calls *atomic.Int32) health.Checker {
+	return health.NewCheckerFunc(name, func(context.Context) health.Result {
+		if calls != nil {
+			calls.Add(1)
+		}
+
+		return health.Result{Status: status}
+	})
+}
+
+func TestORPHAN_Registry_NonCriticalFailure_DegradesReport(t *testing.T) {
+	// Arrange
+	registry := health.NewRegistry(time.Second, time.Minute)
+	registry.Register(staticChecker("redis", health.StatusHealthy, nil), true)
+	registry.Register(staticChecker("googleAPI", health.StatusUnhealthy, nil), false)
+
+	// Act
+	report := registry.Run(context.Background())
+
+	// Assert
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.Equal(t, health.StatusUnhealthy, report.Checks["googleAPI"].Status)
+	assert.False(t, report.Checks["googleAPI"].Critical)
+}
+
+func TestORPHAN_Registry_CriticalFailure_MakesReportUnhealthy(t *testing.T) {
+	// Arrange
+	registry := health.NewRegistry(time.Second, time.Minute)
+	registry.Register(staticChecker("redis", health.StatusUnhealthy, nil), true)
+	registry.Register(staticChecker("sseStreams", health.StatusDegraded, nil), false)
+
+	// Act
+	report := registry.Run(context.Background())
+
+	// Assert
+	assert.Equal(t, health.StatusUnhealthy, report.Status)
+}
+
+func TestORPHAN_Registry_SlowChecker_TimesOut(t *testing.T) {
+	// Arrange
+	registry := health.NewRegistry(20*time.Millisecond, time.Minute)
+	registry.Register(health.NewCheckerFunc("slow", func(ctx context.Context) health.Result {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+
+		return health.Result{Status: health.StatusHealthy}
+	}), true)
+
+	// Act
+	start := time.Now()
+	report := registry.Run(context.Background())
+
+	// Assert
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, health.StatusUnhealthy, report.Status)
+	assert.Contains(t, report.Checks["slow"].Message, "timed out")
+}
+
+func TestORPHAN_Registry_CachesResultsWithinTTL(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+
+	registry := health.NewRegistry(time.Second, time.Minute)
+	registry.Register(staticChecker("redis", health.StatusHealthy, &calls), true)
+
+	// Act
+	first := registry.Run(context.Background())
+	second := registry.Run(context.Background())
+
+	// Assert
+	require.Equal(t, int32(1), calls.Load())
+	assert.False(t, first.Checks["redis"].Cached)
+	assert.True(t, second.Checks["redis"].Cached)
+}
@@ -0,0 +1,34 @@
+package health
+
+import "time"
+
+// Status is the outcome of a single check or of the whole report.
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// Result is what a Checker reports. Details carries checker-specific
+// diagnostics (latency, counts, endpoint) and is rendered verbatim.
+type Result struct {
+	Status    Status                 `json:"status"`
+	Message   string                 `json:"message,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Critical  bool                   `json:"critical"`
+	Cached    bool                   `json:"cached"`
+	CheckedAt time.Time              `json:"checkedAt"`
+	Duration  string                 `json:"duration"`
+}
+
+// worse returns the more severe of two statuses.
+func worse(a, b Status) Status {
+	rank := map[Status]int{StatusHealthy: 0, StatusDegraded: 1, StatusUnhealthy: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,201 @@
+// Package history keeps, per MCP session and document, the snapshots
+// taken before each edit so that the edits can be reverted.
+package history
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// Defaults for NewStore when no limits are configured.
+const (
+	DefaultLimit        = 10
+	DefaultMaxDocuments = 1000
+)
+
+// Entry is one recorded edit. Snapshot is the document as it was before
+// the edit; RevisionAfter is the revision the edit produced, which a
+// revert expects the document to still be at. Segment is the segment ID
+// the edit changed, empty for the body.
+type Entry struct {
+	ID             string         `json:"id"`
+	DocumentID     string         `json:"documentId"`
+	Tool           string         `json:"tool"`
+	Modes          []string       `json:"modes"`
+	RevisionBefore string         `json:"revisionBefore"`
+	RevisionAfter  string         `json:"revisionAfter"`
+	Segment        string         `json:"segment,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	Snapshot       *docs.Document `json:"-"`
+}
+
+type key struct {
+	sessionID  string
+	documentID string
+}
+
+// Store is a bounded in-memory edit history. Each session and document
+// keeps at most limit entries, dropping the oldest first, and at most
+// maxDocuments such histories are kept, dropping the least recently
+// recorded. Snapshots hold whole documents, so the bounds are what keep
+// memory in check. Store is safe for concurrent use.
+type Store struct {
+	mu           sync.Mutex
+	limit        int
+	maxDocuments int
+	entries      map[key][]Entry
+	touched      map[key]uint64
+	seq          uint64
+}
+
+// NewStore returns an empty store. A limit of zero or less disables
+// recording altogether.
+func NewStore(limit, maxDocuments int) *Store {
+	if maxDocuments <= 0 {
+		maxDocuments = DefaultMaxDocuments
+	}
+
+	return &Store{
+		limit:        limit,
+		maxDocuments: maxDocuments,
+		entries:      make(map[key][]Entry),
+		touched:      make(map[key]uint64),
+	}
+}
+
+// Record adds e as the latest edit of its document in sessionID and
+// returns it with its ID and creation time filled in.
+func (s *Store) Record(sessionID string, e Entry) Entry {
+	e.ID = uuid.New().String()
+	e.CreatedAt = time.Now()
+
+	if s.limit <= 0 {
+		return e
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{sessionID: sessionID, documentID: e.DocumentID}
+	if _, ok := s.entries[k]; !ok && len(s.entries) >= s.maxDocuments {
+		s.evictOldest()
+	}
+
+	entries := append(s.entries[k], e)
+	if len(entries) > s.limit {
+		entries = append([]Entry(nil), entries[len(entries)-s.limit:]...)
+	}
+
+	s.seq++
+	s.entries[k] = entries
+	s.touched[k] = s.seq
+
+	return e
+}
+
+// Last returns the latest edit of documentID in sessionID.
+func (s *Store) Last(sessionID, documentID string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[key{sessionID: sessionID, documentID: documentID}]
+	if len(entries) == 0 {
+		return Entry{}, false
+	}
+
+	return entries[len(entries)-1], true
+}
+
+// List returns the recorded edits of documentID in sessionID, newest
+// first.
+func (s *Store) List(sessionID, documentID string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[key{sessionID: sessionID, documentID: documentID}]
+	out := make([]Entry, len(entries))
+
+	for i, e := range entries {
+		out[len(entries)-1-i] = e
+	}
+
+	return out
+}
+
+// Reverted removes the entry id once its edit has been undone, leaving
+// the document at revision. When the edit before it led straight into
+// the reverted one, that edit now expects revision instead, so edits can
+// be reverted one after the other.
+func (s *Store) Reverted(sessionID, documentID, id, revision string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{sessionID: sessionID, documentID: documentID}
+	entries := s.entries[k]
+
+	for i, e := range entries {
+		if e.ID != id {
+			continue
+		}
+
+		entries = append(entries[:i:i], entries[i+1:]...)
+		if i > 0 && entries[i-1].RevisionAfter == e.RevisionBefore {
+			entries[i-1].RevisionAfter = revision
+		}
+
+		break
+	}
+
+	if len(entries) == 0 {
+		delete(s.entries, k)
+		delete(s.touched, k)
+
+		return
+	}
+
+	s.entries[k] = entries
+}
+
+// Forget drops every history of sessionID, for sessions that ended.
+func (s *Store) Forget(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k := range s.entries {
+		if k.sessionID == sessionID {
+			delete(s.entries, k)
+			delete(s.touched, k)
+		}
+	}
+}
+
+// Len returns the number of session and document histories held.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// evictOldest drops the history recorded into least recently.
+func (s *Store) evictOldest() {
+	var (
+		oldest key
+		found  bool
+	)
+
+	for k, seq := range s.touched {
+		if !found || seq < s.touched[oldest] {
+			oldest, found = k, true
+		}
+	}
+
+	if found {
+		delete(s.entries, oldest)
+		delete(s.touched, oldest)
+	}
+}
@@ -0,0 +1,87 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/history"
+)
+
+func TestORPHAN_Store_Record_KeepsNewestEntriesUpToLimit(t *testing.T) {
+	// Arrange
+	store := history.NewStore(2, 10)
+
+	// Act
+	store.Record("s1", history.Entry{DocumentID: "d", RevisionAfter: "rev-2"})
+	store.Record("s1", history.Entry{DocumentID: "d", RevisionAfter: "rev-3"})
+	store.Record("s1", history.Entry{DocumentID: "d", RevisionAfter: "rev-4"})
+
+	// Assert
+	entries := store.List("s1", "d")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "rev-4", entries[0].RevisionAfter)
+	assert.Equal(t, "rev-3", entries[1].RevisionAfter)
+	assert.NotEmpty(t, entries[0].ID)
+	assert.Empty(t, store.List("s2", "d"), "histories are per session")
+}
+
+func TestORPHAN_Store_Record_EvictsLeastRecentlyRecordedDocument(t *testing.T) {
+	// Arrange
+	store := history.NewStore(5, 2)
+	store.Record("s1", history.Entry{DocumentID: "a"})
+	store.Record("s1", history.Entry{DocumentID: "b"})
+	store.Record("s1", history.Entry{DocumentID: "a"})
+
+	// Act
+	store.Record("s1", history.Entry{DocumentID: "c"})
+
+	// Assert
+	assert.Equal(t, 2, store.Len())
+	assert.Len(t, store.List("s1", "a"), 2)
+	assert.Empty(t, store.List("s1", "b"))
+}
+
+func TestORPHAN_Store_Reverted_RebasesPreviousEntryOntoNewRevision(t *testing.T) {
+	// Arrange
+	store := history.NewStore(5, 10)
+	first := store.Record("s1", history.Entry{DocumentID: "d", RevisionBefore: "rev-1", RevisionAfter: "rev-2"})
+	second := store.Record("s1", history.Entry{DocumentID: "d", RevisionBefore: "rev-2", RevisionAfter: "rev-3"})
+
+	// Act
+	store.Reverted("s1", "d", second.ID, "rev-4")
+
+	// Assert
+	last, ok := store.Last("s1", "d")
+	require.True(t, ok)
+	assert.Equal(t, first.ID, last.ID)
+	assert.Equal(t, "rev-4", last.RevisionAfter)
+}
+
+func TestORPHAN_Store_ZeroLimit_RecordsNothing(t *testing.T) {
+	// Arrange
+	store := history.NewStore(0, 10)
+
+	// Act
+	store.Record("s1", history.Entry{DocumentID: "d"})
+
+	// Assert
+	_, ok := store.Last("s1", "d")
+	assert.False(t, ok)
+}
+
+func TestORPHAN_Store_Forget_DropsSessionHistories(t *testing.T) {
+	// Arrange
+	store := history.NewStore(5, 10)
+	store.Record("s1", history.Entry{DocumentID: "a"})
+	store.Record("s1", history.Entry{DocumentID: "b"})
+	store.Record("s2", history.Entry{DocumentID: "a"})
+
+	// Act
+	store.Forget("s1")
+
+	// Assert
+	assert.Equal(t, 1, store.Len())
+	assert.Len(t, store.List("s2", "a"), 1)
+}
@@ -0,0 +1,99 @@
+package markdown
+
+import (
+	"unicode/utf16"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// Fragment is Markdown converted to the paragraph model of Google Docs.
+// Warnings list constructs that were degraded to plain text.
+type Fragment struct {
+	Paragraphs []Paragraph
+	Warnings   []string
+}
+
+// Paragraph is one converted block. Text never contains a newline.
+type Paragraph struct {
+	Text       string
+	NamedStyle string
+	List       *List
+	Quote      bool
+	Spans      []Span
+	Footnotes  []Footnote
+}
+
+// Footnote is a footnote referenced at UTF-16 offset Offset of the
+// paragraph text. Content is the Markdown of the note.
+type Footnote struct {
+	Offset  int
+	Content string
+}
+
+// List marks a paragraph as a list item.
+type List struct {
+	Ordered bool
+	Level   int
+}
+
+// Span applies Style to the UTF-16 range [Start, End) of the paragraph
+// text. Fields is the field mask naming the attributes Style sets.
+type Span struct {
+	Start  int
+	End    int
+	Style  docs.TextStyle
+	Fields string
+}
+
+// Empty reports whether the fragment inserts nothing.
+func (f *Fragment) Empty() bool {
+	return len(f.Paragraphs) == 0
+}
+
+// Inline reports whether the fragment is a single unstyled paragraph,
+// i.e. text that can be spliced into an existing paragraph without
+// touching its paragraph style.
+func (f *Fragment) Inline() bool {
+	if len(f.Paragraphs) != 1 {
+		return false
+	}
+
+	p := f.Paragraphs[0]
+
+	return p.NamedStyle == docs.StyleNormalText && p.List == nil && !p.Quote
+}
+
+// Footnotes returns the content of every footnote in the order
+// Requests creates them.
+func (f *Fragment) Footnotes() []string {
+	var notes []string
+
+	for _, p := range f.Paragraphs {
+		for _, n := range p.Footnotes {
+			notes = append(notes, n.Content)
+		}
+	}
+
+	return notes
+}
+
+// Text returns the paragraphs joined by newlines.
+func (f *Fragment) Text() string {
+	text := ""
+
+	for i, p := range f.Paragraphs {
+		if i > 0 {
+			text += "\n"
+		}
+
+		text += p.Text
+	}
+
+	return text
+}
+
+// textLen returns the length of s in UTF-16 code units, the unit every
+// Docs API index is counted in.
+func textLen(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
@@ -0,0 +1,236 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// monospaceFont is used for inline code and fenced code blocks.
+const monospaceFont = "Courier New"
+
+func codeStyle() docs.TextStyle {
+	return docs.TextStyle{WeightedFontFamily: &docs.WeightedFontFamily{FontFamily: monospaceFont}}
+}
+
+// inlineResult accumulates the plain text of a paragraph and the spans
+// styling it. Offsets are UTF-16 code units. Labels are the footnote
+// labels referenced.
+type inlineResult struct {
+	text      strings.Builder
+	length    int
+	spans     []Span
+	footnotes []Footnote
+	labels    []string
+	notes     map[string]string
+	warnings  []string
+}
+
+// parseInline strips inline Markdown from s: **bold**, __bold__, *italic*,
+// _italic_, ~~strikethrough~~, `code`, [links](url), ![images](url) and
+// [^label] references to the footnotes in notes. Unclosed delimiters and
+// references to undefined footnotes are kept as literal text.
+func parseInline(s string, notes map[string]string) *inlineResult {
+	r := &inlineResult{notes: notes}
+	r.parse(s, docs.TextStyle{})
+
+	return r
+}
+
+func (r *inlineResult) parse(s string, style docs.TextStyle) {
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && unicode.IsPunct(rune(rest[1])):
+			r.emit(rest[1:2], style)
+			i += 2
+		case rest[0] == '`':
+			end := strings.IndexByte(rest[1:], '`')
+			if end < 0 {
+				r.emit("`", style)
+				i++
+
+				continue
+			}
+
+			code := style
+			code.WeightedFontFamily = codeStyle().WeightedFontFamily
+			r.emit(rest[1:1+end], code)
+			i += end + 2
+		case strings.HasPrefix(rest, "**"), strings.HasPrefix(rest, "__"):
+			i += r.delimited(rest, rest[:2], style, func(st *docs.TextStyle) { st.Bold = true })
+		case strings.HasPrefix(rest, "~~"):
+			i += r.delimited(rest, "~~", style, func(st *docs.TextStyle) { st.Strikethrough = true })
+		case rest[0] == '*' || (rest[0] == '_' && (i == 0 || !isWordByte(s[i-1]))):
+			i += r.delimited(rest, rest[:1], style, func(st *docs.TextStyle) { st.Italic = true })
+		case strings.HasPrefix(rest, "!["):
+			if n, text, url, ok := link(rest[1:]); ok {
+				r.warn(WarningImage)
+				r.emitLink(text, url, style)
+				i += n + 1
+
+				continue
+			}
+
+			r.emit("!", style)
+			i++
+		case rest[0] == '[':
+			if n, ok := r.footnote(rest); ok {
+				i += n
+
+				continue
+			}
+
+			if n, text, url, ok := link(rest); ok {
+				r.emitLink(text, url, style)
+				i += n
+
+				continue
+			}
+
+			r.emit("[", style)
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			r.emit(rest[:size], style)
+			i += size
+		}
+	}
+}
+
+// delimited handles text wrapped in delim, returning the number of
+// bytes consumed. Without a closing delimiter the opener is literal.
+func (r *inlineResult) delimited(rest, delim string, style docs.TextStyle, apply func(*docs.TextStyle)) int {
+	end := strings.Index(rest[len(delim):], delim)
+	if end <= 0 {
+		r.emit(delim, style)
+
+		return len(delim)
+	}
+
+	inner := style
+	apply(&inner)
+	r.parse(rest[len(delim):len(delim)+end], inner)
+
+	return end + 2*len(delim)
+}
+
+func (r *inlineResult) emitLink(text, url string, style docs.TextStyle) {
+	linked := style
+	linked.Link = &docs.Link{URL: url}
+
+	if text == "" {
+		text = url
+	}
+
+	r.parse(text, linked)
+}
+
+// emit appends text in style, extending the previous span when the
+// style is unchanged.
+func (r *inlineResult) emit(text string, style docs.TextStyle) {
+	n := textLen(text)
+	start := r.length
+
+	r.text.WriteString(text)
+	r.length += n
+
+	fields := styleFields(style)
+	if fields == "" || n == 0 {
+		return
+	}
+
+	if last := len(r.spans) - 1; last >= 0 && r.spans[last].End == start && reflect.DeepEqual(r.spans[last].Style, style) {
+		r.spans[last].End = r.length
+
+		return
+	}
+
+	r.spans = append(r.spans, Span{Start: start, End: r.length, Style: style, Fields: fields})
+}
+
+func (r *inlineResult) warn(warning string) {
+	for _, w := range r.warnings {
+		if w == warning {
+			return
+		}
+	}
+
+	r.warnings = append(r.warnings, warning)
+}
+
+// footnote records a "[^label]" reference to a defined footnote at the
+// start of s and returns the number of bytes consumed.
+func (r *inlineResult) footnote(s string) (int, bool) {
+	end := strings.IndexByte(s, ']')
+	if !strings.HasPrefix(s, "[^") || end <= 2 {
+		return 0, false
+	}
+
+	content, ok := r.notes[s[2:end]]
+	if !ok {
+		return 0, false
+	}
+
+	r.footnotes = append(r.footnotes, Footnote{Offset: r.length, Content: content})
+	r.labels = append(r.labels, s[2:end])
+
+	return end + 1, true
+}
+
+// link parses "[text](url)" at the start of s and returns the number of
+// bytes consumed.
+func link(s string) (int, string, string, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return 0, "", "", false
+	}
+
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return 0, "", "", false
+	}
+
+	closeURL := strings.IndexByte(s[closeText+2:], ')')
+	if closeURL < 0 {
+		return 0, "", "", false
+	}
+
+	url := strings.TrimSpace(s[closeText+2 : closeText+2+closeURL])
+
+	return closeText + 3 + closeURL, s[1:closeText], url, true
+}
+
+// styleFields returns the field mask of the attributes style sets.
+func styleFields(style docs.TextStyle) string {
+	var fields []string
+
+	if style.Bold {
+		fields = append(fields, "bold")
+	}
+
+	if style.Italic {
+		fields = append(fields, "italic")
+	}
+
+	if style.Strikethrough {
+		fields = append(fields, "strikethrough")
+	}
+
+	if style.WeightedFontFamily != nil {
+		fields = append(fields, "weightedFontFamily")
+	}
+
+	if style.Link != nil {
+		fields = append(fields, "link")
+	}
+
+	return strings.Join(fields, ",")
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,262 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// Warnings emitted for Markdown the converter cannot represent natively.
+const (
+	WarningTable          = "tables are not supported yet and were inserted as plain text"
+	WarningImage          = "images are not supported yet and were inserted as links"
+	WarningHorizontalRule = "horizontal rules are not supported and were skipped"
+	WarningUnusedFootnote = "footnote definitions without a reference were skipped"
+)
+
+// maxListLevel is the deepest nesting level Google Docs lists support.
+const maxListLevel = 8
+
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listPattern      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	quotePattern     = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	tableRowPattern  = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	tableSepPattern  = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*(:?-+:?\s*)?$`)
+	fencePattern     = regexp.MustCompile("^\\s*(```|~~~)")
+	footnotePattern  = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.*)$`)
+	headingStyleByID = []string{
+		docs.StyleHeading1, docs.StyleHeading2, docs.StyleHeading3,
+		docs.StyleHeading4, docs.StyleHeading5, docs.StyleHeading6,
+	}
+)
+
+// Parse converts Markdown into a Fragment. It understands ATX headings,
+// paragraphs, bullet and numbered lists (two spaces per nesting level),
+// block quotes, fenced code blocks, footnotes ("[^1]" references with
+// "[^1]: text" definitions) and the inline styles handled by
+// parseInline. Anything else is kept as literal text.
+func Parse(source string) *Fragment {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	lines, notes := footnoteDefinitions(lines)
+
+	p := &blockParser{fragment: &Fragment{}, notes: notes, referenced: make(map[string]bool)}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			i = p.codeBlock(lines, i+1, m[1])
+
+			continue
+		}
+
+		p.line(line)
+	}
+
+	p.flush()
+
+	if len(p.referenced) < len(notes) {
+		p.warn(WarningUnusedFootnote)
+	}
+
+	return p.fragment
+}
+
+// footnoteDefinitions takes the "[^label]: text" lines outside code
+// blocks out of lines and returns the remaining lines and the text of
+// each label. Lines indented by two spaces or more continue the
+// definition above them.
+func footnoteDefinitions(lines []string) ([]string, map[string]string) {
+	var (
+		out   []string
+		fence string
+		label string
+	)
+
+	notes := make(map[string]string)
+
+	for _, line := range lines {
+		if m := fencePattern.FindStringSubmatch(line); m != nil && (fence == "" || m[1] == fence) {
+			if fence == "" {
+				fence = m[1]
+			} else {
+				fence = ""
+			}
+		}
+
+		switch m := footnotePattern.FindStringSubmatch(line); {
+		case fence != "":
+			label = ""
+		case m != nil:
+			label = m[1]
+			notes[label] = strings.TrimSpace(m[2])
+
+			continue
+		case label != "" && strings.TrimSpace(line) != "" && strings.HasPrefix(strings.ReplaceAll(line, "\t", "  "), "  "):
+			notes[label] += " " + strings.TrimSpace(line)
+
+			continue
+		default:
+			label = ""
+		}
+
+		out = append(out, line)
+	}
+
+	return out, notes
+}
+
+type blockParser struct {
+	fragment   *Fragment
+	open       *rawParagraph
+	warned     map[string]bool
+	notes      map[string]string
+	referenced map[string]bool
+}
+
+// rawParagraph collects the source text of a paragraph before inline
+// parsing, so continuation lines can be joined first.
+type rawParagraph struct {
+	text  string
+	style string
+	list  *List
+	quote bool
+}
+
+func (p *blockParser) line(line string) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		p.flush()
+	case headingPattern.MatchString(line):
+		m := headingPattern.FindStringSubmatch(line)
+		p.flush()
+		p.open = &rawParagraph{text: m[2], style: headingStyleByID[len(m[1])-1]}
+		p.flush()
+	case rulePattern.MatchString(line):
+		p.flush()
+		p.warn(WarningHorizontalRule)
+	case tableRowPattern.MatchString(line):
+		p.flush()
+		p.warn(WarningTable)
+
+		if !tableSepPattern.MatchString(line) {
+			p.open = &rawParagraph{text: tableRow(line), style: docs.StyleNormalText}
+			p.flush()
+		}
+	case listPattern.MatchString(line):
+		m := listPattern.FindStringSubmatch(line)
+		p.flush()
+
+		level := len(strings.ReplaceAll(m[1], "\t", "  ")) / 2
+		if level > maxListLevel {
+			level = maxListLevel
+		}
+
+		ordered := m[2] != "-" && m[2] != "*" && m[2] != "+"
+		p.open = &rawParagraph{
+			text:  m[3],
+			style: docs.StyleNormalText,
+			list:  &List{Ordered: ordered, Level: level},
+		}
+	case quotePattern.MatchString(line):
+		text := quotePattern.FindStringSubmatch(line)[1]
+		if p.open != nil && p.open.quote {
+			p.open.text += " " + text
+
+			return
+		}
+
+		p.flush()
+		p.open = &rawParagraph{text: text, style: docs.StyleNormalText, quote: true}
+	default:
+		text := strings.TrimSpace(line)
+		if p.open != nil && !p.open.quote {
+			p.open.text += " " + text
+
+			return
+		}
+
+		p.flush()
+		p.open = &rawParagraph{text: text, style: docs.StyleNormalText}
+	}
+}
+
+// codeBlock emits every line up to the closing fence as a monospaced
+// paragraph and returns the index of the fence line.
+func (p *blockParser) codeBlock(lines []string, start int, fence string) int {
+	p.flush()
+
+	i := start
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			break
+		}
+
+		text := lines[i]
+		para := Paragraph{Text: text, NamedStyle: docs.StyleNormalText}
+
+		if n := textLen(text); n > 0 {
+			para.Spans = []Span{{Start: 0, End: n, Style: codeStyle(), Fields: "weightedFontFamily"}}
+		}
+
+		p.fragment.Paragraphs = append(p.fragment.Paragraphs, para)
+	}
+
+	return i
+}
+
+func (p *blockParser) flush() {
+	if p.open == nil {
+		return
+	}
+
+	raw := p.open
+	p.open = nil
+
+	inline := parseInline(raw.text, p.notes)
+	for _, w := range inline.warnings {
+		p.warn(w)
+	}
+
+	for _, label := range inline.labels {
+		p.referenced[label] = true
+	}
+
+	p.fragment.Paragraphs = append(p.fragment.Paragraphs, Paragraph{
+		Text:       inline.text.String(),
+		NamedStyle: raw.style,
+		List:       raw.list,
+		Quote:      raw.quote,
+		Spans:      inline.spans,
+		Footnotes:  inline.footnotes,
+	})
+}
+
+func (p *blockParser) warn(warning string) {
+	if p.warned == nil {
+		p.warned = make(map[string]bool)
+	}
+
+	if p.warned[warning] {
+		return
+	}
+
+	p.warned[warning] = true
+	p.fragment.Warnings = append(p.fragment.Warnings, warning)
+}
+
+// tableRow flattens a Markdown table row into "a | b | c".
+func tableRow(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(strings.TrimSuffix(line, "|"), "|")
+
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+
+	return strings.Join(cells, " | ")
+}
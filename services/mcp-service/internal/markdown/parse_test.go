@@ -0,0 +1,115 @@
+package markdown_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/markdown"
+)
+
+func TestORPHAN_Parse_BlocksMapToParagraphStyles(t *testing.T) {
+	// Act
+	fragment := markdown.Parse("# Title\n\nFirst line\nsame paragraph\n\n- item\n  - nested\n1. numbered\n> quoted")
+
+	// Assert
+	require.Len(t, fragment.Paragraphs, 6)
+	assert.Equal(t, docs.StyleHeading1, fragment.Paragraphs[0].NamedStyle)
+	assert.Equal(t, "First line same paragraph", fragment.Paragraphs[1].Text)
+	assert.Equal(t, &markdown.List{Ordered: false, Level: 0}, fragment.Paragraphs[2].List)
+	assert.Equal(t, &markdown.List{Ordered: false, Level: 1}, fragment.Paragraphs[3].List)
+	assert.Equal(t, &markdown.List{Ordered: true, Level: 0}, fragment.Paragraphs[4].List)
+	assert.True(t, fragment.Paragraphs[5].Quote)
+	assert.Empty(t, fragment.Warnings)
+}
+
+func TestORPHAN_Parse_InlineStylesBecomeSpans(t *testing.T) {
+	// Act
+	fragment := markdown.Parse("a **bold** _it_ `code` [link](https://example.com) é~~x~~")
+
+	// Assert
+	require.Len(t, fragment.Paragraphs, 1)
+
+	p := fragment.Paragraphs[0]
+	assert.Equal(t, "a bold it code link éx", p.Text)
+	require.Len(t, p.Spans, 5)
+	assert.Equal(t, markdown.Span{Start: 2, End: 6, Style: docs.TextStyle{Bold: true}, Fields: "bold"}, p.Spans[0])
+	assert.Equal(t, "italic", p.Spans[1].Fields)
+	assert.Equal(t, "weightedFontFamily", p.Spans[2].Fields)
+	assert.Equal(t, "https://example.com", p.Spans[3].Style.Link.URL)
+	assert.Equal(t, 21, p.Spans[4].Start)
+}
+
+func TestORPHAN_Parse_UnclosedDelimiters_StayLiteral(t *testing.T) {
+	// Act
+	fragment := markdown.Parse("2 * 3 and snake_case_name")
+
+	// Assert
+	require.Len(t, fragment.Paragraphs, 1)
+	assert.Equal(t, "2 * 3 and snake_case_name", fragment.Paragraphs[0].Text)
+	assert.Empty(t, fragment.Paragraphs[0].Spans)
+}
+
+func TestORPHAN_Parse_Tables_DegradeWithWarning(t *testing.T) {
+	// Act
+	fragment := markdown.Parse("| a | b |\n|---|---|\n| 1 | 2 |")
+
+	// Assert
+	require.Len(t, fragment.Paragraphs, 2)
+	assert.Equal(t, "a | b", fragment.Paragraphs[0].Text)
+	assert.Equal(t, []string{markdown.WarningTable}, fragment.Warnings)
+}
+
+func TestORPHAN_Fragment_Requests_NestedListTabsShrinkInsertedLength(t *testing.T) {
+	// Arrange
+	fragment := markdown.Parse("- a\n  - b")
+
+	// Act
+	requests, inserted := fragment.Requests("", 1, markdown.Placement{TrailingNewline: true})
+
+	// Assert
+	require.NotEmpty(t, requests)
+	assert.Equal(t, "a\n\tb\n", requests[0].InsertText.Text)
+	assert.Equal(t, 4, inserted)
+	assert.NotNil(t, requests[len(requests)-1].CreateParagraphBullets)
+}
+
+func TestORPHAN_Parse_FootnoteReferences_BecomeFootnotes(t *testing.T) {
+	// Act
+	fragment := markdown.Parse("Claim[^src] and [^none].\n\n[^src]: Annual **report**,\n  page 4.\n[^extra]: Unused.")
+
+	// Assert
+	require.Len(t, fragment.Paragraphs, 1)
+	p := fragment.Paragraphs[0]
+	assert.Equal(t, "Claim and [^none].", p.Text)
+	assert.Equal(t, []markdown.Footnote{{Offset: 5, Content: "Annual **report**, page 4."}}, p.Footnotes)
+	assert.Equal(t, []string{markdown.WarningUnusedFootnote}, fragment.Warnings)
+}
+
+func TestORPHAN_Fragment_Requests_CreateFootnotesAfterListTabsAreGone(t *testing.T) {
+	// Arrange
+	store := docs.NewMemoryStore(false)
+	store.Create("doc", "Test")
+	fragment := markdown.Parse("- a[^1]\n  - b[^2]\n\n[^1]: One.\n[^2]: Two.")
+
+	// Act
+	requests, inserted := fragment.Requests("", 1, markdown.Placement{})
+	resp, err := store.BatchUpdate(context.Background(), "doc", requests, nil)
+	require.NoError(t, err)
+	ids := resp.CreatedIDs("createFootnote")
+	_, err = store.BatchUpdate(context.Background(), "doc", markdown.FootnoteRequests(ids, fragment.Footnotes()), nil)
+
+	// Assert
+	require.NoError(t, err)
+	doc, err := store.Get(context.Background(), "doc")
+	require.NoError(t, err)
+	ph := string(docs.Placeholder)
+	assert.Equal(t, "a"+ph+"\nb"+ph+"\n", doc.Text())
+	assert.Equal(t, 5, inserted)
+	require.Len(t, ids, 2)
+	assert.Equal(t, " One.\n", doc.SegmentText(ids[0]))
+	assert.Equal(t, " Two.\n", doc.SegmentText(ids[1]))
+}
@@ -0,0 +1,277 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// quoteIndent is the left indent applied to block quotes, in points.
+const quoteIndent = 36
+
+// resetTextFields clears every attribute the converter can set, so block
+// content does not inherit the style of the text it was inserted next to.
+const resetTextFields = "bold,italic,underline,strikethrough,weightedFontFamily,link"
+
+// styleTextFields are the attributes a Placement style carries over.
+const styleTextFields = "bold,italic,underline,strikethrough,fontSize,weightedFontFamily,foregroundColor,link"
+
+// Placement describes how a fragment joins the surrounding paragraphs.
+//
+// Block content inserted at the start of a paragraph needs a trailing
+// newline so it ends before that paragraph; content added after the
+// last paragraph of a segment needs a leading newline because the final
+// newline of a segment cannot be moved. Content spliced into a
+// paragraph, or replacing a segment's whole text, needs neither.
+//
+// Splice marks content that replaces text inside a paragraph: a single
+// plain paragraph then keeps the surrounding text and paragraph style
+// instead of being reset to the fragment's own. Style, when set, is the
+// text style of what a splice replaces: plain text takes it on, while
+// text the Markdown formats replaces the attributes Markdown can express
+// and keeps the font size and color.
+type Placement struct {
+	LeadingNewline  bool
+	TrailingNewline bool
+	Splice          bool
+	Style           *docs.TextStyle
+}
+
+// Requests returns the batchUpdate requests that insert f at index of
+// segmentID, and the number of code units the document grows by once
+// they are applied. Every index in the requests refers to the document
+// as it is after the preceding requests, which is what batchUpdate
+// expects. Footnotes are created empty, each adding one unit for its
+// reference; FootnoteRequests fills them in afterwards.
+func (f *Fragment) Requests(segmentID string, index int, placement Placement) ([]docs.Request, int) {
+	if f.Empty() {
+		return nil, 0
+	}
+
+	text := textWithTabs(f, placement)
+	block := !placement.Splice || !f.Inline()
+
+	textRange := func(start, end int) *docs.Range {
+		return &docs.Range{SegmentID: segmentID, StartIndex: start, EndIndex: end}
+	}
+
+	reqs := []docs.Request{{InsertText: &docs.InsertTextRequest{
+		Text:     text,
+		Location: &docs.Location{SegmentID: segmentID, Index: index},
+	}}}
+
+	// Tabs marking list nesting are inserted now and removed again by
+	// createParagraphBullets, so offsets below account for them.
+	starts := make([]int, len(f.Paragraphs))
+	lengths := make([]int, len(f.Paragraphs))
+	pos := index
+	tabs := 0
+
+	if placement.LeadingNewline {
+		pos++
+	}
+
+	for i, p := range f.Paragraphs {
+		lengths[i] = textLen(p.Text)
+
+		if p.List != nil {
+			lengths[i] += p.List.Level
+			tabs += p.List.Level
+		}
+
+		starts[i] = pos
+		pos += lengths[i] + 1
+	}
+
+	inserted := textLen(text)
+
+	inherited := !block && placement.Style != nil
+
+	switch {
+	case block:
+		reqs = append(reqs, docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     textRange(index, index+inserted),
+			TextStyle: &docs.TextStyle{},
+			Fields:    resetTextFields,
+		}})
+	case inherited:
+		style := *placement.Style
+		reqs = append(reqs, docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     textRange(index, index+inserted),
+			TextStyle: &style,
+			Fields:    styleTextFields,
+		}})
+	}
+
+	for i, p := range f.Paragraphs {
+		offset := starts[i]
+		if p.List != nil {
+			offset += p.List.Level
+		}
+
+		for _, span := range p.Spans {
+			style, fields := span.Style, span.Fields
+			if inherited {
+				fields = resetTextFields
+			}
+
+			reqs = append(reqs, docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Range:     textRange(offset+span.Start, offset+span.End),
+				TextStyle: &style,
+				Fields:    fields,
+			}})
+		}
+	}
+
+	notes := f.footnoteRequests(segmentID, starts)
+
+	if !block {
+		return append(reqs, notes...), inserted + len(notes)
+	}
+
+	// paragraphRange covers at least one unit of paragraph i, which is
+	// all updateParagraphStyle and the bullet requests need.
+	paragraphRange := func(i int) (int, int) {
+		return starts[i], starts[i] + max(lengths[i], 1)
+	}
+
+	for i, p := range f.Paragraphs {
+		style := &docs.ParagraphStyle{NamedStyleType: p.NamedStyle}
+		if p.Quote {
+			style.IndentStart = &docs.Dimension{Magnitude: quoteIndent, Unit: "PT"}
+		}
+
+		start, end := paragraphRange(i)
+		reqs = append(reqs, docs.Request{UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Range:          textRange(start, end),
+			ParagraphStyle: style,
+			Fields:         "namedStyleType,indentStart",
+		}})
+	}
+
+	first, _ := paragraphRange(0)
+	_, last := paragraphRange(len(f.Paragraphs) - 1)
+	reqs = append(reqs, docs.Request{DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+		Range: textRange(first, last),
+	}})
+
+	// Lists are created back to front: removing nesting tabs shifts the
+	// text after each list, but never the lists still to be created.
+	groups := listGroups(f.Paragraphs)
+	for g := len(groups) - 1; g >= 0; g-- {
+		start, _ := paragraphRange(groups[g].first)
+		_, end := paragraphRange(groups[g].last)
+
+		preset := docs.BulletPresetDisc
+		if groups[g].ordered {
+			preset = docs.BulletPresetNumbers
+		}
+
+		reqs = append(reqs, docs.Request{CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			Range:        textRange(start, end),
+			BulletPreset: preset,
+		}})
+	}
+
+	return append(reqs, notes...), inserted - tabs + len(notes)
+}
+
+// footnoteRequests creates the footnotes of f. They are requested last,
+// once list tabs are gone, so a reference lands at its paragraph's start
+// in starts less the tabs of the paragraphs before, plus one unit for
+// every reference created ahead of it.
+func (f *Fragment) footnoteRequests(segmentID string, starts []int) []docs.Request {
+	var (
+		reqs []docs.Request
+		tabs int
+	)
+
+	for i, p := range f.Paragraphs {
+		for _, n := range p.Footnotes {
+			reqs = append(reqs, docs.Request{CreateFootnote: &docs.CreateFootnoteRequest{
+				Location: &docs.Location{SegmentID: segmentID, Index: starts[i] - tabs + n.Offset + len(reqs)},
+			}})
+		}
+
+		if p.List != nil {
+			tabs += p.List.Level
+		}
+	}
+
+	return reqs
+}
+
+// FootnoteRequests returns the requests that write contents, Markdown
+// as returned by Fragment.Footnotes, into the footnotes footnoteIDs that
+// createFootnote made for them. A new footnote holds a space and a
+// newline; the content goes after the space.
+func FootnoteRequests(footnoteIDs, contents []string) []docs.Request {
+	var reqs []docs.Request
+
+	for i, id := range footnoteIDs {
+		if i >= len(contents) {
+			break
+		}
+
+		note, _ := Parse(contents[i]).Requests(id, 1, Placement{Splice: true})
+		reqs = append(reqs, note...)
+	}
+
+	return reqs
+}
+
+// textWithTabs renders the inserted text, prefixing list items with one
+// tab per nesting level.
+func textWithTabs(f *Fragment, placement Placement) string {
+	var b strings.Builder
+
+	if placement.LeadingNewline {
+		b.WriteByte('\n')
+	}
+
+	for i, p := range f.Paragraphs {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		if p.List != nil {
+			b.WriteString(strings.Repeat("\t", p.List.Level))
+		}
+
+		b.WriteString(p.Text)
+	}
+
+	if placement.TrailingNewline {
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+type listGroup struct {
+	first, last int
+	ordered     bool
+}
+
+// listGroups returns runs of consecutive list items whose top-level
+// kind (bulleted or numbered) matches.
+func listGroups(paragraphs []Paragraph) []listGroup {
+	var groups []listGroup
+
+	for i, p := range paragraphs {
+		if p.List == nil {
+			continue
+		}
+
+		n := len(groups) - 1
+		if n >= 0 && groups[n].last == i-1 && groups[n].ordered == p.List.Ordered {
+			groups[n].last = i
+
+			continue
+		}
+
+		groups = append(groups, listGroup{first: i, last: i, ordered: p.List.Ordered})
+	}
+
+	return groups
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "mcp"
+
+// Metrics owns every Prometheus collector exported by mcp-service. It is
+// built once at startup and registered against the supplied Registerer,
+// so tests can use an isolated prometheus.Registry.
+type Metrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	toolCalls       *prometheus.CounterVec
+	toolDuration    *prometheus.HistogramVec
+	jsonRPCErrors   *prometheus.CounterVec
+	sessionsCreated prometheus.Counter
+	sessionsExpired prometheus.Counter
+	sseDropped      prometheus.Counter
+	googleDuration  *prometheus.HistogramVec
+	editLockWait    *prometheus.HistogramVec
+	registerer      prometheus.Registerer
+}
+
+// New creates and registers the collectors.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "JSON-RPC requests handled, by method.",
+		}, []string{"method"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "JSON-RPC request latency, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_total",
+			Help:      "tools/call invocations, by tool and outcome (success, tool_error, rpc_error).",
+		}, []string{"tool", "outcome"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "tools/call latency, by tool.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool"}),
+		jsonRPCErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jsonrpc_errors_total",
+			Help:      "JSON-RPC error responses, by error code.",
+		}, []string{"code"}),
+		sessionsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sessions_created_total",
+			Help:      "MCP sessions created.",
+		}),
+		sessionsExpired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sessions_expired_total",
+			Help:      "MCP sessions removed after exceeding the idle timeout.",
+		}),
+		sseDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sse_dropped_messages_total",
+			Help:      "Server-to-client messages dropped because the session SSE queue was full.",
+		}),
+		googleDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "google_api_request_duration_seconds",
+			Help:      "Google API call latency, by endpoint and HTTP status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "status"}),
+		editLockWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "edit_lock_wait_seconds",
+			Help:      "Time edits waited for their document's edit lock, by outcome (acquired, timeout, queue_full, error).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+		registerer: reg,
+	}
+
+	reg.MustRegister(
+		m.requests, m.requestDuration,
+		m.toolCalls, m.toolDuration,
+		m.jsonRPCErrors,
+		m.sessionsCreated, m.sessionsExpired,
+		m.sseDropped,
+		m.googleDuration,
+		m.editLockWait,
+	)
+
+	return m
+}
+
+// RegisterPoolGauges exposes point-in-time session pool readings. The
+// callbacks are evaluated on every scrape.
+func (m *Metrics) RegisterPoolGauges(active, sseStreams, sseQueueDepth func() float64) {
+	m.registerer.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sessions_active",
+			Help:      "MCP sessions currently held in the session pool.",
+		}, active),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sse_streams_active",
+			Help:      "Open SSE streams.",
+		}, sseStreams),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sse_queue_depth",
+			Help:      "Messages waiting in SSE channels across all sessions.",
+		}, sseQueueDepth),
+	)
+}
+
+// RegisterEditLockGauge exposes how many edits hold or wait for a
+// document edit lock on this replica.
+func (m *Metrics) RegisterEditLockGauge(depth func() float64) {
+	m.registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "edit_lock_queue_depth",
+		Help:      "Edits holding or waiting for a per-document edit lock.",
+	}, depth))
+}
+
+// ObserveRequest records one JSON-RPC request.
+func (m *Metrics) ObserveRequest(method string, elapsed time.Duration) {
+	m.requests.WithLabelValues(method).Inc()
+	m.requestDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
+
+// ObserveToolCall records one tools/call invocation.
+func (m *Metrics) ObserveToolCall(tool, outcome string, elapsed time.Duration) {
+	m.toolCalls.WithLabelValues(tool, outcome).Inc()
+	m.toolDuration.WithLabelValues(tool).Observe(elapsed.Seconds())
+}
+
+// IncJSONRPCError counts one JSON-RPC error response.
+func (m *Metrics) IncJSONRPCError(code int) {
+	m.jsonRPCErrors.WithLabelValues(strconv.Itoa(code)).Inc()
+}
+
+// SessionCreated counts a new session.
+func (m *Metrics) SessionCreated() {
+	m.sessionsCreated.Inc()
+}
+
+// SessionExpired counts a session reaped for inactivity.
+func (m *Metrics) SessionExpired() {
+	m.sessionsExpired.Inc()
+}
+
+// SSEMessageDropped counts a message that could not be queued.
+func (m *Metrics) SSEMessageDropped() {
+	m.sseDropped.Inc()
+}
+
+// ObserveGoogleAPI records one outbound Google API call.
+func (m *Metrics) ObserveGoogleAPI(endpoint, status string, elapsed time.Duration) {
+	m.googleDuration.WithLabelValues(endpoint, status).Observe(elapsed.Seconds())
+}
+
+// ObserveEditLockWait records how long one edit waited for its lock.
+func (m *Metrics) ObserveEditLockWait(outcome string, elapsed time.Duration) {
+	m.editLockWait.WithLabelValues(outcome).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,86 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/metrics"
+)
+
+func TestORPHAN_Metrics_RecordsToolCallsAndErrors(t *testing.T) {
+	// Arrange
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	m.RegisterPoolGauges(
+		func() float64 { return 3 },
+		func() float64 { return 1 },
+		func() float64 { return 7 },
+	)
+
+	// Act
+	m.ObserveToolCall("append", "success", 10*time.Millisecond)
+	m.ObserveToolCall("append", "rpc_error", 10*time.Millisecond)
+	m.IncJSONRPCError(-32602)
+	m.SessionCreated()
+
+	// Assert
+	expected := `
+# HELP mcp_jsonrpc_errors_total JSON-RPC error responses, by error code.
+# TYPE mcp_jsonrpc_errors_total counter
+mcp_jsonrpc_errors_total{code="-32602"} 1
+# HELP mcp_sse_queue_depth Messages waiting in SSE channels across all sessions.
+# TYPE mcp_sse_queue_depth gauge
+mcp_sse_queue_depth 7
+# HELP mcp_tool_calls_total tools/call invocations, by tool and outcome (success, tool_error, rpc_error).
+# TYPE mcp_tool_calls_total counter
+mcp_tool_calls_total{outcome="rpc_error",tool="append"} 1
+mcp_tool_calls_total{outcome="success",tool="append"} 1
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"mcp_jsonrpc_errors_total", "mcp_sse_queue_depth", "mcp_tool_calls_total")
+	require.NoError(t, err)
+}
+
+func TestORPHAN_RoundTripper_LabelsByEndpointAndStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	client := &http.Client{Transport: metrics.NewRoundTripper(nil, metrics.New(reg))}
+
+	// Act
+	resp, err := client.Get(server.URL + "/v1/documents/abc123:batchUpdate")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Assert
+	count := testutil.CollectAndCount(reg, "mcp_google_api_request_duration_seconds")
+	assert.Equal(t, 1, count)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	var labels []string
+	for _, family := range families {
+		if family.GetName() != "mcp_google_api_request_duration_seconds" {
+			continue
+		}
+
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels = append(labels, label.GetName()+"="+label.GetValue())
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"endpoint=documents.batchUpdate", "status=404"}, labels)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RoundTripper times outbound Google API calls and records them under a
+// low-cardinality endpoint label. Document IDs never reach the label.
+type RoundTripper struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+// NewRoundTripper wraps next (http.DefaultTransport when nil).
+func NewRoundTripper(next http.RoundTripper, m *Metrics) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RoundTripper{next: next, metrics: m}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	t.metrics.ObserveGoogleAPI(GoogleEndpoint(req), status, time.Since(start))
+
+	return resp, err
+}
+
+// GoogleEndpoint maps a request onto a stable API method name such as
+// "documents.get", "documents.batchUpdate", "drive.permissions" or
+// "oauth2.token". Unrecognised requests fall back to the host name.
+func GoogleEndpoint(req *http.Request) string {
+	path := req.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, "/token"):
+		return "oauth2.token"
+	case strings.Contains(path, "$discovery"):
+		return "discovery"
+	case strings.HasSuffix(path, ":batchUpdate"):
+		return "documents.batchUpdate"
+	case strings.Contains(path, "/documents/"):
+		return "documents.get"
+	case strings.Contains(path, "/permissions"):
+		return "drive.permissions"
+	case strings.Contains(path, "/files/"):
+		return "drive.files"
+	default:
+		return req.URL.Host
+	}
+}
@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"context"
+	"slices"
+)
+
+// Chooser picks which matches of an ambiguous anchor an operation acts
+// on, typically by asking the user. It is consulted for every anchored
+// operation that leaves occurrence at its default of all matches while
+// its anchor matches several places; op is the operation's index and
+// matches are previews numbered as the result lists them. Returning
+// OccurrenceAll keeps the default, and an error aborts the edit. A
+// chooser may let other edits of the document run while it waits, so an
+// edit that consulted one is only written if the document is still at
+// the revision it was planned against.
+type Chooser func(ctx context.Context, op int, anchor string, matches []MatchPreview) (Occurrence, error)
+
+// pinOnChoose makes consulting o.Choose pin the write to revisionID,
+// unless the caller already required a revision.
+func (o *Options) pinOnChoose(revisionID string) {
+	choose := o.Choose
+	o.Choose = func(ctx context.Context, op int, anchor string, matches []MatchPreview) (Occurrence, error) {
+		if o.RequiredRevisionID == "" {
+			o.RequiredRevisionID = revisionID
+		}
+
+		return choose(ctx, op, anchor, matches)
+	}
+}
+
+// choose asks chooser about the ambiguous operations of ops and returns
+// them with the occurrences chosen, and whether any changed.
+func (p *projection) choose(ctx context.Context, ops []Operation, matches [][]match, chooser Chooser) ([]Operation, bool, error) {
+	var chosen []Operation
+
+	for i, op := range ops {
+		occurrence, ok, err := p.chooseOne(ctx, i, op, op.Mode, matches[i], chooser)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if chosen == nil {
+			chosen = slices.Clone(ops)
+		}
+
+		chosen[i].Occurrence = occurrence
+	}
+
+	if chosen == nil {
+		return ops, false, nil
+	}
+
+	return chosen, true, nil
+}
+
+// chooseOne asks chooser about operation index when it is ambiguous and
+// reports whether another occurrence was chosen.
+func (p *projection) chooseOne(ctx context.Context, index int, op Operation, mode Mode, matches []match, chooser Chooser) (Occurrence, bool, error) {
+	if !op.anchored() || !op.Occurrence.all() || len(matches) < 2 {
+		return Occurrence{}, false, nil
+	}
+
+	occurrence, err := chooser(ctx, index, op.AnchorText, p.previews(index, mode, matches))
+	if err != nil {
+		return Occurrence{}, false, err
+	}
+
+	return occurrence, !occurrence.all(), nil
+}
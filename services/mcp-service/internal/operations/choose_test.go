@@ -0,0 +1,121 @@
+package operations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+var errUserDeclined = errors.New("user declined")
+
+// pickSecond is a Chooser that records what it was asked and picks the
+// second match.
+func pickSecond(asked *[][]operations.MatchPreview) operations.Chooser {
+	return func(_ context.Context, _ int, _ string, matches []operations.MatchPreview) (operations.Occurrence, error) {
+		*asked = append(*asked, matches)
+
+		return operations.Occurrence{Indexes: []int{2}}, nil
+	}
+}
+
+func TestORPHAN_Editor_Chooser_PicksAmbiguousMatch(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo one.\n\nFoo two.")
+	var asked [][]operations.MatchPreview
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "foo", Content: "Bar"},
+	}, operations.Options{Choose: pickSecond(&asked)})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, "Foo", asked[0][0].Text)
+	assert.Equal(t, " two.", asked[0][1].ContextAfter)
+	assert.Equal(t, 1, result.MatchesChanged)
+	assert.Equal(t, "Foo one.\nBar two.\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_Chooser_SkipsUnambiguousOperations(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo one.\n\nFoo two.\n\nBaz.")
+	var asked [][]operations.MatchPreview
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "foo", Content: "Bar", Occurrence: operations.OccurrenceLast},
+		{Mode: operations.ModeReplaceMatch, AnchorText: "baz", Content: "Qux"},
+		{Mode: operations.ModeAppend, Content: "End"},
+	}, operations.Options{Choose: pickSecond(&asked)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, asked)
+	assert.Equal(t, "Foo one.\nBar two.\nQux.\nEnd\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_ChooserError_AppliesNothing(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo one.\n\nFoo two.")
+	before := get(t, store)
+	decline := func(context.Context, int, string, []operations.MatchPreview) (operations.Occurrence, error) {
+		return operations.Occurrence{}, errUserDeclined
+	}
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertAfter, AnchorText: "foo", Content: "Inserted"},
+	}, operations.Options{Choose: decline})
+
+	// Assert
+	require.ErrorIs(t, err, errUserDeclined)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_DocumentChangedWhileChoosing_AppliesNothing(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo one.\n\nFoo two.")
+	concurrentEdit := func(ctx context.Context, _ int, _ string, _ []operations.MatchPreview) (operations.Occurrence, error) {
+		_, err := editor.Apply(ctx, docID, []operations.Operation{
+			{Mode: operations.ModeAppend, Content: "Meanwhile"},
+		}, operations.Options{})
+
+		return operations.Occurrence{Indexes: []int{2}}, err
+	}
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "foo", Content: "Bar"},
+	}, operations.Options{Choose: concurrentEdit})
+
+	// Assert
+	var mismatch *operations.RevisionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "Foo one.\nFoo two.\nMeanwhile\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_FormatChooser_PicksAmbiguousMatch(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo one.\n\nFoo two.")
+	bold := true
+	var asked [][]operations.MatchPreview
+
+	// Act
+	result, err := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "foo"},
+		operations.Format{Bold: &bold}, operations.Options{Choose: pickSecond(&asked)})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, 1, result.MatchesChanged)
+	runs := get(t, store).Body.Content[2].Paragraph.Elements
+	assert.True(t, runs[0].TextRun.TextStyle.Bold)
+	assert.Equal(t, "Foo", runs[0].TextRun.Content)
+}
@@ -0,0 +1,211 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// ChunkLimits bounds each batchUpdate an edit is sent in. The Docs API
+// rejects oversized requests, so larger edits are split into several
+// batchUpdates. A zero field leaves that dimension unbounded.
+type ChunkLimits struct {
+	// MaxRequests is the number of requests per batchUpdate.
+	MaxRequests int
+	// MaxBytes is the JSON size of the requests in one batchUpdate. An
+	// insertText larger than this is split into several inserts.
+	MaxBytes int
+}
+
+// DefaultChunkLimits keep every batchUpdate well below the Docs API
+// request size cap.
+var DefaultChunkLimits = ChunkLimits{MaxRequests: 500, MaxBytes: 2 << 20}
+
+// insertOverhead is room left for the rest of an insertText request
+// when its text is split to fit MaxBytes.
+const insertOverhead = 256
+
+// Chunk is one batchUpdate of an edit that was split. It holds requests
+// [FirstRequest, FirstRequest+Requests) of the chunked request list and,
+// once applied, the revision the document reached.
+type Chunk struct {
+	FirstRequest int    `json:"first_request"`
+	Requests     int    `json:"requests"`
+	Applied      bool   `json:"applied"`
+	RevisionID   string `json:"revisionId,omitempty"`
+}
+
+// PartialEditError reports an edit split into chunks that failed after
+// some of them were written. Exactly the chunks marked Applied are in
+// the document, which is now at RevisionID; Snapshot is the document as
+// it was before the edit, so the change to Segment can still be reverted.
+type PartialEditError struct {
+	Chunks     []Chunk
+	Applied    int
+	RevisionID string
+	Segment    string
+	Snapshot   *docs.Document
+	Err        error
+}
+
+func (e *PartialEditError) Error() string {
+	return fmt.Sprintf("edit partially applied: %d of %d chunks written (requests 0-%d of %d): %v",
+		e.Applied, len(e.Chunks), e.appliedRequests()-1, e.totalRequests(), e.Err)
+}
+
+// Unwrap returns the error that stopped the edit.
+func (e *PartialEditError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PartialEditError) appliedRequests() int {
+	n := 0
+	for _, c := range e.Chunks[:e.Applied] {
+		n += c.Requests
+	}
+
+	return n
+}
+
+func (e *PartialEditError) totalRequests() int {
+	n := 0
+	for _, c := range e.Chunks {
+		n += c.Requests
+	}
+
+	return n
+}
+
+// chunkRequests splits requests into consecutive batches within limits.
+// batchUpdate applies requests in order and each index refers to the
+// document after the requests before it, so consecutive batches sent one
+// after another leave every index valid.
+func chunkRequests(requests []docs.Request, limits ChunkLimits) [][]docs.Request {
+	requests = splitInserts(requests, limits.MaxBytes)
+
+	var (
+		chunks [][]docs.Request
+		cur    []docs.Request
+		size   int
+	)
+
+	for _, req := range requests {
+		n := requestSize(req)
+
+		full := limits.MaxRequests > 0 && len(cur) >= limits.MaxRequests
+		if limits.MaxBytes > 0 && size+n > limits.MaxBytes {
+			full = true
+		}
+
+		if full && len(cur) > 0 {
+			chunks = append(chunks, cur)
+			cur, size = nil, 0
+		}
+
+		cur = append(cur, req)
+		size += n
+	}
+
+	if len(cur) > 0 {
+		chunks = append(chunks, cur)
+	}
+
+	return chunks
+}
+
+// planChunks describes batches, none of them applied yet.
+func planChunks(batches [][]docs.Request) []Chunk {
+	chunks := make([]Chunk, len(batches))
+	first := 0
+
+	for i, batch := range batches {
+		chunks[i] = Chunk{FirstRequest: first, Requests: len(batch)}
+		first += len(batch)
+	}
+
+	return chunks
+}
+
+// splitInserts replaces every insertText too large for maxBytes with
+// inserts of consecutive pieces of its text. Each piece goes where the
+// previous one ended, so the document, and every later index, is the
+// same as after the single insert.
+func splitInserts(requests []docs.Request, maxBytes int) []docs.Request {
+	if maxBytes <= insertOverhead {
+		return requests
+	}
+
+	var out []docs.Request
+
+	for _, req := range requests {
+		if req.InsertText == nil || req.InsertText.Location == nil || requestSize(req) <= maxBytes {
+			out = append(out, req)
+
+			continue
+		}
+
+		loc := *req.InsertText.Location
+		for _, piece := range splitText(req.InsertText.Text, maxBytes-insertOverhead) {
+			pieceLoc := loc
+			out = append(out, docs.Request{InsertText: &docs.InsertTextRequest{Text: piece, Location: &pieceLoc}})
+			loc.Index += len(utf16.Encode([]rune(piece)))
+		}
+	}
+
+	return out
+}
+
+// splitText cuts text into pieces of at most maxBytes encoded, at rune
+// boundaries. It prefers not to cut right after a newline: a piece that
+// starts a paragraph would take the style of the text after it instead
+// of continuing the text before it.
+func splitText(text string, maxBytes int) []string {
+	var pieces []string
+
+	for len(text) > 0 {
+		cut := 0
+		for cut < len(text) {
+			_, size := utf8.DecodeRuneInString(text[cut:])
+			if cut > 0 && encodedLen(text[:cut+size]) > maxBytes {
+				break
+			}
+
+			cut += size
+		}
+
+		if cut < len(text) {
+			for back := cut; back > cut/2; {
+				r, size := utf8.DecodeLastRuneInString(text[:back])
+				if r != '\n' {
+					cut = back
+
+					break
+				}
+
+				back -= size
+			}
+		}
+
+		pieces = append(pieces, text[:cut])
+		text = text[cut:]
+	}
+
+	return pieces
+}
+
+// encodedLen is the length of s as a JSON string, which escapes
+// quotes, control characters and some runes.
+func encodedLen(s string) int {
+	raw, _ := json.Marshal(s)
+
+	return len(raw)
+}
+
+func requestSize(req docs.Request) int {
+	raw, _ := json.Marshal(req)
+
+	return len(raw) + 1
+}
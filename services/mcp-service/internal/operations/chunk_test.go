@@ -0,0 +1,167 @@
+package operations_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+var errBatchRejected = errors.New("batch rejected")
+
+// failingStore rejects every batchUpdate after the first ok ones.
+type failingStore struct {
+	*docs.MemoryStore
+	ok int
+}
+
+func (s *failingStore) BatchUpdate(ctx context.Context, documentID string, requests []docs.Request, writeControl *docs.WriteControl) (*docs.BatchUpdateResponse, error) {
+	if s.ok == 0 {
+		return nil, errBatchRejected
+	}
+	s.ok--
+
+	return s.MemoryStore.BatchUpdate(ctx, documentID, requests, writeControl)
+}
+
+const largeContent = "# Report\n\nBody **bold** and *italic* text.\n\n- one\n- two\n\n" +
+	"## Details\n\n1. first\n2. second\n\nClosing paragraph with `code` in it."
+
+func TestORPHAN_Editor_Chunked_MatchesSingleBatch(t *testing.T) {
+	// Arrange
+	content := largeContent + "\n\n" + strings.Repeat("Long text é 😀 \"quoted\". ", 40)
+	single, singleEditor := seed(t, "Existing")
+	chunked, chunkedEditor := seed(t, "Existing")
+	chunkedEditor.SetChunkLimits(operations.ChunkLimits{MaxRequests: 3, MaxBytes: 600})
+	current := get(t, chunked).RevisionID
+
+	// Act
+	_, err := singleEditor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: content},
+	}, operations.Options{})
+	require.NoError(t, err)
+	result, err := chunkedEditor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: content},
+	}, operations.Options{RequiredRevisionID: current})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, get(t, single).Body, get(t, chunked).Body)
+	require.Greater(t, len(result.Chunks), 2)
+	for _, chunk := range result.Chunks {
+		assert.True(t, chunk.Applied)
+		assert.LessOrEqual(t, chunk.Requests, 3)
+	}
+	assert.Equal(t, get(t, chunked).RevisionID, result.RevisionID)
+}
+
+func TestORPHAN_Editor_Chunked_DryRunPlansChunks(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Existing")
+	editor.SetChunkLimits(operations.ChunkLimits{MaxRequests: 3})
+	before := get(t, store)
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: largeContent},
+	}, operations.Options{DryRun: true})
+
+	// Assert
+	require.NoError(t, err)
+	require.Greater(t, len(result.Chunks), 1)
+	total := 0
+	for _, chunk := range result.Chunks {
+		assert.Equal(t, total, chunk.FirstRequest)
+		assert.False(t, chunk.Applied)
+		total += chunk.Requests
+	}
+	assert.Equal(t, len(result.Requests), total)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_ChunkFails_ReportsAppliedPortion(t *testing.T) {
+	// Arrange
+	memory, _ := seed(t, "Existing")
+	store := &failingStore{MemoryStore: memory, ok: 1}
+	editor := operations.NewEditor(store)
+	editor.SetChunkLimits(operations.ChunkLimits{MaxRequests: 2})
+	before := get(t, memory)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: largeContent},
+	}, operations.Options{})
+
+	// Assert
+	var partial *operations.PartialEditError
+	require.ErrorAs(t, err, &partial)
+	require.ErrorIs(t, err, errBatchRejected)
+	assert.Equal(t, 1, partial.Applied)
+	assert.True(t, partial.Chunks[0].Applied)
+	assert.False(t, partial.Chunks[1].Applied)
+	assert.Equal(t, get(t, memory).RevisionID, partial.RevisionID)
+	assert.Equal(t, before, partial.Snapshot)
+	assert.NotEqual(t, before.Text(), get(t, memory).Text())
+}
+
+func TestORPHAN_Editor_FirstChunkFails_IsNotPartial(t *testing.T) {
+	// Arrange
+	memory, _ := seed(t, "Existing")
+	editor := operations.NewEditor(&failingStore{MemoryStore: memory})
+	editor.SetChunkLimits(operations.ChunkLimits{MaxRequests: 2})
+	before := get(t, memory)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: largeContent},
+	}, operations.Options{})
+
+	// Assert
+	var partial *operations.PartialEditError
+	require.ErrorIs(t, err, errBatchRejected)
+	assert.False(t, errors.As(err, &partial))
+	assert.Equal(t, before, get(t, memory))
+}
+
+func TestORPHAN_Editor_AtomicTooLarge_AppliesNothing(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Existing")
+	editor.SetChunkLimits(operations.ChunkLimits{MaxRequests: 2})
+	before := get(t, store)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: largeContent},
+	}, operations.Options{Atomic: true})
+	_, dryErr := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: largeContent},
+	}, operations.Options{Atomic: true, DryRun: true})
+
+	// Assert
+	require.ErrorIs(t, err, operations.ErrEditTooLarge)
+	require.ErrorIs(t, dryErr, operations.ErrEditTooLarge)
+	var partial *operations.PartialEditError
+	assert.NotErrorAs(t, err, &partial)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_AtomicFootnote_IsRejected(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Existing")
+	before := get(t, store)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "Revenue grew[^1].\n\n[^1]: Audited figures."},
+	}, operations.Options{Atomic: true})
+
+	// Assert
+	require.ErrorIs(t, err, operations.ErrInvalidOperation)
+	assert.Equal(t, before, get(t, store))
+}
@@ -0,0 +1,113 @@
+package operations
+
+import (
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// maxDiffCells bounds the LCS table of lineDiff. Larger changes are
+// reported as one hunk covering everything between the common prefix
+// and suffix.
+const maxDiffCells = 4_000_000
+
+// DiffHunk is one changed region of a dry run. Paragraph numbers are
+// 1-based positions of the first paragraph of the region in the
+// document before and after the edit.
+type DiffHunk struct {
+	BeforeParagraph int      `json:"before_paragraph"`
+	AfterParagraph  int      `json:"after_paragraph"`
+	Before          []string `json:"before"`
+	After           []string `json:"after"`
+}
+
+// paragraphTexts splits a document into paragraph texts, dropping the
+// placeholders that stand in for non-text elements.
+func paragraphTexts(doc *docs.Document, segmentID string) []string {
+	text := strings.ReplaceAll(doc.SegmentText(segmentID), string(docs.Placeholder), "")
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// lineDiff compares two paragraph lists and returns the changed regions.
+func lineDiff(before, after []string) []DiffHunk {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	a := before[prefix : len(before)-suffix]
+	b := after[prefix : len(after)-suffix]
+
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	if len(a)*len(b) > maxDiffCells {
+		return []DiffHunk{{BeforeParagraph: prefix + 1, AfterParagraph: prefix + 1, Before: a, After: b}}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var hunks []DiffHunk
+
+	var open *DiffHunk
+
+	flush := func() {
+		if open != nil {
+			hunks = append(hunks, *open)
+			open = nil
+		}
+	}
+
+	start := func(i, j int) *DiffHunk {
+		if open == nil {
+			open = &DiffHunk{BeforeParagraph: prefix + i + 1, AfterParagraph: prefix + j + 1}
+		}
+
+		return open
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			h := start(i, j)
+			h.After = append(h.After, b[j])
+			j++
+		default:
+			h := start(i, j)
+			h.Before = append(h.Before, a[i])
+			i++
+		}
+	}
+
+	flush()
+
+	return hunks
+}
@@ -0,0 +1,556 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/markdown"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// Editor runs edit operations against a Store. Every call fetches one
+// snapshot, resolves all anchors in it, converts the Markdown content
+// and sends a single batchUpdate, so a set of operations either applies
+// completely or not at all. Edits too large for one batchUpdate are split
+// into chained ones unless Options.Atomic is set.
+type Editor struct {
+	store  docs.Store
+	limits ChunkLimits
+}
+
+// NewEditor returns an Editor writing through store in batchUpdates
+// within DefaultChunkLimits.
+func NewEditor(store docs.Store) *Editor {
+	return &Editor{store: store, limits: DefaultChunkLimits}
+}
+
+// SetChunkLimits changes how large edits are split into batchUpdates.
+// It must be called before the Editor is used.
+func (e *Editor) SetChunkLimits(limits ChunkLimits) {
+	e.limits = limits
+}
+
+// Options adjust how Apply runs.
+type Options struct {
+	// DryRun runs the whole pipeline but sends nothing; the result then
+	// carries the planned requests, the matched ranges and a diff.
+	DryRun bool
+	// RequiredRevisionID, when set, rejects the edit unless the document
+	// is still at that revision. It is checked against the snapshot and
+	// sent as writeControl, so changes made after the fetch are caught too.
+	RequiredRevisionID string
+	// Choose, when set, is asked which matches to act on for anchors that
+	// match several places without an explicit occurrence.
+	Choose Chooser
+	// Segment is the part of the document edited: the body when empty,
+	// "header", "footer" or a header, footer or footnote ID.
+	Segment string
+	// TabID is the tab a document link pointed into. Only the first tab,
+	// which is the body, can be edited; any other tab is rejected.
+	TabID string
+	// Atomic refuses edits that need more than one batchUpdate instead of
+	// splitting them, so the edit is applied completely or not at all.
+	Atomic bool
+}
+
+// Result is the structured outcome of an edit, in the shape of the
+// design document's output schema. Matches lists every match with its
+// surrounding context, so a follow-up call can pick one by occurrence.
+// Operations is only filled for multi-operation requests; the fields
+// after it only for dry runs. Chunks is only filled when the edit was
+// too large for one batchUpdate. Segment is the segment ID edited, empty
+// for the body. Snapshot is the document the edit was planned against.
+type Result struct {
+	Type           string            `json:"type"`
+	DocumentID     string            `json:"docId"`
+	RevisionID     string            `json:"revisionId,omitempty"`
+	MatchesFound   int               `json:"matches_found"`
+	MatchesChanged int               `json:"matches_changed"`
+	PreviewURL     string            `json:"preview_url"`
+	Warnings       []string          `json:"warnings"`
+	Matches        []MatchPreview    `json:"matches,omitempty"`
+	Operations     []OperationResult `json:"operations,omitempty"`
+	DryRun         bool              `json:"dry_run,omitempty"`
+	Requests       []docs.Request    `json:"requests,omitempty"`
+	Diff           []DiffHunk        `json:"diff,omitempty"`
+	Chunks         []Chunk           `json:"chunks,omitempty"`
+	Segment        string            `json:"segment,omitempty"`
+	Snapshot       *docs.Document    `json:"-"`
+
+	// footnoteIDs are the footnotes the edit created, in request order.
+	footnoteIDs []string
+}
+
+// OperationResult reports the matches of one operation of a batch.
+type OperationResult struct {
+	Mode           Mode `json:"mode"`
+	MatchesFound   int  `json:"matches_found"`
+	MatchesChanged int  `json:"matches_changed"`
+}
+
+// target is where an operation acts, before content is attached.
+type target struct {
+	start     int
+	end       int
+	placement markdown.Placement
+	intoEmpty bool
+}
+
+// Apply executes ops in order against documentID.
+func (e *Editor) Apply(ctx context.Context, documentID string, ops []Operation, opts Options) (*Result, error) {
+	for i, op := range ops {
+		if err := op.Validate(); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	doc, err := e.fetch(ctx, documentID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentID, err := resolveSegment(doc, opts.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartStage(ctx, "resolve_anchors", attribute.Int("mcp.operations", len(ops)))
+	proj := projectSegment(doc, segmentID)
+	targets, matches, err := resolve(proj, ops)
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Choose != nil {
+		opts.pinOnChoose(doc.RevisionID)
+		var changed bool
+		if ops, changed, err = proj.choose(ctx, ops, matches, opts.Choose); err != nil {
+			return nil, err
+		}
+
+		if changed {
+			if targets, matches, err = resolve(proj, ops); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, span = tracing.StartStage(ctx, "convert_markdown")
+	fragments := make([]*markdown.Fragment, len(ops))
+	for i, op := range ops {
+		fragments[i] = markdown.Parse(op.Content)
+	}
+	tracing.EndStage(span, nil)
+
+	_, span = tracing.StartStage(ctx, "plan_requests")
+	p := &planner{segmentID: segmentID}
+	for i := range ops {
+		for _, t := range targets[i] {
+			err = p.add(edit{
+				op: i, start: t.start, end: t.end,
+				content: fragments[i], placement: t.placement, intoEmpty: t.intoEmpty,
+			})
+			if err != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
+	span.SetAttributes(attribute.Int("mcp.requests", len(p.requests)))
+	tracing.EndStage(span, err)
+
+	if err == nil && segmentID != "" && len(p.footnotes) > 0 {
+		err = fmt.Errorf("%w: footnotes can only be added to the body", ErrInvalidOperation)
+	}
+
+	if err == nil && opts.Atomic && len(p.footnotes) > 0 {
+		err = fmt.Errorf("%w: footnote text needs a second batchUpdate, so footnotes cannot be added atomically",
+			ErrInvalidOperation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := buildResult(documentID, proj, ops, targets, matches, fragments)
+	result.RevisionID = doc.RevisionID
+	result.Segment = segmentID
+	result.Snapshot = doc
+
+	if err := e.send(ctx, result, doc, p.requests, opts); err != nil {
+		return nil, err
+	}
+
+	if !opts.DryRun && len(p.footnotes) > 0 {
+		if err := e.fillFootnotes(ctx, result, doc, len(p.requests), p.footnotes); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// fillFootnotes writes contents into the footnotes the edit created.
+// Their IDs are only known once the edit is applied, so the content
+// follows in one more batchUpdate chained to the edit's revision; when
+// it fails the edit, sent in requests, is reported as partially applied.
+func (e *Editor) fillFootnotes(ctx context.Context, result *Result, doc *docs.Document, sent int, contents []string,
+) error {
+	requests := markdown.FootnoteRequests(result.footnoteIDs, contents)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	chunks := result.Chunks
+	if len(chunks) == 0 {
+		chunks = []Chunk{{Requests: sent, Applied: true, RevisionID: result.RevisionID}}
+	}
+	last := chunks[len(chunks)-1]
+	chunks = append(chunks, Chunk{FirstRequest: last.FirstRequest + last.Requests, Requests: len(requests)})
+
+	next, _, err := e.batchUpdate(ctx, result.DocumentID, requests, result.RevisionID)
+	if err != nil {
+		return &PartialEditError{
+			Chunks: chunks, Applied: len(chunks) - 1, RevisionID: result.RevisionID,
+			Segment: result.Segment, Snapshot: doc, Err: err,
+		}
+	}
+
+	result.RevisionID = next
+
+	return nil
+}
+
+// fetch reads the snapshot an edit is planned against and checks it is
+// at the required revision.
+func (e *Editor) fetch(ctx context.Context, documentID string, opts Options) (*docs.Document, error) {
+	fetchCtx, span := tracing.StartStage(ctx, "fetch_document", attribute.String("mcp.document_id", documentID))
+	doc, err := e.store.Get(fetchCtx, documentID)
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return nil, fmt.Errorf("fetch document: %w", err)
+	}
+
+	if opts.RequiredRevisionID != "" && doc.RevisionID != opts.RequiredRevisionID {
+		return nil, &RevisionMismatchError{Required: opts.RequiredRevisionID, Current: doc.RevisionID}
+	}
+
+	if opts.TabID != "" && len(doc.Tabs) > 0 && doc.Tabs[0].TabProperties.TabID != opts.TabID {
+		return nil, fmt.Errorf("%w: tab %s is not the first tab of the document; only the first tab can be read or edited",
+			ErrInvalidOperation, opts.TabID)
+	}
+
+	return doc, nil
+}
+
+// send writes requests and records the new revision in result, or for
+// a dry run fills in the preview instead.
+func (e *Editor) send(ctx context.Context, result *Result, doc *docs.Document, requests []docs.Request, opts Options) error {
+	batches := chunkRequests(requests, e.limits)
+	if opts.Atomic && len(batches) > 1 {
+		return fmt.Errorf("%w: %d requests need %d batchUpdates", ErrEditTooLarge, len(requests), len(batches))
+	}
+
+	if opts.DryRun {
+		if len(batches) > 1 {
+			result.Chunks = planChunks(batches)
+		}
+
+		return preview(ctx, result, doc, requests)
+	}
+
+	if len(requests) > 0 {
+		return e.sendChunks(ctx, result, doc, batches, opts.RequiredRevisionID)
+	}
+
+	return nil
+}
+
+// sendChunks writes the batches the chunk limits split an edit into, one
+// batchUpdate each. Each chunk after the first requires the revision the previous
+// one produced, so no other write can land between chunks and shift the
+// indexes the later ones were planned with. A failure after the first
+// chunk is reported as a PartialEditError.
+func (e *Editor) sendChunks(ctx context.Context, result *Result, doc *docs.Document, batches [][]docs.Request, revision string) error {
+	chunks := planChunks(batches)
+	if len(batches) > 1 {
+		result.Chunks = chunks
+	}
+
+	for i, batch := range batches {
+		next, resp, err := e.batchUpdate(ctx, result.DocumentID, batch, revision)
+		if err != nil {
+			if i == 0 {
+				return err
+			}
+
+			return &PartialEditError{
+				Chunks: chunks, Applied: i, RevisionID: revision, Segment: result.Segment, Snapshot: doc, Err: err,
+			}
+		}
+
+		if resp != nil {
+			result.footnoteIDs = append(result.footnoteIDs, resp.CreatedIDs("createFootnote")...)
+		}
+		revision = next
+		chunks[i].Applied = true
+		chunks[i].RevisionID = next
+	}
+
+	result.RevisionID = revision
+
+	return nil
+}
+
+// batchUpdate sends requests and returns the revision they produced with
+// the response. A rejected writeControl is turned into a
+// RevisionMismatchError carrying the revision the document has moved on
+// to.
+func (e *Editor) batchUpdate(ctx context.Context, documentID string, requests []docs.Request, requiredRevisionID string,
+) (string, *docs.BatchUpdateResponse, error) {
+	var writeControl *docs.WriteControl
+	if requiredRevisionID != "" {
+		writeControl = &docs.WriteControl{RequiredRevisionID: requiredRevisionID}
+	}
+
+	updateCtx, span := tracing.StartStage(ctx, "batch_update", attribute.Int("mcp.requests", len(requests)))
+	resp, err := e.store.BatchUpdate(updateCtx, documentID, requests, writeControl)
+	tracing.EndStage(span, err)
+
+	switch {
+	case errors.Is(err, docs.ErrRevisionMismatch):
+		mismatch := &RevisionMismatchError{Required: requiredRevisionID}
+		if doc, getErr := e.store.Get(ctx, documentID); getErr == nil {
+			mismatch.Current = doc.RevisionID
+		}
+
+		return "", nil, mismatch
+	case err != nil:
+		return "", nil, fmt.Errorf("batch update: %w", err)
+	case resp.WriteControl != nil:
+		return resp.WriteControl.RequiredRevisionID, resp, nil
+	default:
+		return "", resp, nil
+	}
+}
+
+// preview fills the dry-run fields of result by replaying requests on a
+// copy of the snapshot.
+func preview(ctx context.Context, result *Result, doc *docs.Document, requests []docs.Request) error {
+	_, span := tracing.StartStage(ctx, "simulate", attribute.Int("mcp.requests", len(requests)))
+
+	before, err := docs.Simulate(doc, nil)
+	if err == nil {
+		var after *docs.Document
+
+		after, err = docs.Simulate(doc, requests)
+		if err == nil {
+			result.Diff = lineDiff(paragraphTexts(before, result.Segment), paragraphTexts(after, result.Segment))
+		}
+	}
+
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return fmt.Errorf("simulate edit: %w", err)
+	}
+
+	result.DryRun = true
+	result.Requests = requests
+
+	return nil
+}
+
+func buildResult(documentID string, p *projection, ops []Operation, targets [][]target, matches [][]match,
+	fragments []*markdown.Fragment,
+) *Result {
+	result := &Result{
+		Type:       "ok",
+		DocumentID: documentID,
+		PreviewURL: docs.PreviewURL(documentID),
+		Warnings:   []string{},
+		Matches:    []MatchPreview{},
+	}
+
+	seen := make(map[string]bool)
+
+	for i, op := range ops {
+		result.MatchesFound += len(matches[i])
+		result.MatchesChanged += len(targets[i])
+		result.Matches = append(result.Matches, p.previews(i, op.Mode, matches[i])...)
+
+		if len(ops) > 1 {
+			result.Operations = append(result.Operations, OperationResult{
+				Mode:           op.Mode,
+				MatchesFound:   len(matches[i]),
+				MatchesChanged: len(targets[i]),
+			})
+		}
+
+		for _, w := range fragments[i].Warnings {
+			if !seen[w] {
+				seen[w] = true
+				result.Warnings = append(result.Warnings, w)
+			}
+		}
+	}
+
+	return result
+}
+
+// resolve locates every operation's targets in the snapshot and returns
+// them with the ranges matched per operation, marking those its
+// occurrence selects. Operations without an anchor match the range they
+// act on, within their section if scoped.
+func resolve(p *projection, ops []Operation) ([][]target, [][]match, error) {
+	if len(p.paragraphs) == 0 {
+		return nil, nil, fmt.Errorf("%w: document has no body", ErrInvalidOperation)
+	}
+
+	targets := make([][]target, len(ops))
+	matches := make([][]match, len(ops))
+
+	for i, op := range ops {
+		var scope section
+
+		if op.Section != "" {
+			var err error
+			if scope, err = p.section(i, op.Section); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if !op.anchored() {
+			t := fixedTarget(p, op.Mode)
+			if op.Section != "" {
+				t = sectionTarget(p, scope, op.Mode)
+			}
+
+			targets[i] = []target{t}
+			matches[i] = []match{{start: t.start, end: t.end, selected: true}}
+
+			continue
+		}
+
+		var err error
+		if matches[i], err = p.anchorMatches(i, op, scope); err != nil {
+			return nil, nil, err
+		}
+
+		targets[i] = anchorTargets(p, op.Mode, matches[i])
+	}
+
+	return targets, matches, nil
+}
+
+// anchorMatches finds the anchor of op, operation index of the request,
+// inside scope when op is scoped, and marks the matches its occurrence
+// selects.
+func (p *projection) anchorMatches(index int, op Operation, scope section) ([]match, error) {
+	matches, err := p.find(op.AnchorText, op.matchOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Section != "" {
+		var inside []match
+
+		for _, m := range matches {
+			if scope.contains(m) {
+				inside = append(inside, m)
+			}
+		}
+
+		if len(inside) == 0 {
+			return nil, anchorNotFoundInSection(index, op.AnchorText, op.Section)
+		}
+
+		matches = inside
+	}
+
+	if len(matches) == 0 {
+		return nil, anchorNotFound(index, op.AnchorText)
+	}
+
+	if n, ok := op.Occurrence.pick(matches); !ok {
+		return nil, occurrenceNotFound(index, op.AnchorText, n, p.previews(index, op.Mode, matches))
+	}
+
+	return matches, nil
+}
+
+// fixedTarget places replace_all, prepend and append without an anchor.
+func fixedTarget(p *projection, mode Mode) target {
+	switch mode {
+	case ModeReplaceAll:
+		return target{start: p.first().start, end: p.bodyEnd - 1, intoEmpty: true}
+	case ModePrepend:
+		if first := p.first(); !first.empty || !first.last {
+			return target{start: first.start, end: first.start, placement: markdown.Placement{TrailingNewline: true}}
+		}
+	default:
+		if final := p.final(); !final.empty {
+			return target{start: p.bodyEnd - 1, end: p.bodyEnd - 1, placement: markdown.Placement{LeadingNewline: true}}
+		}
+	}
+
+	// The only place left is an empty final paragraph: fill it in place.
+	start := p.final().start
+
+	return target{start: start, end: start, intoEmpty: true}
+}
+
+// anchorTargets turns the selected matches into targets. Replacements
+// take on the text style where the match begins. Insertions land on
+// paragraph boundaries and are made once per paragraph even when the
+// anchor occurs in it several times.
+func anchorTargets(p *projection, mode Mode, matches []match) []target {
+	var targets []target
+
+	seen := make(map[int]bool)
+
+	for _, m := range matches {
+		if !m.selected {
+			continue
+		}
+
+		var t target
+
+		switch mode {
+		case ModeReplaceMatch:
+			t = target{start: m.start, end: m.end, placement: markdown.Placement{Splice: true, Style: p.styleAt(m.start)}}
+		case ModeInsertBefore:
+			para := p.paragraphAt(m.start)
+			t = target{start: para.start, end: para.start, placement: markdown.Placement{TrailingNewline: true}}
+		default:
+			para := p.paragraphAt(m.end - 1)
+			if para.last {
+				t = target{start: para.end - 1, end: para.end - 1, placement: markdown.Placement{LeadingNewline: true}}
+			} else {
+				t = target{start: para.end, end: para.end, placement: markdown.Placement{TrailingNewline: true}}
+			}
+		}
+
+		if mode != ModeReplaceMatch {
+			if seen[t.start] {
+				continue
+			}
+
+			seen[t.start] = true
+		}
+
+		targets = append(targets, t)
+	}
+
+	return targets
+}
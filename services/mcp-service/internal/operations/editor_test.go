@@ -0,0 +1,538 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+const docID = "doc-1"
+
+// seed creates docID in a fresh store with the given Markdown content.
+func seed(t *testing.T, content string) (*docs.MemoryStore, *operations.Editor) {
+	t.Helper()
+
+	store := docs.NewMemoryStore(false)
+	store.Create(docID, "Test")
+	editor := operations.NewEditor(store)
+
+	if content != "" {
+		_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+			{Mode: operations.ModeReplaceAll, Content: content},
+		}, operations.Options{})
+		require.NoError(t, err)
+	}
+
+	return store, editor
+}
+
+func get(t *testing.T, store *docs.MemoryStore) *docs.Document {
+	t.Helper()
+
+	doc, err := store.Get(context.Background(), docID)
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestORPHAN_Editor_ReplaceAll_WritesHeadingsAndParagraphs(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "old text\n\nmore")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceAll, Content: "# Report\n\nBody **bold** text."},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result.Type)
+	assert.Equal(t, 1, result.MatchesChanged)
+	assert.Equal(t, "https://docs.google.com/document/d/doc-1", result.PreviewURL)
+
+	doc := get(t, store)
+	assert.Equal(t, "Report\nBody bold text.\n", doc.Text())
+
+	content := doc.Body.Content
+	require.Len(t, content, 3)
+	assert.Equal(t, docs.StyleHeading1, content[1].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, docs.StyleNormalText, content[2].Paragraph.ParagraphStyle.NamedStyleType)
+
+	runs := content[2].Paragraph.Elements
+	require.Len(t, runs, 3)
+	assert.Equal(t, "bold", runs[1].TextRun.Content)
+	assert.True(t, runs[1].TextRun.TextStyle.Bold)
+	assert.False(t, runs[2].TextRun.TextStyle.Bold)
+}
+
+func TestORPHAN_Editor_PrependAndAppend_KeepOperationOrder(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModePrepend, Content: "first"},
+		{Mode: operations.ModePrepend, Content: "second"},
+		{Mode: operations.ModeAppend, Content: "third"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\nthird\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_Batch_ResolvesAnchorsAgainstSnapshot(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "# Intro\n\nDraft text here.\n\n## Conclusion\n\nThe end.")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertBefore, AnchorText: "conclusion", Content: "## Findings\n\n- one\n- two"},
+		{Mode: operations.ModeReplaceMatch, AnchorText: "Draft", Content: "Final"},
+		{Mode: operations.ModeInsertAfter, AnchorText: "The end.", Content: "Signed."},
+		{Mode: operations.ModePrepend, Content: "Summary first."},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Summary first.\nIntro\nFinal text here.\nFindings\none\ntwo\nConclusion\nThe end.\nSigned.\n",
+		get(t, store).Text())
+	require.Len(t, result.Operations, 4)
+	assert.Equal(t, 4, result.MatchesChanged)
+
+	content := get(t, store).Body.Content
+	assert.Equal(t, docs.StyleNormalText, content[1].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, docs.StyleHeading1, content[2].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, docs.StyleHeading2, content[4].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.NotNil(t, content[5].Paragraph.Bullet)
+	assert.NotNil(t, content[6].Paragraph.Bullet)
+	assert.Nil(t, content[9].Paragraph.Bullet)
+}
+
+func TestORPHAN_Editor_ReplaceMatch_ReplacesEveryMatchCaseInsensitively(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Foo and foo.\n\nFOO!")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "foo", Content: "bar"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.MatchesFound)
+	assert.Equal(t, 3, result.MatchesChanged)
+	assert.Equal(t, "bar and bar.\nbar!\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_AnchorNotFound_AppliesNothing(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Hello world")
+	before := get(t, store)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "added"},
+		{Mode: operations.ModeInsertAfter, AnchorText: "missing", Content: "x"},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeAnchorNotFound, opErr.Code)
+	assert.Equal(t, 1, opErr.Operation)
+	assert.Len(t, opErr.Hints, 3)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_OverlappingReplacements_Conflict(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "alpha beta gamma")
+	before := get(t, store)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "alpha beta", Content: "x"},
+		{Mode: operations.ModeReplaceMatch, AnchorText: "beta gamma", Content: "y"},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeConflictingOperations, opErr.Code)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_ReplaceAllThenAppend_AppendsAfterNewContent(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "old")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceAll, Content: "new"},
+		{Mode: operations.ModeAppend, Content: "tail"},
+		{Mode: operations.ModePrepend, Content: "head"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "head\nnew\ntail\n", get(t, store).Text())
+}
+
+func TestORPHAN_ParseMode_AcceptsAliases(t *testing.T) {
+	// Act
+	mode, err := operations.ParseMode("insertBefore")
+	_, unknownErr := operations.ParseMode("overwrite")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, operations.ModeInsertBefore, mode)
+	require.ErrorIs(t, unknownErr, operations.ErrInvalidOperation)
+}
+
+func TestORPHAN_Editor_DryRun_PreviewsWithoutWriting(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "# Intro\n\nDraft text here.\n\nUnchanged.")
+	before := get(t, store)
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "draft", Content: "Final"},
+		{Mode: operations.ModeAppend, Content: "Tail."},
+	}, operations.Options{DryRun: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, before, get(t, store))
+	assert.True(t, result.DryRun)
+	assert.NotEmpty(t, result.Requests)
+
+	require.Len(t, result.Matches, 2)
+	assert.Equal(t, "Draft", result.Matches[0].Text)
+	assert.Equal(t, "", result.Matches[0].ContextBefore)
+	assert.Equal(t, " text here.", result.Matches[0].ContextAfter)
+	assert.Equal(t, operations.ModeAppend, result.Matches[1].Mode)
+
+	assert.Equal(t, []operations.DiffHunk{
+		{BeforeParagraph: 2, AfterParagraph: 2, Before: []string{"Draft text here."}, After: []string{"Final text here."}},
+		{BeforeParagraph: 4, AfterParagraph: 4, Before: nil, After: []string{"Tail."}},
+	}, result.Diff)
+}
+
+func TestORPHAN_Editor_RequiredRevision_ReturnsNewRevision(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Hello")
+	current := get(t, store).RevisionID
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "World"},
+	}, operations.Options{RequiredRevisionID: current})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEqual(t, current, result.RevisionID)
+	assert.Equal(t, get(t, store).RevisionID, result.RevisionID)
+}
+
+func TestORPHAN_Editor_StaleRevision_ReportsCurrentRevision(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Hello")
+	stale := get(t, store).RevisionID
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "Concurrent edit"},
+	}, operations.Options{})
+	require.NoError(t, err)
+	before := get(t, store)
+
+	// Act
+	_, err = editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "Too late"},
+	}, operations.Options{RequiredRevisionID: stale})
+
+	// Assert
+	var mismatch *operations.RevisionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.ErrorIs(t, err, docs.ErrRevisionMismatch)
+	assert.Equal(t, stale, mismatch.Required)
+	assert.Equal(t, before.RevisionID, mismatch.Current)
+	assert.Equal(t, before, get(t, store))
+}
+
+func TestORPHAN_Editor_Restore_UndoesEdit(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "# Report\n\n- one\n- two\n\nClosing **words**.")
+	before := get(t, store)
+
+	edited, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: "two", Content: "## Changed"},
+	}, operations.Options{})
+	require.NoError(t, err)
+	require.NotEqual(t, before.Text(), get(t, store).Text())
+
+	// Act
+	result, err := editor.Restore(context.Background(), docID, edited.Snapshot,
+		operations.Options{RequiredRevisionID: edited.RevisionID})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchesChanged)
+
+	after := get(t, store)
+	assert.Equal(t, before.Text(), after.Text())
+	assert.Equal(t, after.RevisionID, result.RevisionID)
+
+	for i, el := range before.Body.Content {
+		if el.Paragraph == nil {
+			continue
+		}
+
+		restored := after.Body.Content[i].Paragraph
+		assert.Equal(t, el.Paragraph.ParagraphStyle.NamedStyleType, restored.ParagraphStyle.NamedStyleType)
+		assert.Equal(t, el.Paragraph.Bullet != nil, restored.Bullet != nil)
+		assert.Equal(t, len(el.Paragraph.Elements), len(restored.Elements))
+	}
+}
+
+func TestORPHAN_Editor_Restore_RefusesChangedDocument(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "text")
+	edited, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "more"},
+	}, operations.Options{})
+	require.NoError(t, err)
+
+	_, err = editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "someone else"},
+	}, operations.Options{})
+	require.NoError(t, err)
+
+	// Act
+	_, err = editor.Restore(context.Background(), docID, edited.Snapshot,
+		operations.Options{RequiredRevisionID: edited.RevisionID})
+
+	// Assert
+	var mismatch *operations.RevisionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "text\nmore\nsomeone else\n", get(t, store).Text())
+}
+
+const sectionedContent = "# Install\n\nintro\n\n## Linux\n\napt install\n\n## Mac\n\nbrew install\n\n# Usage\n\nrun it"
+
+func TestORPHAN_Editor_SectionReplaceAll_ReplacesOnlySectionBody(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, sectionedContent)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceAll, Section: "install >  LINUX", Content: "dnf install\n\nyum install"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+
+	doc := get(t, store)
+	assert.Equal(t, "Install\nintro\nLinux\ndnf install\nyum install\nMac\nbrew install\nUsage\nrun it\n", doc.Text())
+	assert.Equal(t, docs.StyleHeading2, doc.Body.Content[3].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, docs.StyleNormalText, doc.Body.Content[4].Paragraph.ParagraphStyle.NamedStyleType)
+	assert.Equal(t, docs.StyleHeading2, doc.Body.Content[6].Paragraph.ParagraphStyle.NamedStyleType)
+}
+
+func TestORPHAN_Editor_SectionAppendAndPrepend_StayInsideSection(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, sectionedContent)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Section: "Install", Content: "see also"},
+		{Mode: operations.ModePrepend, Section: "Usage", Content: "first"},
+		{Mode: operations.ModeAppend, Section: "Usage", Content: "last"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Install\nintro\nLinux\napt install\nMac\nbrew install\nsee also\nUsage\nfirst\nrun it\nlast\n",
+		get(t, store).Text())
+}
+
+func TestORPHAN_Editor_SectionAnchor_MatchesOnlyInsideSection(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, sectionedContent)
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, Section: "Mac", AnchorText: "install", Content: "upgrade"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchesFound)
+	assert.Equal(t, "Install\nintro\nLinux\napt install\nMac\nbrew upgrade\nUsage\nrun it\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_MissingSection_ListsOutline(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, sectionedContent)
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Section: "Usage > Linux", Content: "x"},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeSectionNotFound, opErr.Code)
+	assert.Equal(t, []string{"Install", "Install > Linux", "Install > Mac", "Usage"}, opErr.Outline)
+}
+
+func TestORPHAN_Editor_AmbiguousSection_IsRejected(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, "# Server\n\n## Linux\n\na\n\n# Client\n\n## Linux\n\nb")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Section: "Linux", Content: "x"},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeAmbiguousSection, opErr.Code)
+	assert.Contains(t, opErr.Outline, "Client > Linux")
+}
+
+func TestORPHAN_Editor_InsertAfterLastOccurrence_ListsEveryMatch(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Note one\n\nNote two\n\nNote three")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertAfter, Content: "added", AnchorText: "note", Occurrence: operations.OccurrenceLast},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Note one\nNote two\nNote three\nadded\n", get(t, store).Text())
+	assert.Equal(t, 3, result.MatchesFound)
+	assert.Equal(t, 1, result.MatchesChanged)
+	require.Len(t, result.Matches, 3)
+	assert.Equal(t, 2, result.Matches[1].Occurrence)
+	assert.False(t, result.Matches[1].Selected)
+	assert.Equal(t, " two", result.Matches[1].ContextAfter)
+	assert.True(t, result.Matches[2].Selected)
+}
+
+func TestORPHAN_Editor_ReplaceMatchOccurrenceList_ReplacesOnlyListedMatches(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "a x b x c x")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, Content: "Y", AnchorText: "x", Occurrence: operations.Occurrence{Indexes: []int{1, 3}}},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "a Y b x c Y\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_OccurrenceBeyondMatches_ListsMatches(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "x and x")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertBefore, Content: "new", AnchorText: "x", Occurrence: operations.Occurrence{Indexes: []int{3}}},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeOccurrenceNotFound, opErr.Code)
+	require.Len(t, opErr.Matches, 2)
+	assert.Equal(t, 2, opErr.Matches[1].Occurrence)
+	assert.Equal(t, "x and x\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_Anchor_IgnoresSmartQuotesAndNonBreakingSpaces(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Click “Save now” to continue.")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, Content: "Apply", AnchorText: `"save  now"`},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Click Apply to continue.\n", get(t, store).Text())
+	assert.InDelta(t, 1.0, result.Matches[0].Confidence, 0)
+}
+
+func TestORPHAN_Editor_Anchor_MatchesAcrossParagraphs(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Intro\n\nend of one\n\nstart of two\n\nOutro")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, Content: "joined", AnchorText: "of one start"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Intro\nend joined of two\nOutro\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_AnchorWithBulletGlyphs_MatchesListText(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "- alpha\n- beta\n\nAfter")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertBefore, Content: "Before", AnchorText: "• alpha\n• beta"},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Before\nalpha\nbeta\nAfter\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_FuzzyAnchorBelowThreshold_IsNotFound(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Other text.")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, Content: "x", AnchorText: "Other txt here", Fuzzy: true, MinSimilarity: 0.95},
+	}, operations.Options{})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeAnchorNotFound, opErr.Code)
+	assert.Equal(t, "Other text.\n", get(t, store).Text())
+}
+
+func TestORPHAN_Editor_FuzzyAnchor_MatchesMisspelledText(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Install the dependencies first.\n\nThen run it.")
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeInsertAfter, Content: "Check versions.", AnchorText: "Instal the dependancies first", Fuzzy: true},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "Install the dependencies first", result.Matches[0].Text)
+	assert.InDelta(t, 0.93, result.Matches[0].Confidence, 0.001)
+	assert.Equal(t, "Install the dependencies first.\nCheck versions.\nThen run it.\n", get(t, store).Text())
+}
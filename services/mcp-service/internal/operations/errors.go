@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// ErrInvalidOperation signals an operation with missing or unknown fields.
+var ErrInvalidOperation = errors.New("invalid operation")
+
+// ErrEditTooLarge signals an atomic edit too large for one batchUpdate.
+var ErrEditTooLarge = errors.New("edit too large for one atomic update")
+
+// Error codes reported to clients in structured tool errors.
+const (
+	CodeAnchorNotFound        = "ANCHOR_NOT_FOUND"
+	CodeConflictingOperations = "CONFLICTING_OPERATIONS"
+	CodeSectionNotFound       = "SECTION_NOT_FOUND"
+	CodeAmbiguousSection      = "AMBIGUOUS_SECTION"
+	CodeOccurrenceNotFound    = "OCCURRENCE_NOT_FOUND"
+	CodeSegmentNotFound       = "SEGMENT_NOT_FOUND"
+)
+
+// RevisionMismatchError reports that the document is no longer at the
+// revision the caller required. Current is empty when it could not be
+// determined.
+type RevisionMismatchError struct {
+	Required string
+	Current  string
+}
+
+func (e *RevisionMismatchError) Error() string {
+	return fmt.Sprintf("document is at revision %q, not the required %q", e.Current, e.Required)
+}
+
+// Unwrap lets callers match the error with docs.ErrRevisionMismatch.
+func (e *RevisionMismatchError) Unwrap() error {
+	return docs.ErrRevisionMismatch
+}
+
+// Hint is a follow-up action the client may offer the user.
+type Hint struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+}
+
+// anchorNotFoundHints are the recovery options from the design document.
+var anchorNotFoundHints = []Hint{
+	{Action: "insert_at_end", Label: "Insert at the end"},
+	{Action: "replace_all", Label: "Overwrite the entire document"},
+	{Action: "ask_user", Label: "Ask the user"},
+}
+
+// OperationError is an edit that cannot be planned against the current
+// document. Nothing has been written when it is returned. Operation is
+// the zero-based position of the failing operation within the request.
+// Section errors list the heading paths of the document in Outline;
+// occurrence errors list the anchor matches there are in Matches;
+// segment errors list the segments there are in Segments.
+type OperationError struct {
+	Code      string
+	Message   string
+	Operation int
+	Hints     []Hint
+	Outline   []string
+	Matches   []MatchPreview
+	Segments  []string
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %s: %s", e.Operation, e.Code, e.Message)
+}
+
+func anchorNotFound(index int, anchor string) *OperationError {
+	return &OperationError{
+		Code:      CodeAnchorNotFound,
+		Message:   fmt.Sprintf("Anchor '%s' not found in the document.", anchor),
+		Operation: index,
+		Hints:     anchorNotFoundHints,
+	}
+}
+
+func anchorNotFoundInSection(index int, anchor, path string) *OperationError {
+	err := anchorNotFound(index, anchor)
+	err.Message = fmt.Sprintf("Anchor '%s' not found in section '%s'.", anchor, path)
+
+	return err
+}
+
+func sectionError(index int, code, message string, outline []Heading) *OperationError {
+	paths := make([]string, len(outline))
+	for i, h := range outline {
+		paths[i] = h.Path
+	}
+
+	return &OperationError{
+		Code:      code,
+		Message:   message,
+		Operation: index,
+		Hints:     []Hint{{Action: "ask_user", Label: "Ask the user"}},
+		Outline:   paths,
+	}
+}
+
+func occurrenceNotFound(index int, anchor string, occurrence int, matches []MatchPreview) *OperationError {
+	return &OperationError{
+		Code: CodeOccurrenceNotFound,
+		Message: fmt.Sprintf("Anchor '%s' has %d matches; occurrence %d does not exist.",
+			anchor, len(matches), occurrence),
+		Operation: index,
+		Hints:     []Hint{{Action: "ask_user", Label: "Ask the user"}},
+		Matches:   matches,
+	}
+}
+
+func segmentNotFound(doc *docs.Document, message string) *OperationError {
+	return &OperationError{
+		Code:     CodeSegmentNotFound,
+		Message:  message,
+		Hints:    []Hint{{Action: "ask_user", Label: "Ask the user"}},
+		Segments: segments(doc),
+	}
+}
+
+func conflicting(index, earlier int) *OperationError {
+	return &OperationError{
+		Code: CodeConflictingOperations,
+		Message: fmt.Sprintf("operation %d targets content that operation %d replaces or deletes; "+
+			"anchors are resolved against the document before any operation is applied", index, earlier),
+		Operation: index,
+	}
+}
@@ -0,0 +1,417 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// modeFormat labels the matches of a Format call. It is not an
+// Operation mode.
+const modeFormat Mode = "format"
+
+// Bullet settings accepted by Format.
+const (
+	BulletsDisc     = "bulleted"
+	BulletsNumbered = "numbered"
+	BulletsNone     = "none"
+)
+
+// Named paragraph styles and alignments accepted by Format.
+var (
+	namedStyles = []string{
+		docs.StyleNormalText, docs.StyleTitle, docs.StyleSubtitle,
+		docs.StyleHeading1, docs.StyleHeading2, docs.StyleHeading3,
+		docs.StyleHeading4, docs.StyleHeading5, docs.StyleHeading6,
+	}
+	alignments = map[string]string{
+		"START": "START", "LEFT": "START", "CENTER": "CENTER", "END": "END", "RIGHT": "END", "JUSTIFIED": "JUSTIFIED",
+	}
+)
+
+// NamedStyles lists the paragraph styles Format accepts.
+func NamedStyles() []string {
+	return append([]string(nil), namedStyles...)
+}
+
+// Selection is the text Format restyles: the matches of AnchorText,
+// within Section when set, or else the body of Section. The matching
+// fields mean what they do on an Operation.
+type Selection struct {
+	AnchorText    string
+	CaseSensitive bool
+	Section       string
+	Occurrence    Occurrence
+	Fuzzy         bool
+	MinSimilarity float64
+}
+
+// operation is the Operation that locates the same text, for reusing
+// its validation and anchor matching.
+func (s Selection) operation() Operation {
+	op := Operation{
+		Mode: ModeReplaceMatch, AnchorText: s.AnchorText, CaseSensitive: s.CaseSensitive, Section: s.Section,
+		Occurrence: s.Occurrence, Fuzzy: s.Fuzzy, MinSimilarity: s.MinSimilarity,
+	}
+	if s.AnchorText == "" {
+		op.Mode = ModeReplaceAll
+	}
+
+	return op
+}
+
+// Format is a set of style changes. Nil fields are left as they are; an
+// empty Link, FontFamily or Color removes that style. Color is a
+// "#RRGGBB" value, FontSize is in points, and Bullets is one of
+// BulletsDisc, BulletsNumbered and BulletsNone.
+type Format struct {
+	Bold          *bool    `json:"bold,omitempty"`
+	Italic        *bool    `json:"italic,omitempty"`
+	Underline     *bool    `json:"underline,omitempty"`
+	Strikethrough *bool    `json:"strikethrough,omitempty"`
+	Link          *string  `json:"link,omitempty"`
+	FontFamily    *string  `json:"fontFamily,omitempty"`
+	FontSize      *float64 `json:"fontSize,omitempty"`
+	Color         *string  `json:"color,omitempty"`
+	NamedStyle    *string  `json:"namedStyle,omitempty"`
+	Alignment     *string  `json:"alignment,omitempty"`
+	Bullets       *string  `json:"bullets,omitempty"`
+}
+
+// Format applies format to the text sel selects without changing the
+// text itself: only style and bullet requests are sent. Text styles
+// cover the selected ranges; paragraph styles and bullets cover every
+// paragraph those ranges touch.
+func (e *Editor) Format(ctx context.Context, documentID string, sel Selection, format Format, opts Options) (*Result, error) {
+	op := sel.operation()
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	if sel.AnchorText == "" && strings.TrimSpace(sel.Section) == "" {
+		return nil, fmt.Errorf("%w: format requires anchorText or section", ErrInvalidOperation)
+	}
+
+	text, textFields, err := format.textStyle()
+	if err != nil {
+		return nil, err
+	}
+
+	para, paraFields, err := format.paragraphStyle()
+	if err != nil {
+		return nil, err
+	}
+
+	preset, err := format.bulletPreset()
+	if err != nil {
+		return nil, err
+	}
+
+	if textFields == "" && paraFields == "" && format.Bullets == nil {
+		return nil, fmt.Errorf("%w: format sets no style", ErrInvalidOperation)
+	}
+
+	doc, err := e.fetch(ctx, documentID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentID, err := resolveSegment(doc, opts.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartStage(ctx, "resolve_anchors", attribute.Int("mcp.operations", 1))
+	proj := projectSegment(doc, segmentID)
+	matches, err := proj.selection(op)
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Choose != nil {
+		opts.pinOnChoose(doc.RevisionID)
+		occurrence, changed, err := proj.chooseOne(ctx, 0, op, modeFormat, matches, opts.Choose)
+		if err != nil {
+			return nil, err
+		}
+
+		if changed {
+			op.Occurrence = occurrence
+			if matches, err = proj.selection(op); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, span = tracing.StartStage(ctx, "plan_requests")
+
+	var requests []docs.Request
+
+	for _, r := range textRanges(proj, matches) {
+		if textFields != "" {
+			requests = append(requests, docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Range: &docs.Range{StartIndex: r.start, EndIndex: r.end}, TextStyle: text, Fields: textFields,
+			}})
+		}
+	}
+
+	paragraphs := paragraphRanges(proj, matches)
+
+	for _, r := range paragraphs {
+		if paraFields != "" {
+			requests = append(requests, docs.Request{UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Range: &docs.Range{StartIndex: r.start, EndIndex: r.end}, ParagraphStyle: para, Fields: paraFields,
+			}})
+		}
+	}
+
+	// Creating bullets removes leading tabs, so lists are made back to
+	// front to keep the earlier ranges in place.
+	for i := len(paragraphs) - 1; i >= 0 && format.Bullets != nil; i-- {
+		r := &docs.Range{StartIndex: paragraphs[i].start, EndIndex: paragraphs[i].end}
+		if preset == "" {
+			requests = append(requests, docs.Request{DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{Range: r}})
+		} else {
+			requests = append(requests, docs.Request{CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+				Range: r, BulletPreset: preset,
+			}})
+		}
+	}
+
+	docs.InSegment(requests, segmentID)
+	span.SetAttributes(attribute.Int("mcp.requests", len(requests)))
+	tracing.EndStage(span, nil)
+
+	result := &Result{
+		Type:         "ok",
+		DocumentID:   documentID,
+		RevisionID:   doc.RevisionID,
+		MatchesFound: len(matches),
+		PreviewURL:   docs.PreviewURL(documentID),
+		Warnings:     []string{},
+		Matches:      proj.previews(0, modeFormat, matches),
+		Segment:      segmentID,
+		Snapshot:     doc,
+	}
+
+	for _, m := range matches {
+		if m.selected {
+			result.MatchesChanged++
+		}
+	}
+
+	if err := e.send(ctx, result, doc, requests, opts); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// selection returns the anchor matches of op, or the body of its
+// section as a single match.
+func (p *projection) selection(op Operation) ([]match, error) {
+	var scope section
+
+	if op.Section != "" {
+		var err error
+		if scope, err = p.section(0, op.Section); err != nil {
+			return nil, err
+		}
+	}
+
+	if op.anchored() {
+		return p.anchorMatches(0, op, scope)
+	}
+
+	return []match{{start: scope.start, end: scope.end, selected: true}}, nil
+}
+
+// textRanges returns the selected ranges, short of the final newline of
+// the body, which cannot be styled.
+func textRanges(p *projection, matches []match) []match {
+	var out []match
+
+	for _, m := range matches {
+		end := min(m.end, p.bodyEnd-1)
+		if m.selected && end > m.start {
+			out = append(out, match{start: m.start, end: end})
+		}
+	}
+
+	return out
+}
+
+// paragraphRanges widens the selected ranges to whole paragraphs and
+// merges those that touch, in document order.
+func paragraphRanges(p *projection, matches []match) []match {
+	var spans []match
+
+	for _, m := range matches {
+		if !m.selected || m.end <= m.start {
+			continue
+		}
+
+		spans = append(spans, match{start: p.paragraphAt(m.start).start, end: p.paragraphAt(m.end - 1).end})
+	}
+
+	sort.Slice(spans, func(a, b int) bool { return spans[a].start < spans[b].start })
+
+	var out []match
+
+	for _, s := range spans {
+		if last := len(out) - 1; last >= 0 && s.start <= out[last].end {
+			out[last].end = max(out[last].end, s.end)
+
+			continue
+		}
+
+		out = append(out, s)
+	}
+
+	return out
+}
+
+func (f Format) textStyle() (*docs.TextStyle, string, error) {
+	style := &docs.TextStyle{}
+
+	var fields []string
+
+	flags := []struct {
+		name  string
+		value *bool
+		dst   *bool
+	}{
+		{"bold", f.Bold, &style.Bold},
+		{"italic", f.Italic, &style.Italic},
+		{"underline", f.Underline, &style.Underline},
+		{"strikethrough", f.Strikethrough, &style.Strikethrough},
+	}
+
+	for _, flag := range flags {
+		if flag.value != nil {
+			*flag.dst = *flag.value
+			fields = append(fields, flag.name)
+		}
+	}
+
+	if f.Link != nil {
+		if *f.Link != "" {
+			if u, err := url.Parse(*f.Link); err != nil || (u.Scheme == "" && !strings.HasPrefix(*f.Link, "#")) {
+				return nil, "", fmt.Errorf("%w: link %q is not an absolute URL", ErrInvalidOperation, *f.Link)
+			}
+
+			style.Link = &docs.Link{URL: *f.Link}
+		}
+
+		fields = append(fields, "link")
+	}
+
+	if f.FontFamily != nil {
+		if name := strings.TrimSpace(*f.FontFamily); name != "" {
+			style.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: name}
+		}
+
+		fields = append(fields, "weightedFontFamily")
+	}
+
+	if f.FontSize != nil {
+		if *f.FontSize <= 0 || *f.FontSize > 400 {
+			return nil, "", fmt.Errorf("%w: fontSize %g is not between 0 and 400 points", ErrInvalidOperation, *f.FontSize)
+		}
+
+		style.FontSize = &docs.Dimension{Magnitude: *f.FontSize, Unit: "PT"}
+		fields = append(fields, "fontSize")
+	}
+
+	if f.Color != nil {
+		if *f.Color != "" {
+			rgb, err := parseColor(*f.Color)
+			if err != nil {
+				return nil, "", err
+			}
+
+			style.ForegroundColor = &docs.OptionalColor{Color: &docs.Color{RGBColor: rgb}}
+		}
+
+		fields = append(fields, "foregroundColor")
+	}
+
+	return style, strings.Join(fields, ","), nil
+}
+
+func (f Format) paragraphStyle() (*docs.ParagraphStyle, string, error) {
+	style := &docs.ParagraphStyle{}
+
+	var fields []string
+
+	if f.NamedStyle != nil {
+		name := strings.ToUpper(strings.Join(strings.Fields(*f.NamedStyle), "_"))
+		if !slices.Contains(namedStyles, name) {
+			return nil, "", fmt.Errorf("%w: namedStyle %q is not one of %s",
+				ErrInvalidOperation, *f.NamedStyle, strings.Join(namedStyles, ", "))
+		}
+
+		style.NamedStyleType = name
+		fields = append(fields, "namedStyleType")
+	}
+
+	if f.Alignment != nil {
+		alignment, ok := alignments[strings.ToUpper(strings.TrimSpace(*f.Alignment))]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: alignment %q is not START, CENTER, END or JUSTIFIED",
+				ErrInvalidOperation, *f.Alignment)
+		}
+
+		style.Alignment = alignment
+		fields = append(fields, "alignment")
+	}
+
+	return style, strings.Join(fields, ","), nil
+}
+
+// bulletPreset returns the list preset to create, or "" to remove
+// bullets.
+func (f Format) bulletPreset() (string, error) {
+	if f.Bullets == nil {
+		return "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(*f.Bullets)) {
+	case BulletsDisc:
+		return docs.BulletPresetDisc, nil
+	case BulletsNumbered:
+		return docs.BulletPresetNumbers, nil
+	case BulletsNone:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%w: bullets %q is not %s, %s or %s",
+			ErrInvalidOperation, *f.Bullets, BulletsDisc, BulletsNumbered, BulletsNone)
+	}
+}
+
+// parseColor reads a "#RRGGBB" color.
+func parseColor(s string) (*docs.RGBColor, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return nil, fmt.Errorf("%w: color %q is not of the form #RRGGBB", ErrInvalidOperation, s)
+	}
+
+	return &docs.RGBColor{
+		Red:   float64(value>>16&0xff) / 255,
+		Green: float64(value>>8&0xff) / 255,
+		Blue:  float64(value&0xff) / 255,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+func TestORPHAN_Editor_Format_StylesMatchWithoutRewritingText(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Intro\n\nSee the release notes here.")
+	bold, link := true, "https://example.com/notes"
+
+	// Act
+	result, err := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "release notes"},
+		operations.Format{Bold: &bold, Link: &link}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MatchesChanged)
+
+	doc := get(t, store)
+	assert.Equal(t, "Intro\nSee the release notes here.\n", doc.Text())
+
+	runs := doc.Body.Content[2].Paragraph.Elements
+	require.Len(t, runs, 3)
+	assert.Equal(t, "release notes", runs[1].TextRun.Content)
+	assert.True(t, runs[1].TextRun.TextStyle.Bold)
+	assert.Equal(t, "https://example.com/notes", runs[1].TextRun.TextStyle.Link.URL)
+	assert.False(t, runs[2].TextRun.TextStyle.Bold)
+}
+
+func TestORPHAN_Editor_Format_SendsOnlyStyleRequests(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, "Title line\n\nfirst item\n\nsecond item")
+	heading, bullets, color := "heading 2", operations.BulletsNumbered, "#FF8000"
+
+	// Act
+	headingResult, headingErr := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "title line"},
+		operations.Format{NamedStyle: &heading, Color: &color}, operations.Options{DryRun: true})
+	listResult, listErr := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "item"},
+		operations.Format{Bullets: &bullets}, operations.Options{DryRun: true})
+
+	// Assert
+	require.NoError(t, headingErr)
+	require.Len(t, headingResult.Requests, 2)
+	assert.Equal(t, "foregroundColor", headingResult.Requests[0].UpdateTextStyle.Fields)
+	assert.InDelta(t, 0.5, headingResult.Requests[0].UpdateTextStyle.TextStyle.ForegroundColor.Color.RGBColor.Green, 0.01)
+	assert.Equal(t, docs.StyleHeading2, headingResult.Requests[1].UpdateParagraphStyle.ParagraphStyle.NamedStyleType)
+
+	require.NoError(t, listErr)
+	require.Len(t, listResult.Requests, 1, "adjacent list paragraphs become one list")
+	assert.Equal(t, docs.BulletPresetNumbers, listResult.Requests[0].CreateParagraphBullets.BulletPreset)
+	assert.Equal(t, &docs.Range{StartIndex: 12, EndIndex: 35}, listResult.Requests[0].CreateParagraphBullets.Range)
+}
+
+func TestORPHAN_Editor_Format_SectionBodyGetsParagraphStyle(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "# Notes\n\none\n\ntwo\n\n# Next\n\nthree")
+	alignment := "center"
+
+	// Act
+	_, err := editor.Format(context.Background(), docID,
+		operations.Selection{Section: "Notes"},
+		operations.Format{Alignment: &alignment}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+
+	content := get(t, store).Body.Content
+	require.Len(t, content, 6)
+	assert.Empty(t, content[1].Paragraph.ParagraphStyle.Alignment)
+	assert.Equal(t, "CENTER", content[2].Paragraph.ParagraphStyle.Alignment)
+	assert.Equal(t, "CENTER", content[3].Paragraph.ParagraphStyle.Alignment)
+	assert.Empty(t, content[5].Paragraph.ParagraphStyle.Alignment)
+}
+
+func TestORPHAN_Editor_Format_InvalidStyle_IsRejected(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, "text")
+	color := "orange"
+
+	// Act
+	_, colorErr := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "text"}, operations.Format{Color: &color}, operations.Options{})
+	_, emptyErr := editor.Format(context.Background(), docID,
+		operations.Selection{AnchorText: "text"}, operations.Format{}, operations.Options{})
+
+	// Assert
+	require.ErrorIs(t, colorErr, operations.ErrInvalidOperation)
+	require.ErrorIs(t, emptyErr, operations.ErrInvalidOperation)
+}
@@ -0,0 +1,139 @@
+package operations
+
+import (
+	"sort"
+)
+
+// DefaultMinSimilarity is the fuzzy matching threshold used when an
+// operation does not set one.
+const DefaultMinSimilarity = 0.8
+
+// maxFuzzyCells bounds the work of one fuzzy search: the anchor length
+// times the document length.
+const maxFuzzyCells = 50_000_000
+
+// scoredSpan is a fuzzy match in normalized runes with its similarity,
+// 1 minus the edit distance relative to the longer of anchor and span.
+type scoredSpan struct {
+	start int
+	end   int
+	score float64
+}
+
+// findFuzzy returns the non-overlapping spans of text that are at least
+// minSimilarity similar to needle, in document order. It runs the
+// Sellers variant of edit distance, where a match may start anywhere,
+// and carries the start of the best alignment along with each cell.
+func findFuzzy(text, needle []rune, minSimilarity float64) []scoredSpan {
+	m := len(needle)
+	if m == 0 {
+		return nil
+	}
+
+	maxDistance := int((1 - minSimilarity) * float64(m))
+
+	prev, prevStart := make([]int, m+1), make([]int, m+1)
+	cur, curStart := make([]int, m+1), make([]int, m+1)
+
+	for i := range prev {
+		prev[i] = i
+	}
+
+	var candidates []scoredSpan
+
+	for j := 1; j <= len(text); j++ {
+		cur[0], curStart[0] = 0, j
+
+		for i := 1; i <= m; i++ {
+			cost := 1
+			if needle[i-1] == text[j-1] {
+				cost = 0
+			}
+
+			best, start := prev[i-1]+cost, prevStart[i-1]
+			if d := prev[i] + 1; d < best {
+				best, start = d, prevStart[i]
+			}
+
+			if d := cur[i-1] + 1; d < best {
+				best, start = d, curStart[i-1]
+			}
+
+			cur[i], curStart[i] = best, start
+		}
+
+		if d := cur[m]; d <= maxDistance {
+			if s, ok := trimSpan(text, curStart[m], j); ok {
+				s.score = 1 - float64(d)/float64(max(m, s.end-s.start))
+				if s.score >= minSimilarity {
+					candidates = append(candidates, s)
+				}
+			}
+		}
+
+		prev, cur = cur, prev
+		prevStart, curStart = curStart, prevStart
+	}
+
+	return bestSpans(candidates, m)
+}
+
+// trimSpan drops the spaces an alignment may begin or end with.
+func trimSpan(text []rune, start, end int) (scoredSpan, bool) {
+	for start < end && text[start] == ' ' {
+		start++
+	}
+
+	for end > start && text[end-1] == ' ' {
+		end--
+	}
+
+	return scoredSpan{start: start, end: end}, start < end
+}
+
+// bestSpans keeps the highest scoring of overlapping candidates,
+// preferring spans as long as the anchor, and sorts them by position.
+func bestSpans(candidates []scoredSpan, length int) []scoredSpan {
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+		if ca.score != cb.score {
+			return ca.score > cb.score
+		}
+
+		if da, db := abs(ca.end-ca.start-length), abs(cb.end-cb.start-length); da != db {
+			return da < db
+		}
+
+		return ca.start < cb.start
+	})
+
+	var out []scoredSpan
+
+	for _, c := range candidates {
+		overlapping := false
+
+		for _, o := range out {
+			if c.start < o.end && o.start < c.end {
+				overlapping = true
+
+				break
+			}
+		}
+
+		if !overlapping {
+			out = append(out, c)
+		}
+	}
+
+	sort.Slice(out, func(a, b int) bool { return out[a].start < out[b].start })
+
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
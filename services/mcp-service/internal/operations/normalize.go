@@ -0,0 +1,114 @@
+package operations
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// normalized is the projection text as anchors are compared against it:
+// typographic quotes, dashes and ellipses are folded to ASCII, invisible
+// characters are dropped and every run of whitespace, paragraph breaks
+// included, becomes one space. Rune i came from the projection runes
+// [from[i], to[i]), so matches map back to document indexes.
+type normalized struct {
+	runes []rune
+	from  []int
+	to    []int
+}
+
+// foldedRunes are replaced before comparison.
+var foldedRunes = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'",
+	'“': `"`, '”': `"`, '„': `"`, '‟': `"`, '″': `"`,
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '−': "-",
+	'…': "...",
+}
+
+// invisibleRunes never take part in a comparison: soft hyphens,
+// zero-width spaces and joiners, and byte order marks.
+var invisibleRunes = map[rune]bool{
+	'\u00AD': true, '\u200B': true, '\u200C': true, '\u200D': true, '\u2060': true, '\uFEFF': true,
+}
+
+// listMarker matches the bullet glyphs and Markdown list markers an
+// anchor copied from a rendered list may start its lines with. Docs
+// keeps bullets out of the text, so they are stripped when the anchor
+// is not found as given.
+var listMarker = regexp.MustCompile(`(?m)^[ \t]*(?:[•◦▪▫‣⁃●○■□*+-]|\d{1,3}[.)])[ \t]+`)
+
+func normalize(src []rune, caseSensitive bool) *normalized {
+	n := &normalized{}
+
+	for i, r := range src {
+		switch {
+		case invisibleRunes[r]:
+			continue
+		case unicode.IsSpace(r):
+			if last := len(n.runes) - 1; last >= 0 && n.runes[last] == ' ' {
+				n.to[last] = i + 1
+
+				continue
+			}
+
+			n.add(' ', i)
+		case foldedRunes[r] != "":
+			for _, f := range foldedRunes[r] {
+				n.add(f, i)
+			}
+		case caseSensitive:
+			n.add(r, i)
+		default:
+			n.add(unicode.ToLower(r), i)
+		}
+	}
+
+	return n
+}
+
+func (n *normalized) add(r rune, i int) {
+	n.runes = append(n.runes, r)
+	n.from = append(n.from, i)
+	n.to = append(n.to, i+1)
+}
+
+// normalizeAnchor normalizes anchor like the document text and drops
+// surrounding whitespace.
+func normalizeAnchor(anchor string, caseSensitive bool) []rune {
+	runes := normalize([]rune(anchor), caseSensitive).runes
+
+	return []rune(strings.TrimSpace(string(runes)))
+}
+
+// span maps the normalized runes [start, end) to a document range.
+func (p *projection) span(n *normalized, start, end int) match {
+	return match{start: p.indexAt(n.from[start]), end: p.indexAt(n.to[end-1])}
+}
+
+// findExact returns every non-overlapping occurrence of needle.
+func findExact(text, needle []rune) [][2]int {
+	var spans [][2]int
+
+	for i := 0; i+len(needle) <= len(text); {
+		if !hasPrefix(text[i:], needle) {
+			i++
+
+			continue
+		}
+
+		spans = append(spans, [2]int{i, i + len(needle)})
+		i += len(needle)
+	}
+
+	return spans
+}
+
+func hasPrefix(text, prefix []rune) bool {
+	for j, r := range prefix {
+		if text[j] != r {
+			return false
+		}
+	}
+
+	return true
+}
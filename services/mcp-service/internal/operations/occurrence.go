@@ -0,0 +1,163 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Occurrence selects which matches of an anchor an operation acts on.
+// The zero value selects every match, as the design document requires
+// by default. Indexes are 1-based positions in document order.
+type Occurrence struct {
+	Indexes []int
+	Last    bool
+}
+
+// Occurrences accepted by name.
+var (
+	OccurrenceAll   = Occurrence{}
+	OccurrenceFirst = Occurrence{Indexes: []int{1}}
+	OccurrenceLast  = Occurrence{Last: true}
+)
+
+// ParseOccurrence reads "all", "first", "last", a 1-based index such as
+// "2" or a comma-separated list of indexes such as "1,3".
+func ParseOccurrence(s string) (Occurrence, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "all":
+		return OccurrenceAll, nil
+	case "first":
+		return OccurrenceFirst, nil
+	case "last":
+		return OccurrenceLast, nil
+	}
+
+	var indexes []int
+
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return Occurrence{}, fmt.Errorf("%w: occurrence %q is not all, first, last or a list of indexes",
+				ErrInvalidOperation, s)
+		}
+
+		indexes = append(indexes, n)
+	}
+
+	return occurrenceOf(indexes)
+}
+
+// UnmarshalJSON accepts a name or index string, a number, or an array
+// of numbers.
+func (o *Occurrence) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	var err error
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*o = OccurrenceAll
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err = json.Unmarshal(data, &s); err == nil {
+			*o, err = ParseOccurrence(s)
+		}
+	case len(data) > 0 && data[0] == '[':
+		var indexes []int
+		if err = json.Unmarshal(data, &indexes); err == nil {
+			if len(indexes) == 0 {
+				return fmt.Errorf("%w: occurrence list is empty", ErrInvalidOperation)
+			}
+
+			*o, err = occurrenceOf(indexes)
+		}
+	default:
+		var n int
+		if err = json.Unmarshal(data, &n); err == nil {
+			*o, err = occurrenceOf([]int{n})
+		}
+	}
+
+	return err
+}
+
+// String returns the occurrence in the form ParseOccurrence reads.
+func (o Occurrence) String() string {
+	switch {
+	case o.Last:
+		return "last"
+	case len(o.Indexes) == 0:
+		return "all"
+	}
+
+	parts := make([]string, len(o.Indexes))
+	for i, n := range o.Indexes {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// all reports whether every match is selected.
+func (o Occurrence) all() bool {
+	return !o.Last && len(o.Indexes) == 0
+}
+
+func (o Occurrence) validate() error {
+	for _, n := range o.Indexes {
+		if n < 1 {
+			return fmt.Errorf("%w: occurrence %d is not a 1-based index", ErrInvalidOperation, n)
+		}
+	}
+
+	return nil
+}
+
+// pick marks the selected matches. When an index is beyond the number
+// of matches it marks nothing and returns the largest index and false.
+func (o Occurrence) pick(matches []match) (int, bool) {
+	switch {
+	case o.all():
+		for i := range matches {
+			matches[i].selected = true
+		}
+	case o.Last:
+		matches[len(matches)-1].selected = true
+	default:
+		if n := slices.Max(o.Indexes); n > len(matches) {
+			return n, false
+		}
+
+		for _, n := range o.Indexes {
+			matches[n-1].selected = true
+		}
+	}
+
+	return 0, true
+}
+
+// occurrenceOf sorts and deduplicates indexes.
+func occurrenceOf(indexes []int) (Occurrence, error) {
+	sorted := append([]int(nil), indexes...)
+	sort.Ints(sorted)
+
+	var out []int
+
+	for _, n := range sorted {
+		if len(out) == 0 || out[len(out)-1] != n {
+			out = append(out, n)
+		}
+	}
+
+	o := Occurrence{Indexes: out}
+	if err := o.validate(); err != nil {
+		return Occurrence{}, err
+	}
+
+	return o, nil
+}
@@ -0,0 +1,57 @@
+package operations_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+func TestORPHAN_Occurrence_UnmarshalJSON_AcceptsNamesIndexesAndLists(t *testing.T) {
+	// Arrange
+	var args struct {
+		Name   operations.Occurrence `json:"name"`
+		Index  operations.Occurrence `json:"index"`
+		List   operations.Occurrence `json:"list"`
+		Digits operations.Occurrence `json:"digits"`
+	}
+
+	// Act
+	err := json.Unmarshal([]byte(`{"name":"Last","index":2,"list":[3,1,3],"digits":"1, 2"}`), &args)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, operations.OccurrenceLast, args.Name)
+	assert.Equal(t, []int{2}, args.Index.Indexes)
+	assert.Equal(t, []int{1, 3}, args.List.Indexes)
+	assert.Equal(t, "1,2", args.Digits.String())
+}
+
+func TestORPHAN_Occurrence_InvalidValues_AreRejected(t *testing.T) {
+	// Arrange
+	var o operations.Occurrence
+
+	// Act
+	zeroErr := json.Unmarshal([]byte(`0`), &o)
+	emptyErr := json.Unmarshal([]byte(`[]`), &o)
+	_, nameErr := operations.ParseOccurrence("second")
+
+	// Assert
+	require.ErrorIs(t, zeroErr, operations.ErrInvalidOperation)
+	require.ErrorIs(t, emptyErr, operations.ErrInvalidOperation)
+	require.ErrorIs(t, nameErr, operations.ErrInvalidOperation)
+}
+
+func TestORPHAN_Operation_OccurrenceWithoutAnchor_IsInvalid(t *testing.T) {
+	// Arrange
+	op := operations.Operation{Mode: operations.ModeAppend, Content: "x", Occurrence: operations.OccurrenceFirst}
+
+	// Act
+	err := op.Validate()
+
+	// Assert
+	require.ErrorIs(t, err, operations.ErrInvalidOperation)
+}
@@ -0,0 +1,136 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode selects what an Operation does with its content.
+type Mode string
+
+// Edit modes, named as in the design document.
+const (
+	ModeReplaceAll   Mode = "replace_all"
+	ModeAppend       Mode = "append"
+	ModePrepend      Mode = "prepend"
+	ModeReplaceMatch Mode = "replace_match"
+	ModeInsertBefore Mode = "insert_before"
+	ModeInsertAfter  Mode = "insert_after"
+)
+
+// modeAliases accepts the camelCase spellings used by the tool names.
+var modeAliases = map[string]Mode{
+	"replaceAll":   ModeReplaceAll,
+	"replaceMatch": ModeReplaceMatch,
+	"insertBefore": ModeInsertBefore,
+	"insertAfter":  ModeInsertAfter,
+}
+
+// ParseMode resolves a mode name or alias.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(name); mode {
+	case ModeReplaceAll, ModeAppend, ModePrepend, ModeReplaceMatch, ModeInsertBefore, ModeInsertAfter:
+		return mode, nil
+	}
+
+	if mode, ok := modeAliases[name]; ok {
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("%w: unknown mode %q", ErrInvalidOperation, name)
+}
+
+// Modes lists every mode in documentation order.
+func Modes() []Mode {
+	return []Mode{ModeReplaceAll, ModeAppend, ModePrepend, ModeReplaceMatch, ModeInsertBefore, ModeInsertAfter}
+}
+
+// Operation is one edit. Content is Markdown. AnchorText is required by
+// replace_match, insert_before and insert_after, and optional for
+// append, which then inserts after the anchor instead of at the end.
+// Anchors are matched case-insensitively unless CaseSensitive is set.
+// Section, a heading path such as "Installation > Linux", scopes the
+// operation to the body under that heading: replace_all, prepend and
+// append then act on the section instead of the document, and anchors
+// only match inside it. Occurrence picks which anchor matches are acted
+// on; by default all of them are.
+//
+// Anchors are compared with typographic quotes and dashes folded and
+// whitespace collapsed, so they match across paragraph breaks. Fuzzy
+// additionally accepts text at least MinSimilarity similar (0 means
+// DefaultMinSimilarity) when the anchor is not found as written.
+type Operation struct {
+	Mode          Mode
+	Content       string
+	AnchorText    string
+	CaseSensitive bool
+	Section       string
+	Occurrence    Occurrence
+	Fuzzy         bool
+	MinSimilarity float64
+}
+
+// anchored reports whether the operation locates its target by anchor.
+func (op Operation) anchored() bool {
+	switch op.Mode {
+	case ModeReplaceMatch, ModeInsertBefore, ModeInsertAfter:
+		return true
+	case ModeAppend:
+		return op.AnchorText != ""
+	default:
+		return false
+	}
+}
+
+// matchOptions returns how the anchor is compared with the document.
+func (op Operation) matchOptions() matchOptions {
+	opts := matchOptions{caseSensitive: op.CaseSensitive, fuzzy: op.Fuzzy, minSimilarity: op.MinSimilarity}
+	if opts.minSimilarity == 0 {
+		opts.minSimilarity = DefaultMinSimilarity
+	}
+
+	return opts
+}
+
+// Validate checks the fields the mode requires.
+func (op Operation) Validate() error {
+	if _, err := ParseMode(string(op.Mode)); err != nil {
+		return err
+	}
+
+	switch op.Mode {
+	case ModeReplaceMatch, ModeInsertBefore, ModeInsertAfter:
+		if strings.TrimSpace(op.AnchorText) == "" {
+			return fmt.Errorf("%w: %s requires anchorText", ErrInvalidOperation, op.Mode)
+		}
+	case ModeReplaceAll, ModeAppend, ModePrepend:
+	}
+
+	if op.MinSimilarity < 0 || op.MinSimilarity > 1 {
+		return fmt.Errorf("%w: minSimilarity %g is not between 0 and 1", ErrInvalidOperation, op.MinSimilarity)
+	}
+
+	if (op.Fuzzy || op.MinSimilarity > 0) && !op.anchored() {
+		return fmt.Errorf("%w: fuzzy matching requires anchorText", ErrInvalidOperation)
+	}
+
+	if !op.Occurrence.all() {
+		if !op.anchored() {
+			return fmt.Errorf("%w: occurrence requires anchorText", ErrInvalidOperation)
+		}
+
+		if err := op.Occurrence.validate(); err != nil {
+			return err
+		}
+	}
+
+	if op.Section != "" {
+		for _, step := range strings.Split(op.Section, ">") {
+			if strings.TrimSpace(step) == "" {
+				return fmt.Errorf("%w: section path %q has an empty step", ErrInvalidOperation, op.Section)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package operations
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// Counts sums up part of a document. Words and images in table cells
+// count as well.
+type Counts struct {
+	WordCount int `json:"wordCount"`
+	Tables    int `json:"tables"`
+	Images    int `json:"images"`
+}
+
+// OutlineHeading is an outline heading with the counts of its section
+// body, subsections included.
+type OutlineHeading struct {
+	Heading
+	Counts
+}
+
+// TabOutline is the outline of one document tab. NestingLevel is 0 for
+// top-level tabs.
+type TabOutline struct {
+	TabID        string           `json:"tabId"`
+	Title        string           `json:"title"`
+	NestingLevel int              `json:"nestingLevel"`
+	Headings     []OutlineHeading `json:"headings"`
+	Counts
+}
+
+// OutlineResult describes the structure of a document. Headings and
+// Counts cover the body, which edits and section paths refer to. Tabs
+// lists every tab, the first one being the body, when the document was
+// read with its tabs.
+type OutlineResult struct {
+	Type       string           `json:"type"`
+	DocumentID string           `json:"docId"`
+	RevisionID string           `json:"revisionId,omitempty"`
+	Title      string           `json:"title,omitempty"`
+	Headings   []OutlineHeading `json:"headings"`
+	Counts
+	Tabs       []TabOutline `json:"tabs,omitempty"`
+	PreviewURL string       `json:"preview_url"`
+}
+
+// Outline returns the heading tree of documentID without changing it.
+func (e *Editor) Outline(ctx context.Context, documentID string) (*OutlineResult, error) {
+	doc, err := e.fetch(ctx, documentID, Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartStage(ctx, "outline")
+	defer tracing.EndStage(span, nil)
+
+	result := &OutlineResult{
+		Type:       "ok",
+		DocumentID: documentID,
+		RevisionID: doc.RevisionID,
+		Title:      doc.Title,
+		Tabs:       tabOutlines(doc.Tabs),
+		PreviewURL: docs.PreviewURL(documentID),
+	}
+	result.Headings, result.Counts = project(doc).describe()
+
+	return result, nil
+}
+
+// tabOutlines lists tabs depth-first, each followed by its child tabs,
+// the order the tab list shows them in.
+func tabOutlines(tabs []docs.Tab) []TabOutline {
+	var out []TabOutline
+
+	for _, tab := range tabs {
+		t := TabOutline{
+			TabID:        tab.TabProperties.TabID,
+			Title:        tab.TabProperties.Title,
+			NestingLevel: tab.TabProperties.NestingLevel,
+			Headings:     []OutlineHeading{},
+		}
+
+		if tab.DocumentTab != nil {
+			t.Headings, t.Counts = project(&docs.Document{Body: tab.DocumentTab.Body}).describe()
+		}
+
+		out = append(out, t)
+		out = append(out, tabOutlines(tab.ChildTabs)...)
+	}
+
+	return out
+}
+
+// describe returns the outline with the counts of every section body,
+// and the counts of the whole body.
+func (p *projection) describe() ([]OutlineHeading, Counts) {
+	outline := p.outline()
+	headings := make([]OutlineHeading, len(outline))
+
+	for i, h := range outline {
+		headings[i] = OutlineHeading{Heading: h, Counts: p.count(p.paragraphs[h.paragraph].end, h.EndIndex)}
+	}
+
+	return headings, p.count(0, p.bodyEnd)
+}
+
+// count sums up the document range [start, end). Runes, tables and
+// images are all in document order.
+func (p *projection) count(start, end int) Counts {
+	within := func(indexes []int) (int, int) {
+		return sort.SearchInts(indexes, start), sort.SearchInts(indexes, end)
+	}
+
+	from, to := within(p.index)
+	c := Counts{WordCount: len(strings.Fields(string(p.runes[from:to])))}
+
+	from, to = within(p.tables)
+	c.Tables = to - from
+
+	from, to = within(p.images)
+	c.Images = to - from
+
+	return c
+}
@@ -0,0 +1,147 @@
+package operations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// fixedStore serves one document read as is, tables and images included.
+type fixedStore struct {
+	doc *docs.Document
+}
+
+func (s fixedStore) Get(context.Context, string) (*docs.Document, error) {
+	return s.doc, nil
+}
+
+func (s fixedStore) BatchUpdate(context.Context, string, []docs.Request, *docs.WriteControl) (*docs.BatchUpdateResponse, error) {
+	return nil, errors.New("read-only store")
+}
+
+// paragraphAt returns a paragraph of text starting at index, styled
+// namedStyle when it is not empty.
+func paragraphAt(index int, text, namedStyle string) docs.StructuralElement {
+	end := index + len(utf16.Encode([]rune(text)))
+	el := docs.StructuralElement{StartIndex: index, EndIndex: end, Paragraph: &docs.Paragraph{
+		Elements: []docs.ParagraphElement{{StartIndex: index, EndIndex: end, TextRun: &docs.TextRun{Content: text}}},
+	}}
+
+	if namedStyle != "" {
+		el.Paragraph.ParagraphStyle = &docs.ParagraphStyle{NamedStyleType: namedStyle}
+	}
+
+	return el
+}
+
+func TestORPHAN_Editor_Outline_CountsWordsPerSection(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, "Intro words here.\n\n# Install\n\nRun it.\n\n## Linux\n\nUse apt to install.\n\n# Usage\n\nCall it.")
+
+	// Act
+	result, err := editor.Outline(context.Background(), docID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Headings, 3)
+	assert.Equal(t, 1, result.Headings[0].Level)
+	assert.Equal(t, "Install", result.Headings[0].Path)
+	assert.Equal(t, 7, result.Headings[0].WordCount)
+	assert.Equal(t, "Install > Linux", result.Headings[1].Path)
+	assert.Equal(t, 4, result.Headings[1].WordCount)
+	assert.Equal(t, result.Headings[2].StartIndex, result.Headings[0].EndIndex)
+	assert.Equal(t, 2, result.Headings[2].WordCount)
+	assert.Equal(t, 14, result.WordCount)
+	assert.Empty(t, result.Tabs)
+}
+
+func TestORPHAN_Editor_Outline_CountsTablesAndImages(t *testing.T) {
+	// Arrange
+	image := paragraphAt(20, "x\n", "")
+	image.EndIndex = 23
+	image.Paragraph.Elements = append([]docs.ParagraphElement{{
+		StartIndex: 20, EndIndex: 21, InlineObjectElement: &docs.InlineObjectElement{InlineObjectID: "kix.1"},
+	}}, docs.ParagraphElement{StartIndex: 21, EndIndex: 23, TextRun: &docs.TextRun{Content: "x\n"}})
+	body := &docs.Body{Content: []docs.StructuralElement{
+		paragraphAt(1, "Plan\n", docs.StyleHeading1),
+		{StartIndex: 6, EndIndex: 14, Table: &docs.Table{Rows: 1, Columns: 1, TableRows: []docs.TableRow{{
+			StartIndex: 7, EndIndex: 13, TableCells: []docs.TableCell{{
+				StartIndex: 8, EndIndex: 13, Content: []docs.StructuralElement{paragraphAt(9, "Cell\n", "")},
+			}},
+		}}}},
+		paragraphAt(14, "Data\n", docs.StyleHeading1),
+		paragraphAt(19, "\n", ""),
+		image,
+	}}
+	editor := operations.NewEditor(fixedStore{doc: &docs.Document{DocumentID: docID, Body: body}})
+
+	// Act
+	result, err := editor.Outline(context.Background(), docID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Headings, 2)
+	assert.Equal(t, operations.Counts{WordCount: 1, Tables: 1}, result.Headings[0].Counts)
+	assert.Equal(t, operations.Counts{WordCount: 1, Images: 1}, result.Headings[1].Counts)
+	assert.Equal(t, operations.Counts{WordCount: 4, Tables: 1, Images: 1}, result.Counts)
+}
+
+func TestORPHAN_Editor_Outline_ListsTabsDepthFirst(t *testing.T) {
+	// Arrange
+	first := &docs.Body{Content: []docs.StructuralElement{paragraphAt(1, "Notes\n", docs.StyleHeading1)}}
+	child := &docs.Body{Content: []docs.StructuralElement{
+		paragraphAt(1, "Draft\n", docs.StyleHeading2), paragraphAt(7, "Two words\n", ""),
+	}}
+	doc := &docs.Document{DocumentID: docID, Body: first, Tabs: []docs.Tab{
+		{
+			TabProperties: docs.TabProperties{TabID: "t.0", Title: "Main"},
+			DocumentTab:   &docs.DocumentTab{Body: first},
+			ChildTabs: []docs.Tab{{
+				TabProperties: docs.TabProperties{TabID: "t.1", Title: "Sub", NestingLevel: 1},
+				DocumentTab:   &docs.DocumentTab{Body: child},
+			}},
+		},
+		{TabProperties: docs.TabProperties{TabID: "t.2", Title: "Empty", Index: 1}},
+	}}
+	editor := operations.NewEditor(fixedStore{doc: doc})
+
+	// Act
+	result, err := editor.Outline(context.Background(), docID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Tabs, 3)
+	assert.Equal(t, []string{"t.0", "t.1", "t.2"},
+		[]string{result.Tabs[0].TabID, result.Tabs[1].TabID, result.Tabs[2].TabID})
+	assert.Equal(t, "Notes", result.Headings[0].Path)
+	assert.Equal(t, 1, result.Tabs[1].NestingLevel)
+	assert.Equal(t, "Draft", result.Tabs[1].Headings[0].Path)
+	assert.Equal(t, 2, result.Tabs[1].Headings[0].WordCount)
+	assert.Empty(t, result.Tabs[2].Headings)
+}
+
+func TestORPHAN_Editor_SecondTab_IsRejected(t *testing.T) {
+	// Arrange
+	body := &docs.Body{Content: []docs.StructuralElement{paragraphAt(1, "Intro\n", "")}}
+	doc := &docs.Document{DocumentID: docID, Body: body, Tabs: []docs.Tab{
+		{TabProperties: docs.TabProperties{TabID: "t.0"}, DocumentTab: &docs.DocumentTab{Body: body}},
+		{TabProperties: docs.TabProperties{TabID: "t.notes"}, DocumentTab: &docs.DocumentTab{Body: body}},
+	}}
+	editor := operations.NewEditor(fixedStore{doc: doc})
+
+	// Act
+	_, firstErr := editor.Search(context.Background(), docID, operations.SearchQuery{Query: "Intro", TabID: "t.0"})
+	_, secondErr := editor.Search(context.Background(), docID, operations.SearchQuery{Query: "Intro", TabID: "t.notes"})
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.ErrorIs(t, secondErr, operations.ErrInvalidOperation)
+	assert.Contains(t, secondErr.Error(), "t.notes")
+}
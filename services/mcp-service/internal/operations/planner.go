@@ -0,0 +1,111 @@
+package operations
+
+import (
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/markdown"
+)
+
+// edit is one change resolved against the snapshot: the range
+// [start, end) is deleted (start == end for pure insertions) and
+// content is inserted at start.
+type edit struct {
+	op        int
+	start     int
+	end       int
+	content   *markdown.Fragment
+	placement markdown.Placement
+	// intoEmpty marks block content placed into an empty paragraph; it
+	// needs a leading newline if an earlier edit already filled it.
+	intoEmpty bool
+}
+
+// applied records an edit that has been planned, in snapshot
+// coordinates, with the number of code units it inserted.
+type applied struct {
+	edit
+	inserted int
+}
+
+// planner turns resolved edits into one list of batchUpdate requests
+// for segmentID. Every edit is expressed in snapshot coordinates, so
+// positions are shifted by the edits planned before it. Footnotes holds
+// the content of the footnotes the requests create, in request order.
+type planner struct {
+	segmentID string
+	done      []applied
+	requests  []docs.Request
+	footnotes []string
+}
+
+// add plans e after every edit added so far.
+func (p *planner) add(e edit) error {
+	for _, prev := range p.done {
+		if overlaps(prev, e) {
+			return conflicting(e.op, prev.op)
+		}
+	}
+
+	placement := e.placement
+	if e.intoEmpty && p.filled(e.start) {
+		placement = markdown.Placement{LeadingNewline: true}
+	}
+
+	start := p.shift(e.start)
+
+	if e.end > e.start {
+		p.requests = append(p.requests, docs.Request{DeleteContentRange: &docs.DeleteContentRangeRequest{
+			Range: &docs.Range{SegmentID: p.segmentID, StartIndex: start, EndIndex: p.shift(e.end)},
+		}})
+	}
+
+	reqs, inserted := e.content.Requests(p.segmentID, start, placement)
+	p.requests = append(p.requests, reqs...)
+	p.footnotes = append(p.footnotes, e.content.Footnotes()...)
+	p.done = append(p.done, applied{edit: e, inserted: inserted})
+
+	return nil
+}
+
+// shift maps a snapshot index to the current document. Content inserted
+// at the same index by an earlier edit stays in front, so edits at one
+// position land in operation order.
+func (p *planner) shift(index int) int {
+	out := index
+
+	for _, prev := range p.done {
+		if index >= prev.end {
+			out += prev.inserted - (prev.end - prev.start)
+		}
+	}
+
+	return out
+}
+
+// filled reports whether an earlier edit inserted content at index.
+func (p *planner) filled(index int) bool {
+	for _, prev := range p.done {
+		if prev.start == index && prev.inserted > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlaps reports whether e touches content prev deletes, or deletes
+// content prev inserted. Edits that merely meet at a boundary are fine.
+func overlaps(prev applied, e edit) bool {
+	prevDeletes := prev.end > prev.start
+	deletes := e.end > e.start
+
+	switch {
+	case prevDeletes && deletes:
+		return e.start < prev.end && prev.start < e.end
+	case prevDeletes:
+		return e.start > prev.start && e.start < prev.end
+	case deletes:
+		return prev.start > e.start && prev.start < e.end
+	default:
+		return false
+	}
+}
@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"sort"
+	"strings"
+)
+
+// contextRunes is how much text around a match a dry run shows.
+const contextRunes = 40
+
+// MatchPreview is one range an operation matched. For anchored
+// operations it is an anchor match, numbered in document order by
+// Occurrence and Selected when the operation acts on it; otherwise it is
+// the range the content replaces, or the insertion point when nothing is
+// replaced. Confidence is how similar an anchor match is to the anchor,
+// below 1 only for fuzzy matches. Indexes refer to the document before
+// the edit.
+type MatchPreview struct {
+	Operation     int     `json:"operation"`
+	Mode          Mode    `json:"mode"`
+	Occurrence    int     `json:"occurrence"`
+	Selected      bool    `json:"selected"`
+	Confidence    float64 `json:"confidence,omitempty"`
+	StartIndex    int     `json:"start_index"`
+	EndIndex      int     `json:"end_index"`
+	Text          string  `json:"text"`
+	ContextBefore string  `json:"context_before"`
+	ContextAfter  string  `json:"context_after"`
+}
+
+// offset returns the rune offset of the first rune at or after index.
+func (p *projection) offset(index int) int {
+	return sort.SearchInts(p.index, index)
+}
+
+func (p *projection) preview(op int, mode Mode, occurrence int, m match) MatchPreview {
+	start, end := p.offset(m.start), p.offset(m.end)
+
+	from := max(start-contextRunes, 0)
+	to := min(end+contextRunes, len(p.runes))
+
+	return MatchPreview{
+		Operation:     op,
+		Mode:          mode,
+		Occurrence:    occurrence,
+		Selected:      m.selected,
+		Confidence:    m.confidence,
+		StartIndex:    m.start,
+		EndIndex:      m.end,
+		Text:          string(p.runes[start:end]),
+		ContextBefore: trimContext(string(p.runes[from:start]), true),
+		ContextAfter:  trimContext(string(p.runes[end:to]), false),
+	}
+}
+
+// previews describes the matches of operation op.
+func (p *projection) previews(op int, mode Mode, matches []match) []MatchPreview {
+	out := make([]MatchPreview, len(matches))
+	for i, m := range matches {
+		out[i] = p.preview(op, mode, i+1, m)
+	}
+
+	return out
+}
+
+// trimContext drops context beyond the nearest paragraph break, which
+// is rarely useful for locating a match.
+func trimContext(s string, before bool) string {
+	if before {
+		if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+			return s[i+1:]
+		}
+
+		return s
+	}
+
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
@@ -0,0 +1,262 @@
+package operations
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// paragraph is the position of one paragraph in the snapshot. Last is
+// set for the final paragraph of its container (the body or a table
+// cell), whose newline cannot be moved. Level is the heading level, 0
+// for other paragraphs and for headings inside table cells.
+type paragraph struct {
+	start     int
+	end       int
+	empty     bool
+	last      bool
+	cell      bool
+	level     int
+	text      string
+	headingID string
+}
+
+// projection is the plain text of the body, or of another segment, with
+// a map from every rune back to its document index, so matches found in text can be turned
+// into API ranges, and the text style of every rune. Tables and images
+// are the start indexes of the tables and inline objects.
+type projection struct {
+	runes      []rune
+	index      []int
+	styles     []*docs.TextStyle
+	paragraphs []paragraph
+	tables     []int
+	images     []int
+	bodyEnd    int
+	normalized map[bool]*normalized
+}
+
+// match is a half-open document range. Confidence is the similarity of
+// an anchor match, 1 unless it was found by fuzzy matching.
+type match struct {
+	start      int
+	end        int
+	selected   bool
+	confidence float64
+}
+
+// matchOptions are how an anchor is compared with the document.
+type matchOptions struct {
+	caseSensitive bool
+	fuzzy         bool
+	minSimilarity float64
+}
+
+func project(doc *docs.Document) *projection {
+	return projectSegment(doc, "")
+}
+
+// projectSegment projects segmentID of doc, the body when it is empty.
+// Headers, footers and footnotes are indexed from 0.
+func projectSegment(doc *docs.Document, segmentID string) *projection {
+	content, _ := doc.Segment(segmentID)
+
+	p := &projection{bodyEnd: doc.EndIndex()}
+	if segmentID != "" {
+		p.bodyEnd = 0
+		if n := len(content); n > 0 {
+			p.bodyEnd = content[n-1].EndIndex
+		}
+	}
+
+	p.addContent(content, false)
+
+	return p
+}
+
+func (p *projection) addContent(content []docs.StructuralElement, inCell bool) {
+	lastParagraph := -1
+
+	for _, el := range content {
+		switch {
+		case el.Paragraph != nil:
+			p.addParagraph(el, inCell)
+			lastParagraph = len(p.paragraphs) - 1
+		case el.Table != nil:
+			p.tables = append(p.tables, el.StartIndex)
+
+			for _, row := range el.Table.TableRows {
+				for _, cell := range row.TableCells {
+					p.addContent(cell.Content, true)
+				}
+			}
+		}
+	}
+
+	if lastParagraph >= 0 {
+		p.paragraphs[lastParagraph].last = true
+	}
+}
+
+func (p *projection) addParagraph(el docs.StructuralElement, inCell bool) {
+	empty := true
+	from := len(p.runes)
+
+	for _, pe := range el.Paragraph.Elements {
+		if pe.InlineObjectElement != nil {
+			p.images = append(p.images, pe.StartIndex)
+		}
+
+		if pe.TextRun == nil {
+			continue
+		}
+
+		index := pe.StartIndex
+		for _, r := range pe.TextRun.Content {
+			p.runes = append(p.runes, r)
+			p.index = append(p.index, index)
+			p.styles = append(p.styles, pe.TextRun.TextStyle)
+			index += utf16.RuneLen(r)
+
+			if r != '\n' {
+				empty = false
+			}
+		}
+	}
+
+	para := paragraph{start: el.StartIndex, end: el.EndIndex, empty: empty, cell: inCell}
+
+	if style := el.Paragraph.ParagraphStyle; style != nil && !inCell {
+		para.level = headingLevels[style.NamedStyleType]
+		if para.level > 0 {
+			para.text = strings.TrimSpace(string(p.runes[from:]))
+			para.headingID = style.HeadingID
+		}
+	}
+
+	p.paragraphs = append(p.paragraphs, para)
+}
+
+// headingLevels maps the named heading styles to outline levels.
+var headingLevels = map[string]int{
+	docs.StyleHeading1: 1,
+	docs.StyleHeading2: 2,
+	docs.StyleHeading3: 3,
+	docs.StyleHeading4: 4,
+	docs.StyleHeading5: 5,
+	docs.StyleHeading6: 6,
+}
+
+// indexAt maps rune offset i (which may be one past the end) to a
+// document index.
+func (p *projection) indexAt(i int) int {
+	if i < len(p.index) {
+		return p.index[i]
+	}
+
+	if len(p.index) == 0 {
+		return p.bodyEnd
+	}
+
+	last := len(p.index) - 1
+
+	return p.index[last] + utf16.RuneLen(p.runes[last])
+}
+
+// find returns every non-overlapping occurrence of anchor in the
+// normalized text, so matches may span paragraphs. An anchor that is
+// not found as given is retried without list markers and then, if
+// opts.fuzzy is set, by similarity.
+func (p *projection) find(anchor string, opts matchOptions) ([]match, error) {
+	text := p.normalizedText(opts.caseSensitive)
+	needles := [][]rune{normalizeAnchor(anchor, opts.caseSensitive)}
+
+	if stripped := listMarker.ReplaceAllString(anchor, ""); stripped != anchor {
+		needles = append(needles, normalizeAnchor(stripped, opts.caseSensitive))
+	}
+
+	var matches []match
+
+	for _, needle := range needles {
+		if len(needle) == 0 {
+			continue
+		}
+
+		for _, s := range findExact(text.runes, needle) {
+			m := p.span(text, s[0], s[1])
+			m.confidence = 1
+			matches = append(matches, m)
+		}
+
+		if len(matches) > 0 {
+			return matches, nil
+		}
+	}
+
+	needle := needles[len(needles)-1]
+	if !opts.fuzzy || len(needle) == 0 {
+		return nil, nil
+	}
+
+	if len(needle)*len(text.runes) > maxFuzzyCells {
+		return nil, fmt.Errorf("%w: anchor of %d characters is too long to match fuzzily in this document",
+			ErrInvalidOperation, len(needle))
+	}
+
+	for _, s := range findFuzzy(text.runes, needle, opts.minSimilarity) {
+		m := p.span(text, s.start, s.end)
+		m.confidence = math.Round(s.score*100) / 100
+		matches = append(matches, m)
+	}
+
+	return matches, nil
+}
+
+// normalizedText returns the normalized body text, building it once per
+// case mode.
+func (p *projection) normalizedText(caseSensitive bool) *normalized {
+	if p.normalized == nil {
+		p.normalized = make(map[bool]*normalized)
+	}
+
+	if p.normalized[caseSensitive] == nil {
+		p.normalized[caseSensitive] = normalize(p.runes, caseSensitive)
+	}
+
+	return p.normalized[caseSensitive]
+}
+
+// styleAt returns a copy of the text style at index, the zero style if
+// the text there is unstyled or not text.
+func (p *projection) styleAt(index int) *docs.TextStyle {
+	style := &docs.TextStyle{}
+
+	if i := p.offset(index); i < len(p.index) && p.index[i] == index && p.styles[i] != nil {
+		*style = *p.styles[i]
+	}
+
+	return style
+}
+
+// paragraphAt returns the paragraph containing index.
+func (p *projection) paragraphAt(index int) paragraph {
+	for _, para := range p.paragraphs {
+		if index >= para.start && index < para.end {
+			return para
+		}
+	}
+
+	return p.paragraphs[len(p.paragraphs)-1]
+}
+
+// first and final return the first and last body paragraphs.
+func (p *projection) first() paragraph {
+	return p.paragraphs[0]
+}
+
+func (p *projection) final() paragraph {
+	return p.paragraphs[len(p.paragraphs)-1]
+}
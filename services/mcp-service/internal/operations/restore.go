@@ -0,0 +1,58 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// Restore brings documentID back to the content of snapshot, typically
+// the document as it was before an earlier edit. Only the range that
+// differs is rewritten, within opts.Segment. opts.RequiredRevisionID
+// guards against overwriting changes made since that edit, exactly as it
+// does for Apply.
+func (e *Editor) Restore(ctx context.Context, documentID string, snapshot *docs.Document, opts Options) (*Result, error) {
+	doc, err := e.fetch(ctx, documentID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	segmentID, err := resolveSegment(doc, opts.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartStage(ctx, "plan_requests")
+	requests, err := docs.RestoreSegmentRequests(doc, snapshot, segmentID)
+	span.SetAttributes(attribute.Int("mcp.requests", len(requests)))
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return nil, fmt.Errorf("plan restore: %w", err)
+	}
+
+	result := &Result{
+		Type:         "ok",
+		DocumentID:   documentID,
+		RevisionID:   doc.RevisionID,
+		MatchesFound: 1,
+		PreviewURL:   docs.PreviewURL(documentID),
+		Warnings:     []string{},
+		Segment:      segmentID,
+		Snapshot:     doc,
+	}
+
+	if len(requests) > 0 {
+		result.MatchesChanged = 1
+	}
+
+	if err := e.send(ctx, result, doc, requests, opts); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
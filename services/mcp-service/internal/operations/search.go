@@ -0,0 +1,227 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+// Page sizes of Search.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchQuery is what Search looks for. A literal Query is compared the
+// way edit anchors are, so a match's Occurrence can be passed on to an
+// edit with the same anchor and section. With Regex set, Query is an RE2
+// expression over the document text, in which paragraphs end with a
+// newline. Section limits the search to a section body. Offset and Limit
+// select the page of matches returned. TabID is checked as Options.TabID
+// is for edits.
+type SearchQuery struct {
+	Query         string
+	Regex         bool
+	CaseSensitive bool
+	Section       string
+	Offset        int
+	Limit         int
+	TabID         string
+}
+
+// SearchMatch is one match of a search. Paragraph is the text of the
+// paragraph the match starts in and HeadingPath the section path of the
+// heading above it, empty before the first heading.
+type SearchMatch struct {
+	Occurrence    int    `json:"occurrence"`
+	StartIndex    int    `json:"start_index"`
+	EndIndex      int    `json:"end_index"`
+	Text          string `json:"text"`
+	Paragraph     string `json:"paragraph"`
+	HeadingPath   string `json:"heading_path"`
+	ContextBefore string `json:"context_before"`
+	ContextAfter  string `json:"context_after"`
+}
+
+// SearchResult is one page of the matches of a search. Total counts
+// every match; NextOffset is set when more pages follow.
+type SearchResult struct {
+	Type       string        `json:"type"`
+	DocumentID string        `json:"docId"`
+	RevisionID string        `json:"revisionId,omitempty"`
+	Query      string        `json:"query"`
+	Total      int           `json:"total"`
+	Offset     int           `json:"offset"`
+	Limit      int           `json:"limit"`
+	NextOffset *int          `json:"next_offset,omitempty"`
+	Matches    []SearchMatch `json:"matches"`
+	PreviewURL string        `json:"preview_url"`
+}
+
+// Search finds query in documentID without changing it.
+func (e *Editor) Search(ctx context.Context, documentID string, query SearchQuery) (*SearchResult, error) {
+	if query.Query == "" {
+		return nil, fmt.Errorf("%w: search query is empty", ErrInvalidOperation)
+	}
+
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset %d is negative", ErrInvalidOperation, query.Offset)
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = DefaultSearchLimit
+	case query.Limit > MaxSearchLimit:
+		query.Limit = MaxSearchLimit
+	}
+
+	doc, err := e.fetch(ctx, documentID, Options{TabID: query.TabID})
+	if err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartStage(ctx, "search", attribute.Bool("mcp.search.regex", query.Regex))
+	proj := project(doc)
+	matches, err := proj.search(query)
+	tracing.EndStage(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchResult{
+		Type:       "ok",
+		DocumentID: documentID,
+		RevisionID: doc.RevisionID,
+		Query:      query.Query,
+		Total:      len(matches),
+		Offset:     query.Offset,
+		Limit:      query.Limit,
+		Matches:    []SearchMatch{},
+		PreviewURL: docs.PreviewURL(documentID),
+	}
+
+	outline := proj.outline()
+	end := min(query.Offset+query.Limit, len(matches))
+
+	for i := query.Offset; i < end; i++ {
+		result.Matches = append(result.Matches, proj.searchMatch(outline, i+1, matches[i]))
+	}
+
+	if end < len(matches) {
+		result.NextOffset = &end
+	}
+
+	return result, nil
+}
+
+// search returns every match of query in document order.
+func (p *projection) search(query SearchQuery) ([]match, error) {
+	var scope section
+
+	if query.Section != "" {
+		var err error
+		if scope, err = p.section(0, query.Section); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		matches []match
+		err     error
+	)
+
+	if query.Regex {
+		matches, err = p.findRegex(query.Query, query.CaseSensitive)
+	} else {
+		matches, err = p.find(query.Query, matchOptions{caseSensitive: query.CaseSensitive})
+	}
+
+	if err != nil || query.Section == "" {
+		return matches, err
+	}
+
+	var inside []match
+
+	for _, m := range matches {
+		if scope.contains(m) {
+			inside = append(inside, m)
+		}
+	}
+
+	return inside, nil
+}
+
+// findRegex returns the non-empty matches of expr in the body text.
+func (p *projection) findRegex(expr string, caseSensitive bool) ([]match, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid regular expression: %v", ErrInvalidOperation, err)
+	}
+
+	if !caseSensitive {
+		re = regexp.MustCompile("(?i)" + expr)
+	}
+
+	text := string(p.runes)
+
+	// runeAt maps byte offsets of text to rune offsets
+	runeAt := make([]int, len(text)+1)
+	for i, offset := 0, 0; offset < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		for b := offset; b < offset+size; b++ {
+			runeAt[b] = i
+		}
+		offset += size
+		runeAt[offset] = i + 1
+	}
+
+	var matches []match
+
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+
+		matches = append(matches, match{
+			start:      p.indexAt(runeAt[loc[0]]),
+			end:        p.indexAt(runeAt[loc[1]]),
+			confidence: 1,
+		})
+	}
+
+	return matches, nil
+}
+
+// searchMatch describes match number occurrence.
+func (p *projection) searchMatch(outline []Heading, occurrence int, m match) SearchMatch {
+	preview := p.preview(0, "", occurrence, m)
+	para := p.paragraphAt(m.start)
+
+	out := SearchMatch{
+		Occurrence:    occurrence,
+		StartIndex:    m.start,
+		EndIndex:      m.end,
+		Text:          preview.Text,
+		Paragraph:     strings.TrimSuffix(string(p.runes[p.offset(para.start):p.offset(para.end)]), "\n"),
+		ContextBefore: preview.ContextBefore,
+		ContextAfter:  preview.ContextAfter,
+	}
+
+	// Headings are in document order and sections nest, so the last one
+	// containing the match is the innermost
+	for _, h := range outline {
+		if h.StartIndex <= m.start && m.start < h.EndIndex {
+			out.HeadingPath = h.Path
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,104 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+const searchContent = "Intro mentions Alpha.\n\n# Install\n\n## Linux\n\nRun alpha setup.\n\n## Mac\n\nRun ALPHA setup too."
+
+func TestORPHAN_Editor_Search_ReportsParagraphAndHeadingPath(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, searchContent)
+
+	// Act
+	result, err := editor.Search(context.Background(), docID, operations.SearchQuery{Query: "alpha"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Nil(t, result.NextOffset)
+	assert.Equal(t, get(t, store).RevisionID, result.RevisionID)
+	require.Len(t, result.Matches, 3)
+
+	first, second := result.Matches[0], result.Matches[1]
+	assert.Equal(t, 1, first.Occurrence)
+	assert.Equal(t, "Alpha", first.Text)
+	assert.Equal(t, "Intro mentions Alpha.", first.Paragraph)
+	assert.Empty(t, first.HeadingPath)
+	assert.Equal(t, "Run alpha setup.", second.Paragraph)
+	assert.Equal(t, "Install > Linux", second.HeadingPath)
+	assert.Equal(t, "Run ", second.ContextBefore)
+	assert.Equal(t, "Install > Mac", result.Matches[2].HeadingPath)
+}
+
+func TestORPHAN_Editor_Search_OccurrenceCarriesOverToEdits(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, searchContent)
+	found, err := editor.Search(context.Background(), docID, operations.SearchQuery{Query: "alpha", Section: "Install"})
+	require.NoError(t, err)
+	require.Equal(t, 2, found.Total)
+	mac := found.Matches[1]
+
+	// Act
+	_, err = editor.Apply(context.Background(), docID, []operations.Operation{{
+		Mode: operations.ModeReplaceMatch, AnchorText: "alpha", Section: "Install", Content: "Beta",
+		Occurrence: operations.Occurrence{Indexes: []int{mac.Occurrence}},
+	}}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Install > Mac", mac.HeadingPath)
+	assert.Contains(t, get(t, store).Text(), "Run Beta setup too.")
+	assert.Contains(t, get(t, store).Text(), "Run alpha setup.")
+}
+
+func TestORPHAN_Editor_SearchRegex_PagesThroughMatches(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, searchContent)
+
+	// Act
+	page, err := editor.Search(context.Background(), docID, operations.SearchQuery{
+		Query: `run \w+`, Regex: true, Offset: 1, Limit: 1,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Matches, 1)
+	assert.Equal(t, 2, page.Matches[0].Occurrence)
+	assert.Equal(t, "Run ALPHA", page.Matches[0].Text)
+	assert.Nil(t, page.NextOffset)
+}
+
+func TestORPHAN_Editor_SearchRegex_CaseSensitive_FirstPageHasNextOffset(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, searchContent)
+
+	// Act
+	page, err := editor.Search(context.Background(), docID, operations.SearchQuery{
+		Query: `[A-Z][a-z]+`, Regex: true, CaseSensitive: true, Limit: 2,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Intro", "Alpha"}, []string{page.Matches[0].Text, page.Matches[1].Text})
+	require.NotNil(t, page.NextOffset)
+	assert.Equal(t, 2, *page.NextOffset)
+}
+
+func TestORPHAN_Editor_Search_InvalidRegex_IsInvalid(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, searchContent)
+
+	// Act
+	_, err := editor.Search(context.Background(), docID, operations.SearchQuery{Query: "(", Regex: true})
+
+	// Assert
+	require.ErrorIs(t, err, operations.ErrInvalidOperation)
+}
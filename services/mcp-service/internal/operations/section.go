@@ -0,0 +1,173 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/markdown"
+)
+
+// PathSeparator joins heading texts into a section path.
+const PathSeparator = " > "
+
+// Heading is one entry of a document outline. StartIndex is where the
+// heading paragraph starts and EndIndex where its section ends: at the
+// next heading of the same or a higher level, or at the end of the body.
+type Heading struct {
+	Level      int    `json:"level"`
+	Text       string `json:"text"`
+	Path       string `json:"path"`
+	HeadingID  string `json:"headingId,omitempty"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+
+	paragraph int
+}
+
+// Outline returns the headings of doc in document order.
+func Outline(doc *docs.Document) []Heading {
+	return project(doc).outline()
+}
+
+// section is the resolved scope of a heading path. Its body is
+// [start, end): everything after the heading paragraph up to the end of
+// the section.
+type section struct {
+	heading paragraph
+	start   int
+	end     int
+}
+
+func (p *projection) outline() []Heading {
+	var (
+		headings []Heading
+		parents  []Heading
+	)
+
+	for i, para := range p.paragraphs {
+		if para.level == 0 {
+			continue
+		}
+
+		for len(parents) > 0 && parents[len(parents)-1].Level >= para.level {
+			parents = parents[:len(parents)-1]
+		}
+
+		path := para.text
+		if len(parents) > 0 {
+			path = parents[len(parents)-1].Path + PathSeparator + para.text
+		}
+
+		h := Heading{
+			Level: para.level, Text: para.text, Path: path, HeadingID: para.headingID,
+			StartIndex: para.start, EndIndex: p.bodyEnd, paragraph: i,
+		}
+
+		for j := range headings {
+			if headings[j].EndIndex == p.bodyEnd && headings[j].Level >= para.level {
+				headings[j].EndIndex = para.start
+			}
+		}
+
+		headings = append(headings, h)
+		parents = append(parents, h)
+	}
+
+	return headings
+}
+
+// section resolves path, heading texts separated by ">", against the
+// outline. Each step matches a heading nested anywhere below the
+// previous one, ignoring case and repeated spaces; a step may also be a
+// heading ID such as "h.4f2k9". The path must name exactly one heading.
+func (p *projection) section(index int, path string) (section, error) {
+	outline := p.outline()
+	candidates := []Heading{{Level: 0, EndIndex: p.bodyEnd}}
+
+	for _, step := range strings.Split(path, ">") {
+		step = normalizeHeading(step)
+
+		var next []Heading
+
+		seen := make(map[int]bool)
+
+		for _, parent := range candidates {
+			for _, h := range outline {
+				if h.Level <= parent.Level || h.StartIndex < parent.StartIndex || h.StartIndex >= parent.EndIndex {
+					continue
+				}
+
+				if !seen[h.paragraph] && (normalizeHeading(h.Text) == step || strings.EqualFold(h.HeadingID, step)) {
+					seen[h.paragraph] = true
+					next = append(next, h)
+				}
+			}
+		}
+
+		candidates = next
+	}
+
+	switch len(candidates) {
+	case 0:
+		return section{}, sectionError(index, CodeSectionNotFound,
+			fmt.Sprintf("Section '%s' not found in the document outline.", path), outline)
+	case 1:
+		h := candidates[0]
+		heading := p.paragraphs[h.paragraph]
+
+		return section{heading: heading, start: heading.end, end: h.EndIndex}, nil
+	default:
+		return section{}, sectionError(index, CodeAmbiguousSection,
+			fmt.Sprintf("Section '%s' matches %d headings; give a longer path.", path, len(candidates)), outline)
+	}
+}
+
+func normalizeHeading(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// contains reports whether m lies within the section body.
+func (s section) contains(m match) bool {
+	return m.start >= s.start && m.end <= s.end
+}
+
+// paragraphs returns the paragraphs of the section body, leaving out
+// those inside tables.
+func (s section) paragraphs(p *projection) []paragraph {
+	var out []paragraph
+
+	for _, para := range p.paragraphs {
+		if !para.cell && para.start >= s.start && para.end <= s.end {
+			out = append(out, para)
+		}
+	}
+
+	return out
+}
+
+// sectionTarget places replace_all, prepend and append within a
+// section: replace_all swaps the body under the heading, prepend goes
+// right after the heading and append right before the next section.
+func sectionTarget(p *projection, s section, mode Mode) target {
+	body := s.paragraphs(p)
+	last := s.heading
+	if len(body) > 0 {
+		last = body[len(body)-1]
+	}
+
+	switch {
+	case mode == ModeReplaceAll && len(body) > 0:
+		return target{start: s.start, end: last.end - 1, intoEmpty: true}
+	case mode == ModePrepend && len(body) > 0:
+		return target{start: s.start, end: s.start, placement: markdown.Placement{TrailingNewline: true}}
+	case last.last:
+		if last.empty && len(body) > 0 {
+			return target{start: last.start, end: last.start, intoEmpty: true}
+		}
+
+		return target{start: last.end - 1, end: last.end - 1, placement: markdown.Placement{LeadingNewline: true}}
+	default:
+		return target{start: last.end, end: last.end, placement: markdown.Placement{TrailingNewline: true}}
+	}
+}
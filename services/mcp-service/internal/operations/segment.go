@@ -0,0 +1,81 @@
+package operations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+)
+
+// Segment names an edit can target besides segment IDs.
+const (
+	SegmentBody   = "body"
+	SegmentHeader = "header"
+	SegmentFooter = "footer"
+)
+
+// resolveSegment maps the segment an edit targets to its segment ID: ""
+// for the body, the default header or footer ID for "header" and
+// "footer", and a header, footer or footnote ID of doc as it is.
+func resolveSegment(doc *docs.Document, name string) (string, error) {
+	var style docs.DocumentStyle
+	if doc.DocumentStyle != nil {
+		style = *doc.DocumentStyle
+	}
+
+	switch name {
+	case "", SegmentBody:
+		return "", nil
+	case SegmentHeader:
+		if style.DefaultHeaderID != "" {
+			return style.DefaultHeaderID, nil
+		}
+
+		return "", segmentNotFound(doc, "The document has no header; add one in Google Docs first.")
+	case SegmentFooter:
+		if style.DefaultFooterID != "" {
+			return style.DefaultFooterID, nil
+		}
+
+		return "", segmentNotFound(doc, "The document has no footer; add one in Google Docs first.")
+	}
+
+	if _, ok := doc.Segment(name); !ok {
+		return "", segmentNotFound(doc, fmt.Sprintf("Segment '%s' not found in the document.", name))
+	}
+
+	return name, nil
+}
+
+// segments lists the segment names and IDs doc accepts.
+func segments(doc *docs.Document) []string {
+	names := []string{SegmentBody}
+
+	if style := doc.DocumentStyle; style != nil {
+		if style.DefaultHeaderID != "" {
+			names = append(names, SegmentHeader)
+		}
+
+		if style.DefaultFooterID != "" {
+			names = append(names, SegmentFooter)
+		}
+	}
+
+	var ids []string
+
+	for id := range doc.Headers {
+		ids = append(ids, id)
+	}
+
+	for id := range doc.Footers {
+		ids = append(ids, id)
+	}
+
+	for id := range doc.Footnotes {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return append(names, ids...)
+}
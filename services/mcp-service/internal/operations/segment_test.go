@@ -0,0 +1,83 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+// addHeader gives docID an empty default header and returns its ID.
+func addHeader(t *testing.T, store *docs.MemoryStore) string {
+	t.Helper()
+
+	resp, err := store.BatchUpdate(context.Background(), docID, []docs.Request{
+		{CreateHeader: &docs.CreateHeaderRequest{Type: docs.HeaderFooterDefault}},
+	}, nil)
+	require.NoError(t, err)
+
+	ids := resp.CreatedIDs("createHeader")
+	require.Len(t, ids, 1)
+
+	return ids[0]
+}
+
+func TestORPHAN_Editor_HeaderSegment_EditsHeaderOnly(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Body text")
+	headerID := addHeader(t, store)
+
+	// Act
+	result, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "Quarterly **report**"},
+	}, operations.Options{Segment: operations.SegmentHeader})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, headerID, result.Segment)
+
+	doc := get(t, store)
+	assert.Equal(t, "Body text\n", doc.Text())
+	assert.Equal(t, "Quarterly report\n", doc.SegmentText(headerID))
+}
+
+func TestORPHAN_Editor_Footnote_CreatesNativeFootnoteWithContent(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Intro")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "Revenue grew[^1].\n\n[^1]: Audited *figures*."},
+	}, operations.Options{})
+
+	// Assert
+	require.NoError(t, err)
+
+	doc := get(t, store)
+	require.Len(t, doc.Footnotes, 1)
+	assert.Equal(t, "Intro\nRevenue grew"+string(docs.Placeholder)+".\n", doc.Text())
+
+	for id := range doc.Footnotes {
+		assert.Equal(t, " Audited figures.\n", doc.SegmentText(id))
+	}
+}
+
+func TestORPHAN_Editor_UnknownSegment_ListsSegments(t *testing.T) {
+	// Arrange
+	_, editor := seed(t, "Body text")
+
+	// Act
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeAppend, Content: "x"},
+	}, operations.Options{Segment: operations.SegmentFooter})
+
+	// Assert
+	var opErr *operations.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, operations.CodeSegmentNotFound, opErr.Code)
+	assert.Equal(t, []string{operations.SegmentBody}, opErr.Segments)
+}
@@ -0,0 +1,163 @@
+package operations_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/docs"
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/operations"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// renderStyles prints one line per paragraph, writing styled runs as
+// [text]{attributes}.
+func renderStyles(doc *docs.Document) string {
+	var b strings.Builder
+
+	for _, el := range doc.Body.Content {
+		if el.Paragraph == nil {
+			continue
+		}
+
+		for _, pe := range el.Paragraph.Elements {
+			if pe.TextRun == nil {
+				continue
+			}
+
+			text := strings.TrimSuffix(pe.TextRun.Content, "\n")
+			if attrs := styleAttributes(pe.TextRun.TextStyle); attrs != "" && text != "" {
+				fmt.Fprintf(&b, "[%s]{%s}", text, attrs)
+			} else {
+				b.WriteString(text)
+			}
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func styleAttributes(style *docs.TextStyle) string {
+	if style == nil {
+		return ""
+	}
+
+	var attrs []string
+
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"bold", style.Bold}, {"italic", style.Italic}, {"underline", style.Underline}, {"strikethrough", style.Strikethrough},
+	} {
+		if flag.set {
+			attrs = append(attrs, flag.name)
+		}
+	}
+
+	if style.WeightedFontFamily != nil {
+		attrs = append(attrs, "font="+style.WeightedFontFamily.FontFamily)
+	}
+
+	if style.FontSize != nil {
+		attrs = append(attrs, fmt.Sprintf("size=%g", style.FontSize.Magnitude))
+	}
+
+	if c := style.ForegroundColor; c != nil && c.Color != nil && c.Color.RGBColor != nil {
+		rgb := c.Color.RGBColor
+		attrs = append(attrs, fmt.Sprintf("color=#%02X%02X%02X",
+			int(math.Round(rgb.Red*255)), int(math.Round(rgb.Green*255)), int(math.Round(rgb.Blue*255))))
+	}
+
+	if style.Link != nil {
+		attrs = append(attrs, "link="+style.Link.URL)
+	}
+
+	return strings.Join(attrs, " ")
+}
+
+// assertGolden compares the rendered styles of the test document with
+// testdata/styles/<name>.golden; go test -update rewrites the file.
+func assertGolden(t *testing.T, store *docs.MemoryStore, name string) {
+	t.Helper()
+
+	got := renderStyles(get(t, store))
+	path := filepath.Join("testdata", "styles", name+".golden")
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o600))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
+
+func replaceMatch(t *testing.T, editor *operations.Editor, anchor, content string) {
+	t.Helper()
+
+	_, err := editor.Apply(context.Background(), docID, []operations.Operation{
+		{Mode: operations.ModeReplaceMatch, AnchorText: anchor, Content: content},
+	}, operations.Options{})
+	require.NoError(t, err)
+}
+
+func TestORPHAN_Golden_ReplaceInsideBoldLink_KeepsLinkAndBold(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "See [**the release notes**](https://example.com/notes) today.")
+
+	// Act
+	replaceMatch(t, editor, "release", "latest")
+
+	// Assert
+	assertGolden(t, store, "inside_bold_link")
+}
+
+func TestORPHAN_Golden_ReplaceWholeStyledRun_KeepsItsStyle(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Plain *emphasis* and ~~gone~~ plain")
+
+	// Act
+	replaceMatch(t, editor, "emphasis", "stress")
+	replaceMatch(t, editor, "gone", "struck")
+
+	// Assert
+	assertGolden(t, store, "whole_styled_run")
+}
+
+func TestORPHAN_Golden_ExplicitFormatting_ReplacesInheritedStyle(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Read **bold words** here")
+
+	// Act
+	replaceMatch(t, editor, "bold", "`code` and plain")
+
+	// Assert
+	assertGolden(t, store, "explicit_formatting")
+}
+
+func TestORPHAN_Golden_ExplicitFormatting_KeepsColorAndSize(t *testing.T) {
+	// Arrange
+	store, editor := seed(t, "Status: alert raised")
+	color, size := "#CC0000", 14.0
+	_, err := editor.Format(context.Background(), docID, operations.Selection{AnchorText: "alert"},
+		operations.Format{Color: &color, FontSize: &size}, operations.Options{})
+	require.NoError(t, err)
+
+	// Act
+	replaceMatch(t, editor, "alert", "**warning**")
+
+	// Assert
+	assertGolden(t, store, "color_and_size")
+}
@@ -0,0 +1,148 @@
+// Package origin validates the Origin header of MCP requests. A page in
+// the user's browser can reach a server it should not talk to through
+// DNS rebinding: its own host name starts resolving to the server's
+// address, so the request passes as same-origin while still carrying
+// the page's Origin. Only an allowlist of origins stops that.
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidOrigin is returned for allowlist entries that are not a
+// scheme://host[:port] origin.
+var ErrInvalidOrigin = errors.New("invalid origin")
+
+// anyPort in an allowlist entry matches every port of the host.
+const anyPort = "*"
+
+// DefaultLocal admits browser clients served from this machine only, on
+// any port. It applies when no allowlist is configured.
+const DefaultLocal = "http://localhost:*,https://localhost:*," +
+	"http://127.0.0.1:*,https://127.0.0.1:*," +
+	"http://[::1]:*,https://[::1]:*"
+
+// Allowlist decides which Origin header values are accepted. Entries are
+// origins such as https://app.example.com, http://localhost:3000 or
+// http://localhost:* for any port; "*" accepts every origin and is only
+// meant for development.
+type Allowlist struct {
+	any     bool
+	origins []entry
+}
+
+type entry struct {
+	scheme string
+	host   string
+	port   string
+}
+
+// Parse reads a comma-separated allowlist.
+func Parse(raw string) (*Allowlist, error) {
+	list := &Allowlist{}
+
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+
+		switch item {
+		case "":
+			continue
+		case "*":
+			list.any = true
+
+			continue
+		}
+
+		e, err := parseEntry(item)
+		if err != nil {
+			return nil, err
+		}
+
+		list.origins = append(list.origins, e)
+	}
+
+	return list, nil
+}
+
+// Allowed reports whether origin, the value of a request's Origin
+// header, is on the list. Opaque origins ("null") and anything that is
+// not a plain origin are only accepted by "*".
+func (l *Allowlist) Allowed(origin string) bool {
+	if l.any {
+		return true
+	}
+
+	got, err := parseEntry(origin)
+	if err != nil || got.port == anyPort {
+		return false
+	}
+
+	for _, want := range l.origins {
+		if want.scheme == got.scheme && want.host == got.host &&
+			(want.port == anyPort || want.port == got.port) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String lists the entries as configured, for logging.
+func (l *Allowlist) String() string {
+	items := make([]string, 0, len(l.origins)+1)
+	if l.any {
+		items = append(items, "*")
+	}
+
+	for _, e := range l.origins {
+		host := e.host
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		items = append(items, e.scheme+"://"+host+":"+e.port)
+	}
+
+	return strings.Join(items, ",")
+}
+
+// parseEntry splits an origin into its parts, lower-cased and with the
+// scheme's default port filled in.
+func parseEntry(raw string) (entry, error) {
+	// url.Parse rejects the wildcard port, so it is swapped for a
+	// placeholder and restored after parsing
+	parsed := raw
+	wildcard := strings.HasSuffix(raw, ":"+anyPort)
+	if wildcard {
+		parsed = strings.TrimSuffix(raw, anyPort) + "1"
+	}
+
+	u, err := url.Parse(parsed)
+	if err != nil || u.Host == "" || u.Hostname() == "" || u.User != nil ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return entry{}, fmt.Errorf("%w: %q", ErrInvalidOrigin, raw)
+	}
+
+	e := entry{
+		scheme: strings.ToLower(u.Scheme),
+		host:   strings.ToLower(u.Hostname()),
+		port:   u.Port(),
+	}
+
+	switch {
+	case wildcard:
+		e.port = anyPort
+	case e.port != "":
+	case e.scheme == "http":
+		e.port = "80"
+	case e.scheme == "https":
+		e.port = "443"
+	default:
+		return entry{}, fmt.Errorf("%w: %q has no port", ErrInvalidOrigin, raw)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,121 @@
+package origin_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/origin"
+)
+
+// decide runs every origin through list.
+func decide(list *origin.Allowlist, origins ...string) map[string]bool {
+	decisions := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		decisions[o] = list.Allowed(o)
+	}
+
+	return decisions
+}
+
+func TestORPHAN_Allowlist_AcceptsListedOriginsOnly(t *testing.T) {
+	// Arrange
+	list, err := origin.Parse("https://app.example.com, http://localhost:3000")
+	require.NoError(t, err)
+
+	// Act
+	decisions := decide(list,
+		"https://app.example.com",
+		"https://APP.example.com:443",
+		"http://localhost:3000",
+		"http://app.example.com",
+		"https://app.example.com:8443",
+		"http://localhost:3001",
+		"https://evil.example.com",
+	)
+
+	// Assert
+	assert.Equal(t, map[string]bool{
+		"https://app.example.com":      true,
+		"https://APP.example.com:443":  true,
+		"http://localhost:3000":        true,
+		"http://app.example.com":       false,
+		"https://app.example.com:8443": false,
+		"http://localhost:3001":        false,
+		"https://evil.example.com":     false,
+	}, decisions)
+}
+
+func TestORPHAN_Allowlist_RebindingOrigins_AreRejected(t *testing.T) {
+	// Arrange
+	list, err := origin.Parse(origin.DefaultLocal)
+	require.NoError(t, err)
+
+	// Act
+	// A rebound host name resolves to this machine but keeps its own origin
+	decisions := decide(list,
+		"http://attacker.example:8081",
+		"http://localhost.attacker.example:8081",
+		"http://127.0.0.1.nip.io:8081",
+		"null",
+		"file://",
+		"http://localhost:6274",
+		"http://127.0.0.1",
+		"http://[::1]:3000",
+	)
+
+	// Assert
+	assert.Equal(t, map[string]bool{
+		"http://attacker.example:8081":           false,
+		"http://localhost.attacker.example:8081": false,
+		"http://127.0.0.1.nip.io:8081":           false,
+		"null":                                   false,
+		"file://":                                false,
+		"http://localhost:6274":                  true,
+		"http://127.0.0.1":                       true,
+		"http://[::1]:3000":                      true,
+	}, decisions)
+}
+
+func TestORPHAN_Allowlist_Wildcard_AcceptsEverything(t *testing.T) {
+	// Arrange
+	list, err := origin.Parse("*")
+	require.NoError(t, err)
+
+	// Act
+	decisions := decide(list, "https://anything.example", "null")
+
+	// Assert
+	assert.Equal(t, map[string]bool{"https://anything.example": true, "null": true}, decisions)
+}
+
+func TestORPHAN_Allowlist_MalformedHeader_IsRejected(t *testing.T) {
+	// Arrange
+	list, err := origin.Parse("http://localhost:*")
+	require.NoError(t, err)
+
+	// Act
+	decisions := decide(list,
+		"http://localhost:*",
+		"http://localhost:3000/path",
+		"http://user@localhost:3000",
+		"localhost:3000",
+	)
+
+	// Assert
+	assert.Equal(t, map[string]bool{
+		"http://localhost:*":         false,
+		"http://localhost:3000/path": false,
+		"http://user@localhost:3000": false,
+		"localhost:3000":             false,
+	}, decisions)
+}
+
+func TestORPHAN_Parse_InvalidEntry_ReturnsError(t *testing.T) {
+	// Act
+	_, err := origin.Parse("https://app.example.com,app.example.com")
+
+	// Assert
+	require.ErrorIs(t, err, origin.ErrInvalidOrigin)
+}
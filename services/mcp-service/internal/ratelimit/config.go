@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Backend names accepted by Config.Backend.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// ErrUnknownBackend signals a Config.Backend value that has no Store.
+var ErrUnknownBackend = errors.New("unknown rate limit backend")
+
+// Config selects the bucket store and the rule applied to each scope.
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	Backend  string `json:"backend"`
+	User     Rule   `json:"user"`
+	Session  Rule   `json:"session"`
+	Document Rule   `json:"document"`
+}
+
+// DefaultConfig returns limits generous enough for interactive use while
+// still stopping a runaway agent. The document rule is the tightest
+// because it maps most directly onto Google Docs write quota.
+func DefaultConfig() Config {
+	return Config{
+		Enabled:  true,
+		Backend:  BackendMemory,
+		User:     Rule{Rate: 10, Burst: 30},
+		Session:  Rule{Rate: 5, Burst: 20},
+		Document: Rule{Rate: 1, Burst: 10},
+	}
+}
+
+// RuleFor returns the rule configured for scope.
+func (c Config) RuleFor(scope Scope) Rule {
+	switch scope {
+	case ScopeUser:
+		return c.User
+	case ScopeSession:
+		return c.Session
+	case ScopeDocument:
+		return c.Document
+	default:
+		return Rule{}
+	}
+}
+
+// LoadConfig starts from DefaultConfig, overlays the JSON file named by
+// RATE_LIMIT_CONFIG_FILE when set, then applies individual environment
+// overrides:
+//
+//	RATE_LIMIT_ENABLED, RATE_LIMIT_BACKEND,
+//	RATE_LIMIT_{USER,SESSION,DOCUMENT}_{RATE,BURST}
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	if path := os.Getenv("RATE_LIMIT_CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read rate limit config: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse rate limit config %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	switch cfg.Backend {
+	case BackendMemory, BackendRedis:
+	default:
+		return cfg, fmt.Errorf("%w: %q", ErrUnknownBackend, cfg.Backend)
+	}
+
+	return cfg, nil
+}
+
+func applyEnv(cfg *Config) error {
+	if raw := os.Getenv("RATE_LIMIT_ENABLED"); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("parse RATE_LIMIT_ENABLED: %w", err)
+		}
+
+		cfg.Enabled = enabled
+	}
+
+	if raw := os.Getenv("RATE_LIMIT_BACKEND"); raw != "" {
+		cfg.Backend = raw
+	}
+
+	rules := map[string]*Rule{
+		"USER":     &cfg.User,
+		"SESSION":  &cfg.Session,
+		"DOCUMENT": &cfg.Document,
+	}
+
+	for name, rule := range rules {
+		if err := applyRuleEnv(name, rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyRuleEnv(name string, rule *Rule) error {
+	rateKey := "RATE_LIMIT_" + name + "_RATE"
+	if raw := os.Getenv(rateKey); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", rateKey, err)
+		}
+
+		rule.Rate = rate
+	}
+
+	burstKey := "RATE_LIMIT_" + name + "_BURST"
+	if raw := os.Getenv(burstKey); raw != "" {
+		burst, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", burstKey, err)
+		}
+
+		rule.Burst = burst
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package ratelimit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+)
+
+func TestORPHAN_LoadConfig_EnvOverridesFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	content := `{"backend":"redis","document":{"rate":3,"burst":6}}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	t.Setenv("RATE_LIMIT_CONFIG_FILE", path)
+	t.Setenv("RATE_LIMIT_DOCUMENT_BURST", "9")
+
+	// Act
+	cfg, err := ratelimit.LoadConfig()
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, ratelimit.BackendRedis, cfg.Backend)
+	assert.InDelta(t, 3.0, cfg.Document.Rate, 0)
+	assert.Equal(t, 9, cfg.Document.Burst)
+	assert.Equal(t, ratelimit.DefaultConfig().Session, cfg.Session)
+}
+
+func TestORPHAN_LoadConfig_UnknownBackend_ReturnsError(t *testing.T) {
+	// Arrange
+	t.Setenv("RATE_LIMIT_BACKEND", "memcached")
+
+	// Act
+	_, err := ratelimit.LoadConfig()
+
+	// Assert
+	require.ErrorIs(t, err, ratelimit.ErrUnknownBackend)
+}
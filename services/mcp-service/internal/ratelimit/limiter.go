@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Subject carries the keys a request can be limited by. Empty fields are
+// skipped, so callers only fill in what they know at their layer.
+type Subject struct {
+	UserID     string
+	SessionID  string
+	DocumentID string
+}
+
+// ExceededError is returned when a bucket has no tokens left. It carries
+// enough detail for the caller to build a retry hint.
+type ExceededError struct {
+	Scope      Scope
+	Key        string
+	Rule       Rule
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s %q, retry after %s", e.Scope, e.Key, e.RetryAfter)
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds, as required by
+// the HTTP Retry-After header.
+func (e *ExceededError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Limiter enforces the configured rules against a Store.
+type Limiter struct {
+	cfg   Config
+	store Store
+	now   func() time.Time
+}
+
+// NewLimiter builds a Limiter. A disabled config yields a Limiter that
+// allows everything, so callers never need a nil check.
+func NewLimiter(cfg Config, store Store) *Limiter {
+	return &Limiter{cfg: cfg, store: store, now: time.Now}
+}
+
+// Allow spends one token from every bucket the subject maps to, or from
+// none of them, and returns an *ExceededError naming the exhausted one.
+// Checking all scopes in a single Store.Take means a request denied for
+// its document does not still use up its user and session budgets.
+// Store failures are logged and the request is let through: an
+// unavailable limiter backend must not take the whole service down with
+// it.
+func (l *Limiter) Allow(ctx context.Context, subject Subject) error {
+	if l == nil || !l.cfg.Enabled {
+		return nil
+	}
+
+	checks := []struct {
+		scope Scope
+		key   string
+	}{
+		{ScopeUser, subject.UserID},
+		{ScopeSession, subject.SessionID},
+		{ScopeDocument, subject.DocumentID},
+	}
+
+	var (
+		scopes  []Scope
+		keys    []string
+		buckets []Bucket
+	)
+
+	for _, check := range checks {
+		rule := l.cfg.RuleFor(check.scope)
+		if check.key == "" || !rule.Enabled() {
+			continue
+		}
+
+		scopes = append(scopes, check.scope)
+		keys = append(keys, check.key)
+		buckets = append(buckets, Bucket{Key: string(check.scope) + ":" + check.key, Rule: rule})
+	}
+
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	decision, err := l.store.Take(ctx, buckets, l.now())
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rate limit store unavailable, allowing request")
+
+		return nil
+	}
+
+	if !decision.Allowed {
+		return &ExceededError{
+			Scope:      scopes[decision.Denied],
+			Key:        keys[decision.Denied],
+			Rule:       buckets[decision.Denied].Rule,
+			RetryAfter: decision.RetryAfter,
+		}
+	}
+
+	return nil
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a copy of ctx carrying subject, so a later layer
+// that learns more of it, such as the document a tool edits, can charge
+// every scope in one Allow.
+func WithSubject(ctx context.Context, subject Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFrom returns the subject stored by WithSubject, or an empty one.
+func SubjectFrom(ctx context.Context) Subject {
+	subject, _ := ctx.Value(subjectKey{}).(Subject)
+
+	return subject
+}
@@ -0,0 +1,114 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+)
+
+func TestORPHAN_Limiter_ExhaustedDocumentBucket_ReturnsExceededError(t *testing.T) {
+	// Arrange
+	cfg := ratelimit.DefaultConfig()
+	cfg.Document = ratelimit.Rule{Rate: 1, Burst: 2}
+	limiter := ratelimit.NewLimiter(cfg, ratelimit.NewMemoryStore())
+	subject := ratelimit.Subject{DocumentID: "doc-1"}
+
+	// Act
+	first := limiter.Allow(context.Background(), subject)
+	second := limiter.Allow(context.Background(), subject)
+	third := limiter.Allow(context.Background(), subject)
+
+	// Assert
+	require.NoError(t, first)
+	require.NoError(t, second)
+
+	var exceeded *ratelimit.ExceededError
+	require.ErrorAs(t, third, &exceeded)
+	assert.Equal(t, ratelimit.ScopeDocument, exceeded.Scope)
+	assert.Equal(t, "doc-1", exceeded.Key)
+	assert.Positive(t, exceeded.RetryAfter)
+	assert.Equal(t, 1, exceeded.RetryAfterSeconds())
+}
+
+func TestORPHAN_Limiter_SeparateDocuments_HaveSeparateBuckets(t *testing.T) {
+	// Arrange
+	cfg := ratelimit.DefaultConfig()
+	cfg.Document = ratelimit.Rule{Rate: 1, Burst: 1}
+	limiter := ratelimit.NewLimiter(cfg, ratelimit.NewMemoryStore())
+
+	// Act & Assert
+	require.NoError(t, limiter.Allow(context.Background(), ratelimit.Subject{DocumentID: "doc-a"}))
+	require.NoError(t, limiter.Allow(context.Background(), ratelimit.Subject{DocumentID: "doc-b"}))
+	require.Error(t, limiter.Allow(context.Background(), ratelimit.Subject{DocumentID: "doc-a"}))
+}
+
+func TestORPHAN_Limiter_Disabled_AllowsEverything(t *testing.T) {
+	// Arrange
+	cfg := ratelimit.DefaultConfig()
+	cfg.Enabled = false
+	cfg.Session = ratelimit.Rule{Rate: 1, Burst: 1}
+	limiter := ratelimit.NewLimiter(cfg, ratelimit.NewMemoryStore())
+	subject := ratelimit.Subject{SessionID: "session-1"}
+
+	// Act & Assert
+	for range 5 {
+		require.NoError(t, limiter.Allow(context.Background(), subject))
+	}
+}
+
+func TestORPHAN_Limiter_DeniedDocument_ChargesNoOtherScope(t *testing.T) {
+	// Arrange
+	cfg := ratelimit.DefaultConfig()
+	cfg.Session = ratelimit.Rule{Rate: 0.001, Burst: 2}
+	cfg.Document = ratelimit.Rule{Rate: 0.001, Burst: 1}
+	limiter := ratelimit.NewLimiter(cfg, ratelimit.NewMemoryStore())
+
+	// Act
+	first := limiter.Allow(context.Background(), ratelimit.Subject{SessionID: "s", DocumentID: "doc-a"})
+	denied := limiter.Allow(context.Background(), ratelimit.Subject{SessionID: "s", DocumentID: "doc-a"})
+	other := limiter.Allow(context.Background(), ratelimit.Subject{SessionID: "s", DocumentID: "doc-b"})
+
+	// Assert
+	require.NoError(t, first)
+	var exceeded *ratelimit.ExceededError
+	require.ErrorAs(t, denied, &exceeded)
+	assert.Equal(t, ratelimit.ScopeDocument, exceeded.Scope)
+	require.NoError(t, other)
+}
+
+func TestORPHAN_Limiter_SubjectFrom_ReturnsTheStoredSubject(t *testing.T) {
+	// Arrange
+	subject := ratelimit.Subject{UserID: "u", SessionID: "s"}
+	ctx := ratelimit.WithSubject(context.Background(), subject)
+
+	// Act
+	stored := ratelimit.SubjectFrom(ctx)
+	empty := ratelimit.SubjectFrom(context.Background())
+
+	// Assert
+	assert.Equal(t, subject, stored)
+	assert.Equal(t, ratelimit.Subject{}, empty)
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, []ratelimit.Bucket, time.Time) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("backend down")
+}
+
+func TestORPHAN_Limiter_StoreFailure_FailsOpen(t *testing.T) {
+	// Arrange
+	limiter := ratelimit.NewLimiter(ratelimit.DefaultConfig(), failingStore{})
+
+	// Act
+	err := limiter.Allow(context.Background(), ratelimit.Subject{UserID: "u", SessionID: "s", DocumentID: "d"})
+
+	// Assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval bounds how often MemoryStore scans for idle buckets.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	last    time.Time
+	expires time.Time
+}
+
+// MemoryStore keeps buckets in process memory. It is the default backend
+// and is only correct when a single replica serves all traffic.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty in-memory bucket store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, buckets []Bucket, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	tokens := make([]float64, len(buckets))
+	for i, b := range buckets {
+		tokens[i] = float64(b.Rule.Burst)
+		if state, ok := s.buckets[b.Key]; ok {
+			tokens[i] = refill(state.tokens, state.last, now, b.Rule)
+		}
+	}
+
+	decision := decide(tokens, buckets)
+	if !decision.Allowed {
+		return decision, nil
+	}
+
+	for i, b := range buckets {
+		s.buckets[b.Key] = &bucket{
+			tokens:  tokens[i] - 1,
+			last:    now,
+			expires: now.Add(b.Rule.refillDuration()),
+		}
+	}
+
+	return decision, nil
+}
+
+// Len returns the number of buckets currently tracked.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
+
+// sweep drops buckets that have refilled completely, since a fresh
+// bucket would behave identically. Callers must hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.expires) {
+			delete(s.buckets, key)
+		}
+	}
+}
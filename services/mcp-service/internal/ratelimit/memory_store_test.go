@@ -0,0 +1,74 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+)
+
+func TestORPHAN_MemoryStore_RefillsOverTime(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	rule := ratelimit.Rule{Rate: 2, Burst: 1}
+	start := time.Unix(1_700_000_000, 0)
+
+	// Act
+	first, err := store.Take(context.Background(), []ratelimit.Bucket{{Key: "k", Rule: rule}}, start)
+	require.NoError(t, err)
+	denied, err := store.Take(context.Background(), []ratelimit.Bucket{{Key: "k", Rule: rule}}, start.Add(100*time.Millisecond))
+	require.NoError(t, err)
+	refilled, err := store.Take(context.Background(), []ratelimit.Bucket{{Key: "k", Rule: rule}}, start.Add(600*time.Millisecond))
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 400*time.Millisecond, denied.RetryAfter)
+	assert.True(t, refilled.Allowed)
+}
+
+func TestORPHAN_MemoryStore_SweepsIdleBuckets(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	rule := ratelimit.Rule{Rate: 10, Burst: 10}
+	start := time.Unix(1_700_000_000, 0)
+
+	_, err := store.Take(context.Background(), []ratelimit.Bucket{{Key: "idle", Rule: rule}}, start)
+	require.NoError(t, err)
+
+	// Act
+	_, err = store.Take(context.Background(), []ratelimit.Bucket{{Key: "fresh", Rule: rule}}, start.Add(2*time.Minute))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestORPHAN_MemoryStore_DeniedTake_SpendsNoBucket(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	now := time.Unix(1_700_000_000, 0)
+	session := ratelimit.Bucket{Key: "session:s", Rule: ratelimit.Rule{Rate: 1, Burst: 2}}
+	document := ratelimit.Bucket{Key: "document:d", Rule: ratelimit.Rule{Rate: 1, Burst: 1}}
+
+	// Act
+	first, err := store.Take(context.Background(), []ratelimit.Bucket{session, document}, now)
+	require.NoError(t, err)
+	denied, err := store.Take(context.Background(), []ratelimit.Bucket{session, document}, now)
+	require.NoError(t, err)
+	sessionOnly, err := store.Take(context.Background(), []ratelimit.Bucket{session}, now)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 1, denied.Denied)
+	assert.Equal(t, time.Second, denied.RetryAfter)
+	assert.True(t, sessionOnly.Allowed)
+	assert.Equal(t, 0, sessionOnly.Remaining)
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnexpectedReply signals that the token bucket script returned a
+// reply the store does not know how to decode.
+var ErrUnexpectedReply = errors.New("unexpected reply from rate limit script")
+
+// redisKeyPrefix carries the {ratelimit} hash tag, so on Redis Cluster
+// every bucket lives in one slot and takeScript can touch the user,
+// session and document buckets of a request together. A per-user tag
+// would not do: a document's bucket is shared by every user editing it.
+const redisKeyPrefix = "mcp:{ratelimit}:"
+
+// takeScript performs the same refill-then-spend arithmetic as the
+// memory store atomically on the Redis side, so replicas share one view
+// of every bucket. KEYS are the buckets; ARGV is the time followed by
+// rate, burst and TTL per bucket. Nothing is written when any bucket is
+// exhausted. Times are passed in milliseconds by the caller.
+var takeScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens = {}
+local denied = -1
+local retry = 0
+local remaining = nil
+
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[i * 3 - 1])
+  local burst = tonumber(ARGV[i * 3])
+
+  local state = redis.call('HMGET', key, 'tokens', 'ts')
+  local t = tonumber(state[1])
+  local ts = tonumber(state[2])
+  if t == nil or ts == nil then
+    t = burst
+    ts = now
+  end
+
+  if now > ts then
+    t = t + (now - ts) * rate / 1000
+  end
+  if t > burst then
+    t = burst
+  end
+  tokens[i] = t
+
+  if t < 1 then
+    local wait = math.ceil((1 - t) * 1000 / rate)
+    if denied < 0 or wait > retry then
+      denied = i - 1
+      retry = wait
+    end
+  elseif remaining == nil or t - 1 < remaining then
+    remaining = t - 1
+  end
+end
+
+if denied >= 0 then
+  return {0, denied, retry, 0}
+end
+
+for i, key in ipairs(KEYS) do
+  redis.call('HSET', key, 'tokens', tostring(tokens[i] - 1), 'ts', tostring(now))
+  redis.call('PEXPIRE', key, tonumber(ARGV[i * 3 + 1]))
+end
+
+return {1, -1, 0, math.floor(remaining or 0)}
+`)
+
+// RedisStore keeps buckets in Redis for multi-replica deployments.
+type RedisStore struct {
+	client redis.Scripter
+}
+
+// NewRedisStore returns a store backed by the supplied Redis client.
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take implements Store.
+func (s *RedisStore) Take(ctx context.Context, buckets []Bucket, now time.Time) (Decision, error) {
+	keys := make([]string, len(buckets))
+	args := []interface{}{now.UnixMilli()}
+
+	for i, b := range buckets {
+		ttl := b.Rule.refillDuration() + time.Second
+		keys[i] = redisKeyPrefix + b.Key
+		args = append(args, b.Rule.Rate, b.Rule.Burst, ttl.Milliseconds())
+	}
+
+	reply, err := takeScript.Run(ctx, s.client, keys, args...).Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("run rate limit script: %w", err)
+	}
+
+	if len(reply) != 4 {
+		return Decision{}, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+
+	allowed, okAllowed := reply[0].(int64)
+	denied, okDenied := reply[1].(int64)
+	retryMs, okRetry := reply[2].(int64)
+	remaining, okRemaining := reply[3].(int64)
+
+	if !okAllowed || !okDenied || !okRetry || !okRemaining {
+		return Decision{}, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+
+	if allowed == 1 {
+		return Decision{Allowed: true, Remaining: int(remaining)}, nil
+	}
+
+	return Decision{
+		Allowed:    false,
+		Denied:     int(denied),
+		RetryAfter: time.Duration(retryMs) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/ratelimit"
+)
+
+func TestORPHAN_RedisStore_SharesBucketAcrossStores(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	replicaA := ratelimit.NewRedisStore(client)
+	replicaB := ratelimit.NewRedisStore(client)
+	bucket := []ratelimit.Bucket{{Key: "document:doc-1", Rule: ratelimit.Rule{Rate: 1, Burst: 2}}}
+	now := time.Unix(1_700_000_000, 0)
+
+	// Act
+	first, err := replicaA.Take(context.Background(), bucket, now)
+	require.NoError(t, err)
+	second, err := replicaB.Take(context.Background(), bucket, now)
+	require.NoError(t, err)
+	third, err := replicaA.Take(context.Background(), bucket, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, 500*time.Millisecond, third.RetryAfter)
+	assert.True(t, server.Exists("mcp:{ratelimit}:document:doc-1"))
+}
+
+func TestORPHAN_RedisStore_DeniedTake_SpendsNoBucket(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := ratelimit.NewRedisStore(client)
+	now := time.Unix(1_700_000_000, 0)
+	session := ratelimit.Bucket{Key: "session:s", Rule: ratelimit.Rule{Rate: 1, Burst: 2}}
+	document := ratelimit.Bucket{Key: "document:d", Rule: ratelimit.Rule{Rate: 1, Burst: 1}}
+
+	// Act
+	first, err := store.Take(context.Background(), []ratelimit.Bucket{session, document}, now)
+	require.NoError(t, err)
+	denied, err := store.Take(context.Background(), []ratelimit.Bucket{session, document}, now)
+	require.NoError(t, err)
+	sessionOnly, err := store.Take(context.Background(), []ratelimit.Bucket{session}, now)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 0, first.Remaining)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 1, denied.Denied)
+	assert.Equal(t, time.Second, denied.RetryAfter)
+	assert.True(t, sessionOnly.Allowed)
+	assert.ElementsMatch(t, []string{"mcp:{ratelimit}:session:s", "mcp:{ratelimit}:document:d"}, server.Keys())
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+import "time"
+
+// Scope identifies which dimension of a request a bucket is keyed by.
+type Scope string
+
+const (
+	ScopeUser     Scope = "user"
+	ScopeSession  Scope = "session"
+	ScopeDocument Scope = "document"
+)
+
+// Rule describes one token bucket: Rate tokens are added per second up
+// to a maximum of Burst. A rule with a non-positive Rate or Burst is
+// treated as disabled.
+type Rule struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Enabled reports whether the rule should be enforced.
+func (r Rule) Enabled() bool {
+	return r.Rate > 0 && r.Burst > 0
+}
+
+// refillDuration is how long an empty bucket takes to fill up again.
+// Stores use it to expire state that no longer carries information.
+func (r Rule) refillDuration() time.Duration {
+	return time.Duration(float64(r.Burst) / r.Rate * float64(time.Second))
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Bucket names one token bucket and the rule that refills it.
+type Bucket struct {
+	Key  string
+	Rule Rule
+}
+
+// Decision is the outcome of taking one token from each of a set of
+// buckets. Remaining is what the emptiest bucket has left; when the take
+// is denied, Denied is the index of the bucket that takes longest to
+// refill and RetryAfter is how long that takes.
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	Denied     int
+	RetryAfter time.Duration
+}
+
+// Store holds token bucket state. Take atomically refills every bucket
+// according to its rule, then removes a single token from each only if
+// all of them have one: a denial spends nothing, so one exhausted scope
+// does not drain the others. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	Take(ctx context.Context, buckets []Bucket, now time.Time) (Decision, error)
+}
+
+// refill applies the token bucket arithmetic shared by every store: add
+// tokens for the elapsed time and cap them at burst.
+func refill(tokens float64, last, now time.Time, rule Rule) float64 {
+	if elapsed := now.Sub(last); elapsed > 0 {
+		tokens += elapsed.Seconds() * rule.Rate
+	}
+
+	return math.Min(tokens, float64(rule.Burst))
+}
+
+// decide allows the take when every bucket holds a token, given the
+// refilled token counts in bucket order.
+func decide(tokens []float64, buckets []Bucket) Decision {
+	decision := Decision{Allowed: true, Remaining: math.MaxInt}
+
+	for i, t := range tokens {
+		if t >= 1 {
+			decision.Remaining = min(decision.Remaining, int(t-1))
+
+			continue
+		}
+
+		wait := time.Duration((1 - t) / buckets[i].Rule.Rate * float64(time.Second))
+		if decision.Allowed || wait > decision.RetryAfter {
+			decision = Decision{Allowed: false, Denied: i, RetryAfter: wait}
+		}
+	}
+
+	if !decision.Allowed {
+		decision.Remaining = 0
+	}
+
+	return decision
+}
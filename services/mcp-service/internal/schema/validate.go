@@ -0,0 +1,630 @@
+// Package schema validates tool arguments against the subset of JSON
+// Schema the tool catalog is written in: type, properties, required,
+// additionalProperties, enum, minimum, maximum, minLength, pattern,
+// minItems, items, oneOf and anyOf. Schemas are the same map literals served by
+// tools/list. Validation does not stop at the first problem: every
+// missing, unexpected or mistyped value is reported with its JSON
+// pointer and a hint on how to fix it.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidJSON is returned when the arguments are not JSON at all.
+var ErrInvalidJSON = errors.New("arguments are not valid JSON")
+
+// ErrInvalidSchema is returned when the schema itself is broken, such as
+// a pattern that is not a valid regular expression.
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// patterns caches compiled patterns by their source, so each is compiled
+// once however often a tool is called.
+var patterns sync.Map
+
+// Kinds of Violation.
+const (
+	KindMissing    = "missing"
+	KindUnexpected = "unexpected"
+	KindType       = "type"
+	KindEnum       = "enum"
+	KindRange      = "range"
+	KindLength     = "length"
+	KindFormat     = "format"
+)
+
+// Schema is a JSON Schema as a decoded map or a Go map literal.
+type Schema = map[string]interface{}
+
+// Violation is one way the arguments do not fit the schema. Pointer is
+// the RFC 6901 JSON pointer of the offending value, or of the property
+// that should have been there.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Hint    string `json:"hint"`
+}
+
+// Validate checks raw against schema. Empty arguments count as an empty
+// object.
+func Validate(schema Schema, raw json.RawMessage) ([]Violation, error) {
+	if err := Check(schema); err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		raw = json.RawMessage("{}")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	var violations []Violation
+	validate(schema, value, "", &violations)
+
+	return violations, nil
+}
+
+// Check compiles every pattern in schema, so a catalog with a broken
+// pattern fails at startup and in tests instead of accepting any value.
+func Check(schema Schema) error {
+	return check(schema, "")
+}
+
+func check(schema Schema, pointer string) error {
+	if pattern, ok := schema["pattern"].(string); ok {
+		if _, err := compile(pattern); err != nil {
+			return fmt.Errorf("%w: %s/pattern: %v", ErrInvalidSchema, pointer, err)
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, property := range properties {
+			if nested, ok := property.(map[string]interface{}); ok {
+				if err := check(nested, pointer+"/properties/"+escape(key)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		if err := check(items, pointer+"/items"); err != nil {
+			return err
+		}
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		alternatives, _ := schema[keyword].([]interface{})
+		for i, alternative := range alternatives {
+			if nested, ok := alternative.(map[string]interface{}); ok {
+				if err := check(nested, pointer+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
+
+func validate(schema Schema, value interface{}, pointer string, out *[]Violation) {
+	if alternatives, ok := schema["oneOf"].([]interface{}); ok {
+		validateOneOf(schema, alternatives, value, pointer, out)
+
+		return
+	}
+
+	if want, ok := schema["type"].(string); ok && !hasType(value, want) {
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindType,
+			Message: fmt.Sprintf("%s must be %s, got %s", name(pointer), article(want), typeOf(value)),
+			Hint:    withDescription(schema, fmt.Sprintf("Send %s", describe(schema))),
+		})
+
+		return
+	}
+
+	if enum := stringList(schema["enum"]); enum != nil {
+		if s, ok := value.(string); ok && !contains(enum, s) {
+			*out = append(*out, Violation{
+				Pointer: pointer,
+				Kind:    KindEnum,
+				Message: fmt.Sprintf("%s must be one of %s, got %q", name(pointer), quoteAll(enum), s),
+				Hint:    suggest("Use one of "+quoteAll(enum), s, enum),
+			})
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(schema, v, pointer, out)
+	case []interface{}:
+		validateArray(schema, v, pointer, out)
+	case string:
+		validateString(schema, v, pointer, out)
+	case json.Number:
+		validateNumber(schema, v, pointer, out)
+	}
+
+	if alternatives, ok := schema["anyOf"].([]interface{}); ok {
+		validateAnyOf(alternatives, value, pointer, out)
+	}
+}
+
+func validateObject(schema Schema, object map[string]interface{}, pointer string, out *[]Violation) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, key := range stringList(schema["required"]) {
+		if _, ok := object[key]; ok {
+			continue
+		}
+
+		property, _ := properties[key].(map[string]interface{})
+		*out = append(*out, Violation{
+			Pointer: pointer + "/" + escape(key),
+			Kind:    KindMissing,
+			Message: fmt.Sprintf("missing required property %q", key),
+			Hint:    withDescription(property, fmt.Sprintf("Add %q (%s)", key, describe(property))),
+		})
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	known := make([]string, 0, len(properties))
+	for key := range properties {
+		known = append(known, key)
+	}
+	sort.Strings(known)
+
+	for _, key := range keys {
+		child := pointer + "/" + escape(key)
+
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			validate(property, object[key], child, out)
+
+			continue
+		}
+
+		if closed, ok := schema["additionalProperties"].(bool); ok && !closed {
+			*out = append(*out, Violation{
+				Pointer: child,
+				Kind:    KindUnexpected,
+				Message: fmt.Sprintf("unknown property %q", key),
+				Hint:    suggest("Remove it; accepted properties are "+quoteAll(known), key, known),
+			})
+		}
+	}
+}
+
+func validateArray(schema Schema, items []interface{}, pointer string, out *[]Violation) {
+	if minItems, ok := number(schema["minItems"]); ok && float64(len(items)) < minItems {
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindLength,
+			Message: fmt.Sprintf("%s needs at least %v item(s), got %d", name(pointer), minItems, len(items)),
+			Hint:    withDescription(schema, "Add items to the array"),
+		})
+	}
+
+	if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range items {
+			validate(itemSchema, item, pointer+"/"+strconv.Itoa(i), out)
+		}
+	}
+}
+
+func validateString(schema Schema, s string, pointer string, out *[]Violation) {
+	if minLength, ok := number(schema["minLength"]); ok && float64(len([]rune(s))) < minLength {
+		message := fmt.Sprintf("%s must be at least %v characters, got %d", name(pointer), minLength, len([]rune(s)))
+		hint := fmt.Sprintf("Send at least %v characters", minLength)
+		if minLength == 1 {
+			message = fmt.Sprintf("%s must not be empty", name(pointer))
+			hint = "Send a non-empty string"
+		}
+
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindLength,
+			Message: message,
+			Hint:    withDescription(schema, hint),
+		})
+
+		return
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		// Validate has already checked that every pattern compiles
+		if re, err := compile(pattern); err == nil && !re.MatchString(s) {
+			*out = append(*out, Violation{
+				Pointer: pointer,
+				Kind:    KindFormat,
+				Message: fmt.Sprintf("%s does not match %s", name(pointer), pattern),
+				Hint:    withDescription(schema, "Send a value matching "+pattern),
+			})
+		}
+	}
+}
+
+func validateNumber(schema Schema, n json.Number, pointer string, out *[]Violation) {
+	f, err := n.Float64()
+	if err != nil {
+		return
+	}
+
+	if minimum, ok := number(schema["minimum"]); ok && f < minimum {
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindRange,
+			Message: fmt.Sprintf("%s must be at least %v, got %v", name(pointer), minimum, n),
+			Hint:    withDescription(schema, fmt.Sprintf("Send %s", describe(schema))),
+		})
+	}
+
+	if maximum, ok := number(schema["maximum"]); ok && f > maximum {
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindRange,
+			Message: fmt.Sprintf("%s must be at most %v, got %v", name(pointer), maximum, n),
+			Hint:    withDescription(schema, fmt.Sprintf("Send %s", describe(schema))),
+		})
+	}
+}
+
+// validateOneOf accepts value when exactly one alternative matches it.
+// A failure is reported as one violation naming every alternative, since
+// the nested problems of each would only confuse.
+func validateOneOf(schema Schema, alternatives []interface{}, value interface{}, pointer string, out *[]Violation) {
+	matches := 0
+	described := make([]string, 0, len(alternatives))
+
+	for _, alternative := range alternatives {
+		alt, ok := alternative.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var nested []Violation
+		validate(alt, value, pointer, &nested)
+		if len(nested) == 0 {
+			matches++
+		}
+
+		described = append(described, describe(alt))
+	}
+
+	if matches == 1 {
+		return
+	}
+
+	*out = append(*out, Violation{
+		Pointer: pointer,
+		Kind:    KindType,
+		Message: fmt.Sprintf("%s must be %s, got %s", name(pointer), strings.Join(described, " or "), preview(value)),
+		Hint:    withDescription(schema, "Send "+strings.Join(described, " or ")),
+	})
+}
+
+// validateAnyOf accepts value when at least one alternative matches it.
+// Alternatives that only require properties, the way a schema asks for
+// one of several properties, fail as a single missing property naming
+// them all.
+func validateAnyOf(alternatives []interface{}, value interface{}, pointer string, out *[]Violation) {
+	var required, described, descriptions []string
+
+	for _, alternative := range alternatives {
+		alt, ok := alternative.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var nested []Violation
+		validate(alt, value, pointer, &nested)
+		if len(nested) == 0 {
+			return
+		}
+
+		required = append(required, stringList(alt["required"])...)
+		described = append(described, describe(alt))
+		if description, ok := alt["description"].(string); ok && description != "" {
+			descriptions = append(descriptions, description)
+		}
+	}
+
+	if len(required) == 0 {
+		*out = append(*out, Violation{
+			Pointer: pointer,
+			Kind:    KindType,
+			Message: fmt.Sprintf("%s must be %s, got %s", name(pointer), strings.Join(described, " or "), preview(value)),
+			Hint:    "Send " + strings.Join(described, " or "),
+		})
+
+		return
+	}
+
+	quoted := make([]string, len(required))
+	for i, key := range required {
+		quoted[i] = strconv.Quote(key)
+	}
+
+	hint := "Add " + strings.Join(quoted, " or ")
+	if len(descriptions) > 0 {
+		hint += ": " + strings.Join(descriptions, "; ")
+	}
+
+	*out = append(*out, Violation{
+		Pointer: pointer + "/" + escape(required[0]),
+		Kind:    KindMissing,
+		Message: "missing required property " + strings.Join(quoted, " or "),
+		Hint:    hint,
+	})
+}
+
+// describe summarizes what a schema accepts, such as `a string` or
+// `one of "all", "first"`.
+func describe(schema Schema) string {
+	if schema == nil {
+		return "any value"
+	}
+
+	if enum := stringList(schema["enum"]); enum != nil {
+		return "one of " + quoteAll(enum)
+	}
+
+	kind, _ := schema["type"].(string)
+	text := article(kind)
+	if kind == "" {
+		text = "any value"
+	}
+
+	minimum, hasMin := number(schema["minimum"])
+	maximum, hasMax := number(schema["maximum"])
+
+	switch {
+	case hasMin && hasMax:
+		text += fmt.Sprintf(" from %v to %v", minimum, maximum)
+	case hasMin:
+		text += fmt.Sprintf(" >= %v", minimum)
+	case hasMax:
+		text += fmt.Sprintf(" <= %v", maximum)
+	}
+
+	if kind == "array" {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			text += " of " + describe(items)
+		}
+	}
+
+	return text
+}
+
+func withDescription(schema Schema, hint string) string {
+	if description, ok := schema["description"].(string); ok && description != "" {
+		return hint + ": " + description
+	}
+
+	return hint
+}
+
+// suggest adds the closest candidate to hint when got looks like a typo
+// of it, such as dryRun for dry_run.
+func suggest(hint, got string, candidates []string) string {
+	best, bestDistance := "", math.MaxInt
+
+	for _, candidate := range candidates {
+		if d := distance(fold(got), fold(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	if best != "" && bestDistance <= 2 {
+		return fmt.Sprintf("Did you mean %q? %s", best, hint)
+	}
+
+	return hint
+}
+
+// fold drops case and separators, so camelCase and snake_case compare
+// equal.
+func fold(s string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func hasType(value interface{}, want string) bool {
+	switch want {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(json.Number)
+
+		return ok
+	default:
+		return typeOf(value) == want
+	}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// preview names a value in a message: its type, or the value itself for
+// short scalars.
+func preview(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= 20 {
+			return strconv.Quote(v)
+		}
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return typeOf(value)
+}
+
+func article(kind string) string {
+	switch kind {
+	case "":
+		return ""
+	case "array", "integer", "object":
+		return "an " + kind
+	default:
+		return "a " + kind
+	}
+}
+
+// name is how messages refer to the value at pointer.
+func name(pointer string) string {
+	if pointer == "" {
+		return "arguments"
+	}
+
+	return pointer
+}
+
+// escape encodes a property name as a JSON pointer token.
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// stringList reads a list of strings, also when it is a slice of a named
+// string type such as operations.Mode.
+func stringList(value interface{}) []string {
+	if items, ok := value.([]interface{}); ok {
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+
+		return list
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.String {
+		return nil
+	}
+
+	list := make([]string, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).String()
+	}
+
+	return list
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func quoteAll(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = strconv.Quote(s)
+	}
+
+	return strings.Join(quoted, ", ")
+}
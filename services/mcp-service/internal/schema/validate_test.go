@@ -0,0 +1,207 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/schema"
+)
+
+// mode is a named string type, like the enums of the tool catalog.
+type mode string
+
+var editSchema = schema.Schema{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"documentId": map[string]interface{}{
+			"type":        "string",
+			"minLength":   1,
+			"description": "Google Docs document ID",
+		},
+		"dry_run": map[string]interface{}{"type": "boolean"},
+		"mode": map[string]interface{}{
+			"type": "string",
+			"enum": []mode{"append", "prepend"},
+		},
+		"min_similarity": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+		"occurrence": map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string", "enum": []string{"all", "first"}},
+				map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+		},
+		"operations": map[string]interface{}{
+			"type":     "array",
+			"minItems": 1,
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"content": map[string]interface{}{"type": "string"},
+				},
+				"required":             []string{"content"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"documentId"},
+	"additionalProperties": false,
+}
+
+// pointers maps each violation's pointer to its kind.
+func pointers(violations []schema.Violation) map[string]string {
+	kinds := make(map[string]string, len(violations))
+	for _, v := range violations {
+		kinds[v.Pointer] = v.Kind
+	}
+
+	return kinds
+}
+
+func TestORPHAN_Validate_ValidArguments_HaveNoViolations(t *testing.T) {
+	// Arrange
+	raw := json.RawMessage(`{"documentId":"doc-1","dry_run":true,"mode":"append",` +
+		`"min_similarity":0.5,"occurrence":2,"operations":[{"content":"x"}]}`)
+
+	// Act
+	violations, err := schema.Validate(editSchema, raw)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestORPHAN_Validate_ReportsEveryViolation(t *testing.T) {
+	// Arrange
+	raw := json.RawMessage(`{"dryRun":true,"mode":"apend","min_similarity":2,` +
+		`"occurrence":0,"operations":[{"content":1,"extra":true},{}]}`)
+
+	// Act
+	violations, err := schema.Validate(editSchema, raw)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"/documentId":           schema.KindMissing,
+		"/dryRun":               schema.KindUnexpected,
+		"/mode":                 schema.KindEnum,
+		"/min_similarity":       schema.KindRange,
+		"/occurrence":           schema.KindType,
+		"/operations/0/content": schema.KindType,
+		"/operations/0/extra":   schema.KindUnexpected,
+		"/operations/1/content": schema.KindMissing,
+	}, pointers(violations))
+}
+
+func TestORPHAN_Validate_Hints_SuggestTheIntendedValue(t *testing.T) {
+	// Arrange
+	raw := json.RawMessage(`{"documentId":"","dryRun":true,"mode":"apend"}`)
+
+	// Act
+	violations, err := schema.Validate(editSchema, raw)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, violations, 3)
+	hints := make(map[string]string, len(violations))
+	for _, v := range violations {
+		hints[v.Pointer] = v.Hint
+	}
+	assert.Contains(t, hints["/documentId"], "Google Docs document ID")
+	assert.Contains(t, hints["/dryRun"], `Did you mean "dry_run"?`)
+	assert.Contains(t, hints["/mode"], `Did you mean "append"?`)
+}
+
+func TestORPHAN_Validate_EmptyArguments_CountAsEmptyObject(t *testing.T) {
+	// Act
+	violations, err := schema.Validate(editSchema, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/documentId": schema.KindMissing}, pointers(violations))
+}
+
+func TestORPHAN_Validate_NonObjectArguments_AreMistyped(t *testing.T) {
+	// Act
+	violations, err := schema.Validate(editSchema, json.RawMessage(`["doc-1"]`))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"": schema.KindType}, pointers(violations))
+}
+
+func TestORPHAN_Validate_MalformedJSON_ReturnsError(t *testing.T) {
+	// Act
+	_, err := schema.Validate(editSchema, json.RawMessage(`{"documentId":`))
+
+	// Assert
+	require.ErrorIs(t, err, schema.ErrInvalidJSON)
+}
+
+func TestORPHAN_Validate_AnyOf_MissingEveryAlternative_NamesThemAll(t *testing.T) {
+	// Arrange
+	formatSchema := schema.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"anchorText": map[string]interface{}{"type": "string"},
+			"section":    map[string]interface{}{"type": "string"},
+		},
+		"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{"anchorText"}, "description": "anchorText restyles its matches"},
+			map[string]interface{}{"required": []string{"section"}, "description": "section restyles a section body"},
+		},
+	}
+
+	// Act
+	missing, err := schema.Validate(formatSchema, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	present, err := schema.Validate(formatSchema, json.RawMessage(`{"section":"Intro"}`))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, present)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "/anchorText", missing[0].Pointer)
+	assert.Equal(t, schema.KindMissing, missing[0].Kind)
+	assert.Equal(t, `missing required property "anchorText" or "section"`, missing[0].Message)
+	assert.Contains(t, missing[0].Hint, "section restyles a section body")
+}
+
+func TestORPHAN_Validate_MinLength_NamesTheActualMinimum(t *testing.T) {
+	// Arrange
+	nameSchema := schema.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string", "minLength": 3},
+		},
+	}
+
+	// Act
+	violations, err := schema.Validate(nameSchema, json.RawMessage(`{"name":"ab"}`))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "/name must be at least 3 characters, got 2", violations[0].Message)
+}
+
+func TestORPHAN_Check_InvalidPattern_ReturnsError(t *testing.T) {
+	// Arrange
+	brokenSchema := schema.Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"content": map[string]interface{}{"type": "string", "pattern": `\S(`},
+		},
+	}
+
+	// Act
+	checkErr := schema.Check(brokenSchema)
+	_, validateErr := schema.Validate(brokenSchema, json.RawMessage(`{"content":"x"}`))
+
+	// Assert
+	require.ErrorIs(t, checkErr, schema.ErrInvalidSchema)
+	assert.Contains(t, checkErr.Error(), "/properties/content/pattern")
+	require.ErrorIs(t, validateErr, schema.ErrInvalidSchema)
+}
@@ -0,0 +1,55 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service/internal/tracing"
+)
+
+func TestORPHAN_LogHook_AddsIDsFromPropagatedTraceparent(t *testing.T) {
+	// Arrange
+	_, err := tracing.Setup(context.Background())
+	require.NoError(t, err)
+
+	headers := http.Header{}
+	headers.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.Extract(context.Background(), headers)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(tracing.LogHook{})
+
+	// Act
+	logger.Info().Ctx(ctx).Msg("traced")
+	logger.Info().Msg("untraced")
+
+	// Assert
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var traced, untraced map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &traced))
+	require.NoError(t, json.Unmarshal(lines[1], &untraced))
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traced["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", traced["span_id"])
+	assert.NotContains(t, untraced, "trace_id")
+}
+
+func TestORPHAN_Setup_UnknownExporter_ReturnsError(t *testing.T) {
+	// Arrange
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+
+	// Act
+	_, err := tracing.Setup(context.Background())
+
+	// Assert
+	require.ErrorIs(t, err, tracing.ErrUnknownExporter)
+}
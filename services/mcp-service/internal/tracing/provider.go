@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter names accepted by OTEL_TRACES_EXPORTER.
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+const defaultServiceName = "mcp-service"
+
+// ErrUnknownExporter signals an OTEL_TRACES_EXPORTER value with no
+// corresponding exporter.
+var ErrUnknownExporter = errors.New("unknown traces exporter")
+
+// Shutdown flushes pending spans and releases exporter resources.
+type Shutdown func(ctx context.Context) error
+
+// Setup installs the W3C trace-context propagator and, when
+// OTEL_TRACES_EXPORTER=otlp, an SDK tracer provider exporting over
+// OTLP/HTTP. The exporter honours the standard OTEL_EXPORTER_OTLP_*
+// variables for endpoint, headers and TLS.
+//
+// The default ("none") keeps OpenTelemetry's no-op provider: no spans
+// are recorded, but incoming traceparent headers are still propagated,
+// so log lines carry the caller's trace ID.
+func Setup(ctx context.Context) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporterName := os.Getenv("OTEL_TRACES_EXPORTER")
+	switch exporterName {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, exporterName)
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("build resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/ondatra-ai/awesome-claude-mcp/services/mcp-service"
+
+// Tracer returns the service tracer from the global provider. It is
+// resolved on every call so that Setup may run after package init.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Extract returns ctx enriched with the remote span context carried by
+// traceparent/tracestate headers, if any.
+func Extract(ctx context.Context, headers http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(headers))
+}
+
+// StartStage opens a span for one tool-pipeline stage, e.g.
+// "validate_arguments" or "batch_update". End the span with EndStage.
+func StartStage(ctx context.Context, stage string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "stage."+stage, trace.WithAttributes(attrs...))
+}
+
+// EndStage records err on span (when non-nil) and ends it.
+func EndStage(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// NewTransport wraps next with client-side HTTP instrumentation: every
+// outbound request gets a span and a traceparent header.
+func NewTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return otelhttp.NewTransport(next)
+}
@@ -5,6 +5,10 @@ export interface EnvironmentConfig {
   frontendUrl: string;
   backendUrl: string;
   mcpServiceUrl: string;
+  /** Fake Google Docs API serving the documents the MCP service edits; local stack only. */
+  fakeGoogleDocsUrl?: string;
+  /** Bearer token of the MCP service admin API; local stack only. */
+  adminToken?: string;
   /** Optional human friendly description for logging. */
   description?: string;
 }
@@ -17,6 +21,8 @@ const environments: Record<EnvironmentName, EnvironmentConfig> = {
     frontendUrl: 'http://localhost:3000',
     backendUrl: 'http://localhost:8080',
     mcpServiceUrl: 'http://localhost:8081',
+    fakeGoogleDocsUrl: 'http://localhost:8090',
+    adminToken: 'test-admin-token',
     description: 'Local docker-compose stack',
   },
   dev: {
@@ -0,0 +1,70 @@
+/**
+ * Fake Google Docs API Helper
+ *
+ * The local test stack runs the MCP service against fake-gdocs, an
+ * offline Google Docs API. Its /fixtures endpoints let tests seed a
+ * document before a tool call and read back what the call wrote.
+ */
+
+export interface IFixtureDocument {
+  documentId: string;
+  title: string;
+  revisionId: string;
+  role: string;
+  text: string;
+  endIndex: number;
+}
+
+export interface ISeedOptions {
+  title?: string;
+  role?: 'owner' | 'writer' | 'commenter' | 'reader' | 'none';
+}
+
+/**
+ * Replace a document with one converted from Markdown.
+ */
+export async function seedDocument(
+  baseUrl: string,
+  documentId: string,
+  markdown: string,
+  options: ISeedOptions = {}
+): Promise<IFixtureDocument> {
+  const response = await fetch(fixtureUrl(baseUrl, documentId), {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ markdown, ...options }),
+  });
+
+  if (!response.ok) {
+    throw new Error(`Seeding ${documentId} failed: ${response.status} ${await response.text()}`);
+  }
+
+  return (await response.json()) as IFixtureDocument;
+}
+
+/**
+ * Read a document as the fake currently holds it.
+ */
+export async function readDocument(
+  baseUrl: string,
+  documentId: string
+): Promise<IFixtureDocument> {
+  const response = await fetch(fixtureUrl(baseUrl, documentId));
+
+  if (!response.ok) {
+    throw new Error(`Reading ${documentId} failed: ${response.status} ${await response.text()}`);
+  }
+
+  return (await response.json()) as IFixtureDocument;
+}
+
+/**
+ * A document ID no other test uses, so parallel tests do not share state.
+ */
+export function uniqueDocumentId(prefix: string): string {
+  return `${prefix}-${Date.now()}-${Math.random().toString(36).slice(2, 8)}`;
+}
+
+function fixtureUrl(baseUrl: string, documentId: string): string {
+  return new URL(`/fixtures/documents/${encodeURIComponent(documentId)}`, baseUrl).toString();
+}
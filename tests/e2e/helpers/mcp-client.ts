@@ -15,6 +15,11 @@ import {
 export interface IMcpClientOptions {
   name?: string;
   version?: string;
+  /**
+   * Transport to use. Without it, Streamable HTTP is tried first and the
+   * legacy HTTP+SSE transport is the fallback.
+   */
+  transport?: 'streamable-http' | 'sse';
 }
 
 export interface IMcpTool {
@@ -34,10 +39,12 @@ export interface IToolResult {
 export class McpClient {
   private client: Client;
   private baseUrl: string;
+  private transport?: IMcpClientOptions['transport'];
   private connected: boolean = false;
 
   constructor(baseUrl: string, options: IMcpClientOptions = {}) {
     this.baseUrl = baseUrl;
+    this.transport = options.transport;
     this.client = new Client({
       name: options.name || 'e2e-test-client',
       version: options.version || '1.0.0',
@@ -53,13 +60,23 @@ export class McpClient {
     }
 
     const mcpUrl = new URL('/mcp', this.baseUrl);
+    const sseUrl = new URL('/sse', this.baseUrl);
+
+    if (this.transport === 'sse') {
+      await this.client.connect(new SSEClientTransport(sseUrl));
+      this.connected = true;
+      return;
+    }
 
     try {
       const transport = new StreamableHTTPClientTransport(mcpUrl);
       await this.client.connect(transport);
       this.connected = true;
-    } catch {
-      const sseTransport = new SSEClientTransport(mcpUrl);
+    } catch (error) {
+      if (this.transport === 'streamable-http') {
+        throw error;
+      }
+      const sseTransport = new SSEClientTransport(sseUrl);
       await this.client.connect(sseTransport);
       this.connected = true;
     }
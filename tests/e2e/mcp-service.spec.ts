@@ -8,8 +8,9 @@ import {
   closeAllClients,
   getResultText,
 } from './helpers/mcp-client';
+import { readDocument, seedDocument, uniqueDocumentId } from './helpers/google-docs';
 
-const { mcpServiceUrl } = getEnvironmentConfig(process.env.E2E_ENV);
+const { mcpServiceUrl, fakeGoogleDocsUrl } = getEnvironmentConfig(process.env.E2E_ENV);
 
 /**
  * MCP Service E2E Tests
@@ -513,4 +514,161 @@ Refer to the API documentation for detailed usage instructions.
 
     await client.close();
   });
+
+  /**
+   * Legacy HTTP+SSE transport (protocol version 2024-11-05): the SDK's
+   * SSEClientTransport opens GET /sse and posts to the endpoint it names.
+   * The same calls over both transports must give the same results.
+   */
+  test.describe('Legacy HTTP+SSE transport', () => {
+    const connect = async (transport: 'streamable-http' | 'sse'): Promise<McpClient> => {
+      const client = new McpClient(mcpServiceUrl, { name: `e2e-${transport}-client`, transport });
+      await client.connect();
+      return client;
+    };
+
+    test('ORPHAN: Legacy SSE client lists the same tools as Streamable HTTP', async () => {
+      const streamable = await connect('streamable-http');
+      const legacy = await connect('sse');
+
+      const streamableTools = await streamable.listTools();
+      const legacyTools = await legacy.listTools();
+
+      expect(legacyTools.length, 'Legacy transport should list tools').toBeGreaterThan(0);
+      expect(legacyTools, 'Both transports should list identical tools').toEqual(streamableTools);
+
+      await streamable.close();
+      await legacy.close();
+    });
+
+    test('ORPHAN: Legacy SSE client gets the same error for an unknown tool', async () => {
+      const streamable = await connect('streamable-http');
+      const legacy = await connect('sse');
+
+      const errorOf = (client: McpClient) =>
+        client.callTool('no_such_tool', {}).then(
+          (result) => getResultText(result),
+          (error: Error) => error.message
+        );
+
+      const legacyError = await errorOf(legacy);
+
+      expect(legacyError, 'Unknown tool should be reported').toMatch(/no_such_tool|not found/i);
+      expect(legacyError, 'Both transports should report the same error').toBe(await errorOf(streamable));
+
+      await streamable.close();
+      await legacy.close();
+    });
+
+    test('ORPHAN: Legacy SSE client edits a document like Streamable HTTP', async () => {
+      test.skip(!fakeGoogleDocsUrl, 'Requires the fake Google Docs API of the local stack');
+      const docsUrl = fakeGoogleDocsUrl ?? '';
+
+      const streamableDoc = uniqueDocumentId('e2e-transport-streamable');
+      const legacyDoc = uniqueDocumentId('e2e-transport-legacy');
+      await seedDocument(docsUrl, streamableDoc, '# Notes\n\nFirst line.');
+      await seedDocument(docsUrl, legacyDoc, '# Notes\n\nFirst line.');
+
+      const streamable = await connect('streamable-http');
+      const legacy = await connect('sse');
+
+      const streamableResult = await streamable.callTool('append', {
+        documentId: streamableDoc,
+        content: 'Second **line**.',
+      });
+      const legacyResult = await legacy.callTool('append', {
+        documentId: legacyDoc,
+        content: 'Second **line**.',
+      });
+
+      expect(legacyResult.isError, 'Append over the legacy transport should succeed').toBeFalsy();
+      expect(getResultText(legacyResult).replace(legacyDoc, 'DOC')).toBe(
+        getResultText(streamableResult).replace(streamableDoc, 'DOC')
+      );
+
+      const legacyText = (await readDocument(docsUrl, legacyDoc)).text;
+      expect(legacyText).toBe('Notes\nFirst line.\nSecond line.\n');
+      expect(legacyText).toBe((await readDocument(docsUrl, streamableDoc)).text);
+
+      await streamable.close();
+      await legacy.close();
+    });
+  });
+
+  /**
+   * ORPHAN: Tool calls change the document content
+   *
+   * These run against the fake Google Docs API of the local stack, which
+   * holds the documents the MCP service edits.
+   */
+  test.describe('Document contents', () => {
+    test.skip(!fakeGoogleDocsUrl, 'Requires the fake Google Docs API of the local stack');
+
+    const docsUrl = fakeGoogleDocsUrl ?? '';
+
+    test('ORPHAN: replace_all writes the converted Markdown', async () => {
+      const documentId = uniqueDocumentId('e2e-replace-all');
+      await seedDocument(docsUrl, documentId, 'Old content that should disappear.');
+
+      const client = new McpClient(mcpServiceUrl);
+      await client.connect();
+
+      const result = await client.callTool('replace_all', {
+        documentId,
+        content: '# Fresh Start\n\nBody text.',
+      });
+
+      expect(result.isError, 'replace_all should not return an error').toBeFalsy();
+
+      const doc = await readDocument(docsUrl, documentId);
+      expect(doc.text, 'Document should hold only the new content').toBe('Fresh Start\nBody text.\n');
+
+      await client.close();
+    });
+
+    test('ORPHAN: insertAfter places content after the anchor paragraph', async () => {
+      const documentId = uniqueDocumentId('e2e-insert-after');
+      await seedDocument(docsUrl, documentId, '# Handbook\n\nWelcome to the team.\n\nSee you soon.');
+
+      const client = new McpClient(mcpServiceUrl);
+      await client.connect();
+
+      const result = await client.callTool('insertAfter', {
+        documentId,
+        anchorText: 'Welcome to the team.',
+        content: 'New hires get a buddy.',
+      });
+
+      expect(result.isError, 'insertAfter should not return an error').toBeFalsy();
+
+      const doc = await readDocument(docsUrl, documentId);
+      expect(doc.text, 'Inserted paragraph should follow the anchor').toBe(
+        'Handbook\nWelcome to the team.\nNew hires get a buddy.\nSee you soon.\n'
+      );
+
+      await client.close();
+    });
+
+    test('ORPHAN: Edits to a read-only document fail and leave it unchanged', async () => {
+      const documentId = 'test-fixture-readonly';
+      const before = await readDocument(docsUrl, documentId);
+
+      const client = new McpClient(mcpServiceUrl);
+      await client.connect();
+
+      const result = await client.callTool('append', {
+        documentId,
+        content: 'This should not be written.',
+      });
+
+      expect(result.isError, 'Append to a read-only document should fail').toBeTruthy();
+      expect(getResultText(result), 'Error should name the permission problem').toContain('PERMISSION_DENIED');
+
+      const after = await readDocument(docsUrl, documentId);
+      expect(after.text, 'Document text should be unchanged').toBe(before.text);
+      expect(after.revisionId, 'Document revision should be unchanged').toBe(before.revisionId);
+
+      await client.close();
+    });
+  });
 });
@@ -1,8 +1,8 @@
-import { test, expect } from '@playwright/test';
+import { test, expect, type APIRequestContext } from '@playwright/test';
 
 import { getEnvironmentConfig } from '../config/environments';
 
-const { mcpServiceUrl } = getEnvironmentConfig(process.env.E2E_ENV);
+const { mcpServiceUrl, fakeGoogleDocsUrl, adminToken } = getEnvironmentConfig(process.env.E2E_ENV);
 
 /**
  * MCP Service Integration Tests
@@ -14,6 +14,10 @@ const { mcpServiceUrl } = getEnvironmentConfig(process.env.E2E_ENV);
  * - POST /mcp: Client sends JSON-RPC messages
  * - GET /mcp: Client establishes SSE stream for server-to-client messages
  * - Header: Mcp-Session-Id for session tracking
+ *
+ * Legacy HTTP+SSE transport (2024-11-05), served for older clients
+ * - GET /sse: Session stream, starting with an endpoint event
+ * - POST /messages?sessionId=: Client messages, answered on the stream
  */
 test.describe('MCP Service Integration Tests', () => {
   test(
@@ -2381,4 +2385,848 @@ test.describe('MCP Service Integration Tests', () => {
       ).toBeTruthy();
     }
   );
+
+  /**
+   * The legacy HTTP+SSE transport (protocol version 2024-11-05): GET /sse
+   * opens a session stream whose first event names the endpoint, and
+   * replies to POST /messages?sessionId= arrive on that stream.
+   */
+  test.describe('Legacy HTTP+SSE transport', () => {
+    interface ISseEvent {
+      event: string;
+      data: string;
+    }
+
+    const openLegacyStream = async () => {
+      const controller = new AbortController();
+      const response = await fetch(`${mcpServiceUrl}/sse`, {
+        headers: { Accept: 'text/event-stream' },
+        signal: controller.signal,
+      });
+      const reader = response.body!.getReader();
+      const decoder = new TextDecoder();
+      let buffer = '';
+
+      // Resolves with the next event, skipping keep-alive comments
+      const next = async (): Promise<ISseEvent> => {
+        for (;;) {
+          const end = buffer.indexOf('\n\n');
+          if (end >= 0) {
+            const block = buffer.slice(0, end);
+            buffer = buffer.slice(end + 2);
+
+            const parsed: ISseEvent = { event: 'message', data: '' };
+            for (const line of block.split('\n')) {
+              if (line.startsWith('event: ')) parsed.event = line.slice('event: '.length);
+              if (line.startsWith('data: ')) parsed.data += line.slice('data: '.length);
+            }
+            if (parsed.data) return parsed;
+            continue;
+          }
+
+          const { value, done } = await reader.read();
+          if (done) throw new Error('SSE stream ended');
+          buffer += decoder.decode(value, { stream: true });
+        }
+      };
+
+      return { response, next, close: () => controller.abort() };
+    };
+
+    const initializeMessage = {
+      jsonrpc: '2.0',
+      method: 'initialize',
+      id: 1,
+      params: {
+        protocolVersion: '2024-11-05',
+        capabilities: {},
+        clientInfo: { name: 'legacy-client', version: '1.0.0' },
+      },
+    };
+
+    test(
+      'ORPHAN: GET /sse announces the message endpoint',
+      async () => {
+        // When: Client opens the legacy SSE stream
+        const stream = await openLegacyStream();
+
+        try {
+          // Then: The stream is event-stream and starts with the endpoint event
+          expect(stream.response.status).toBe(200);
+          expect(stream.response.headers.get('content-type')).toContain('text/event-stream');
+
+          const first = await stream.next();
+          expect(first.event).toBe('endpoint');
+          expect(first.data).toMatch(/^\/messages\?sessionId=[\w-]+$/);
+        } finally {
+          stream.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: POST /messages answers on the stream like POST /mcp',
+      async ({ request }) => {
+        // Given: A legacy session and a Streamable HTTP session
+        const stream = await openLegacyStream();
+
+        try {
+          const endpoint = (await stream.next()).data;
+          const postLegacy = async (message: Record<string, unknown>) => {
+            const accepted = await request.post(`${mcpServiceUrl}${endpoint}`, { data: message });
+            expect(accepted.status(), 'Legacy POST should be accepted').toBe(202);
+            const reply = await stream.next();
+            expect(reply.event).toBe('message');
+            return JSON.parse(reply.data);
+          };
+
+          const streamableInit = await request.post(`${mcpServiceUrl}/mcp`, { data: initializeMessage });
+          const sessionId = streamableInit.headers()['mcp-session-id'];
+          const postStreamable = async (message: Record<string, unknown>) =>
+            (
+              await request.post(`${mcpServiceUrl}/mcp`, {
+                headers: { 'Mcp-Session-Id': sessionId },
+                data: message,
+              })
+            ).json();
+
+          // When: Both sessions initialize and list tools
+          const legacyInit = await postLegacy(initializeMessage);
+          const listTools = { jsonrpc: '2.0', method: 'tools/list', id: 2 };
+          const legacyTools = await postLegacy(listTools);
+          const streamableTools = await postStreamable(listTools);
+
+          // Then: Both transports return the same results
+          const streamableInitBody = await streamableInit.json();
+          expect(legacyInit.id).toBe(1);
+          expect(legacyInit.result.protocolVersion).toBe(streamableInitBody.result.protocolVersion);
+          expect(legacyInit.result.serverInfo).toEqual(streamableInitBody.result.serverInfo);
+          expect(legacyInit.result.capabilities).toEqual(streamableInitBody.result.capabilities);
+          expect(legacyTools).toEqual(streamableTools);
+
+          // Then: Notifications are accepted without a reply
+          const notified = await request.post(`${mcpServiceUrl}${endpoint}`, {
+            data: { jsonrpc: '2.0', method: 'notifications/initialized' },
+          });
+          expect(notified.status()).toBe(202);
+        } finally {
+          stream.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: POST /messages rejects missing and unknown sessions',
+      async ({ request }) => {
+        // When: Client posts without a session or to one that does not exist
+        const missing = await request.post(`${mcpServiceUrl}/messages`, { data: initializeMessage });
+        const unknown = await request.post(`${mcpServiceUrl}/messages?sessionId=no-such-session`, {
+          data: initializeMessage,
+        });
+
+        // Then: The server rejects both
+        expect(missing.status()).toBe(400);
+        expect(unknown.status()).toBe(404);
+      }
+    );
+  });
+
+  /**
+   * Tool arguments are checked against the tool's inputSchema before
+   * dispatch; every violation is listed with its JSON pointer and a hint.
+   */
+  test.describe('Argument validation', () => {
+    const callTool = (request: APIRequestContext, name: string, args: unknown) =>
+      request.post(`${mcpServiceUrl}/mcp`, {
+        headers: { 'Content-Type': 'application/json' },
+        data: {
+          jsonrpc: '2.0',
+          method: 'tools/call',
+          id: 1,
+          params: { name, arguments: args },
+        },
+      });
+
+    const pointers = (errors: Array<{ pointer: string; kind: string }>) =>
+      Object.fromEntries(errors.map((e) => [e.pointer, e.kind]));
+
+    test(
+      'ORPHAN: Missing, unknown and mistyped arguments are all reported',
+      async ({ request }) => {
+        // When: Client sends insertAfter with three different mistakes
+        const response = await callTool(request, 'insertAfter', {
+          docId: 'test-int-validation',
+          content: 42,
+          anchorText: 'Intro',
+        });
+
+        // Then: One -32602 error lists every violation by JSON pointer
+        expect(response.status()).toBe(200);
+        const result = await response.json();
+        expect(result.error.code).toBe(-32602);
+        expect(pointers(result.error.data.errors)).toEqual({
+          '/documentId': 'missing',
+          '/docId': 'unexpected',
+          '/content': 'type',
+        });
+        for (const error of result.error.data.errors) {
+          expect(error.hint, `${error.pointer} should carry a hint`).toBeTruthy();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: Misspelled argument names get a suggestion',
+      async ({ request }) => {
+        // When: Client spells dry_run in camelCase
+        const response = await callTool(request, 'append', {
+          documentId: 'test-int-validation',
+          content: 'Text',
+          dryRun: true,
+        });
+
+        // Then: The error points at the property and names the intended one
+        const result = await response.json();
+        expect(result.error.code).toBe(-32602);
+        expect(result.error.data.errors).toHaveLength(1);
+        expect(result.error.data.errors[0].pointer).toBe('/dryRun');
+        expect(result.error.data.errors[0].hint).toContain('Did you mean "dry_run"?');
+      }
+    );
+
+    test(
+      'ORPHAN: Nested batch_edit operations are validated',
+      async ({ request }) => {
+        // When: Client sends an unknown mode and a non-object operation
+        const response = await callTool(request, 'batch_edit', {
+          documentId: 'test-int-validation',
+          operations: [{ mode: 'apend', content: 'Text' }, 'replace_all'],
+        });
+
+        // Then: Each problem is reported at its array index
+        const result = await response.json();
+        expect(result.error.code).toBe(-32602);
+        expect(pointers(result.error.data.errors)).toEqual({
+          '/operations/0/mode': 'enum',
+          '/operations/1': 'type',
+        });
+      }
+    );
+  });
+
+  /**
+   * DNS rebinding: a page on an attacker's host name that resolves to the
+   * server still sends its own Origin, which must not be on the allowlist.
+   * The local stack keeps the default allowlist of loopback origins.
+   */
+  test.describe('Origin validation', () => {
+    const rebindingOrigin = 'http://attacker.example:8081';
+    const pingMessage = { jsonrpc: '2.0', method: 'ping', id: 1 };
+
+    test(
+      'ORPHAN: Requests from a rebinding origin are rejected with 403',
+      async ({ request }) => {
+        // When: A rebound page posts to /mcp and opens both SSE streams
+        const post = await request.post(`${mcpServiceUrl}/mcp`, {
+          headers: { Origin: rebindingOrigin },
+          data: pingMessage,
+        });
+        const stream = await request.get(`${mcpServiceUrl}/mcp`, { headers: { Origin: rebindingOrigin } });
+        const legacy = await request.get(`${mcpServiceUrl}/sse`, { headers: { Origin: rebindingOrigin } });
+        const messages = await request.post(`${mcpServiceUrl}/messages?sessionId=any`, {
+          headers: { Origin: rebindingOrigin },
+          data: pingMessage,
+        });
+
+        // Then: Every MCP endpoint refuses it before any session is created
+        expect(post.status()).toBe(403);
+        expect(post.headers()['mcp-session-id']).toBeUndefined();
+        expect((await post.json()).error.message).toContain('origin not allowed');
+        expect(stream.status()).toBe(403);
+        expect(legacy.status()).toBe(403);
+        expect(messages.status()).toBe(403);
+      }
+    );
+
+    test(
+      'ORPHAN: Preflight from a rebinding origin gets no CORS grant',
+      async ({ request }) => {
+        // When: The browser preflights a cross-origin POST
+        const preflight = await request.fetch(`${mcpServiceUrl}/mcp`, {
+          method: 'OPTIONS',
+          headers: {
+            Origin: rebindingOrigin,
+            'Access-Control-Request-Method': 'POST',
+          },
+        });
+
+        // Then: The origin is not granted
+        expect(preflight.headers()['access-control-allow-origin']).toBeUndefined();
+      }
+    );
+
+    test(
+      'ORPHAN: Allowed and absent origins are served',
+      async ({ request }) => {
+        // When: A local page and a non-browser client call /mcp
+        const local = await request.post(`${mcpServiceUrl}/mcp`, {
+          headers: { Origin: 'http://localhost:3000' },
+          data: pingMessage,
+        });
+        const direct = await request.post(`${mcpServiceUrl}/mcp`, { data: pingMessage });
+
+        // Then: Both are served and the local page gets its CORS grant
+        expect(local.status()).toBe(200);
+        expect(local.headers()['access-control-allow-origin']).toBe('http://localhost:3000');
+        expect(direct.status()).toBe(200);
+      }
+    );
+  });
+
+  /**
+   * The admin API lists and closes sessions and drains the service; the
+   * local stack enables it with a fixed token.
+   */
+  test.describe('Admin API', () => {
+    test.skip(!adminToken, 'Requires the admin token of the local stack');
+
+    const adminHeaders = { Authorization: `Bearer ${adminToken}` };
+
+    const initialize = (request: APIRequestContext) =>
+      request.post(`${mcpServiceUrl}/mcp`, {
+        data: {
+          jsonrpc: '2.0',
+          method: 'initialize',
+          id: 1,
+          params: {
+            protocolVersion: '2024-11-05',
+            capabilities: {},
+            clientInfo: { name: 'admin-test-client', version: '1.0.0' },
+          },
+        },
+      });
+
+    test(
+      'ORPHAN: Admin routes require the admin token',
+      async ({ request }) => {
+        // When: Client calls the admin API without and with a wrong token
+        const anonymous = await request.get(`${mcpServiceUrl}/admin/sessions`);
+        const wrongToken = await request.get(`${mcpServiceUrl}/admin/sessions`, {
+          headers: { Authorization: 'Bearer not-the-admin-token' },
+        });
+
+        // Then: Both are rejected
+        expect(anonymous.status()).toBe(401);
+        expect(wrongToken.status()).toBe(401);
+      }
+    );
+
+    test(
+      'ORPHAN: Admin API lists, reports and closes a session',
+      async ({ request }) => {
+        // Given: An initialized session
+        const sessionId = (await initialize(request)).headers()['mcp-session-id'];
+        expect(sessionId).toBeTruthy();
+
+        // When: Operator lists sessions and fetches this one
+        const list = await request.get(`${mcpServiceUrl}/admin/sessions`, { headers: adminHeaders });
+        const single = await request.get(`${mcpServiceUrl}/admin/sessions/${sessionId}`, {
+          headers: adminHeaders,
+        });
+
+        // Then: The session is reported with its activity
+        expect(list.status()).toBe(200);
+        const listed = (await list.json()).sessions.map((session: { id: string }) => session.id);
+        expect(listed).toContain(sessionId);
+
+        expect(single.status()).toBe(200);
+        const session = await single.json();
+        expect(session).toMatchObject({ id: sessionId, messageCount: 1, sseConnected: false, queueDepth: 0 });
+        expect(Date.parse(session.createdAt)).not.toBeNaN();
+        expect(Date.parse(session.lastActive)).not.toBeNaN();
+
+        // When: Operator closes the session
+        const closed = await request.delete(`${mcpServiceUrl}/admin/sessions/${sessionId}`, {
+          headers: adminHeaders,
+        });
+
+        // Then: It is gone
+        expect(closed.status()).toBe(204);
+        const after = await request.get(`${mcpServiceUrl}/admin/sessions/${sessionId}`, {
+          headers: adminHeaders,
+        });
+        expect(after.status()).toBe(404);
+      }
+    );
+
+    test(
+      'ORPHAN: Draining refuses new sessions but serves existing ones',
+      async ({ request }) => {
+        // Given: A session opened before the drain
+        const sessionId = (await initialize(request)).headers()['mcp-session-id'];
+
+        try {
+          // When: Operator drains the service
+          const drained = await request.post(`${mcpServiceUrl}/admin/drain`, { headers: adminHeaders });
+          expect(drained.status()).toBe(200);
+          expect((await drained.json()).draining).toBe(true);
+
+          // Then: New sessions and readiness fail, the existing session still works
+          expect((await initialize(request)).status()).toBe(503);
+          expect((await request.get(`${mcpServiceUrl}/readyz`)).status()).toBe(503);
+
+          const existing = await request.post(`${mcpServiceUrl}/mcp`, {
+            headers: { 'Mcp-Session-Id': sessionId },
+            data: { jsonrpc: '2.0', method: 'ping', id: 2 },
+          });
+          expect(existing.status()).toBe(200);
+        } finally {
+          // Cleanup: Accept sessions again for the rest of the suite
+          await request.delete(`${mcpServiceUrl}/admin/drain`, { headers: adminHeaders });
+        }
+
+        expect((await initialize(request)).status()).toBe(200);
+      }
+    );
+  });
+
+  /**
+   * The local stack serves documents from the fake Google Docs API, whose
+   * /fixtures endpoints seed a document and read back what a call wrote.
+   */
+  test.describe('Document contents after tool calls', () => {
+    test.skip(!fakeGoogleDocsUrl, 'Requires the fake Google Docs API of the local stack');
+
+    const fixtureUrl = (documentId: string) =>
+      `${fakeGoogleDocsUrl}/fixtures/documents/${encodeURIComponent(documentId)}`;
+
+    const callTool = (request: APIRequestContext, name: string, args: Record<string, unknown>) =>
+      request.post(`${mcpServiceUrl}/mcp`, {
+        headers: { 'Content-Type': 'application/json' },
+        data: {
+          jsonrpc: '2.0',
+          method: 'tools/call',
+          id: 1,
+          params: { name, arguments: args },
+        },
+      });
+
+    test(
+      'ORPHAN: replace_section rewrites only the named section',
+      async ({ request }) => {
+        // Given: A seeded document with two sections
+        const documentId = `test-int-section-${Date.now()}`;
+        const seeded = await request.put(fixtureUrl(documentId), {
+          data: {
+            markdown:
+              '# Handbook\n\n## Onboarding\n\nRead the handbook.\n\n## Support\n\nEscalate incidents.',
+          },
+        });
+        expect(seeded.status(), 'Fixture should be seeded').toBe(201);
+
+        // When: Client replaces the Onboarding section
+        const response = await callTool(request, 'replace_section', {
+          documentId,
+          section: 'Onboarding',
+          content: 'Ask your buddy.',
+        });
+
+        expect(response.status()).toBe(200);
+        const result = await response.json();
+        expect(result.result.isError, 'replace_section should succeed').toBe(false);
+
+        // Then: Only the section body changed in the stored document
+        const doc = await (await request.get(fixtureUrl(documentId))).json();
+        expect(doc.text).toBe('Handbook\nOnboarding\nAsk your buddy.\nSupport\nEscalate incidents.\n');
+      }
+    );
+
+    test(
+      'ORPHAN: Dry run reports the change without writing it',
+      async ({ request }) => {
+        // Given: A seeded single-paragraph document
+        const documentId = `test-int-dry-run-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Unchanged text.' } });
+        const before = await (await request.get(fixtureUrl(documentId))).json();
+
+        // When: Client appends with dry_run
+        const response = await callTool(request, 'append', {
+          documentId,
+          content: 'Preview only.',
+          dry_run: true,
+        });
+
+        expect(response.status()).toBe(200);
+        const result = await response.json();
+        expect(result.result.isError, 'Dry run should succeed').toBe(false);
+
+        // Then: The stored document and its revision are untouched
+        const after = await (await request.get(fixtureUrl(documentId))).json();
+        expect(after.text).toBe('Unchanged text.\n');
+        expect(after.revisionId).toBe(before.revisionId);
+      }
+    );
+
+    test(
+      'ORPHAN: search_document pages through matches without writing',
+      async ({ request }) => {
+        // Given: A seeded document mentioning the query in two sections
+        const documentId = `test-int-search-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), {
+          data: { markdown: '# Setup\n\n## Linux\n\nRun setup.\n\n## Mac\n\nRun SETUP too.' },
+        });
+        const before = await (await request.get(fixtureUrl(documentId))).json();
+
+        // When: Client searches one match per page
+        const response = await callTool(request, 'search_document', {
+          documentId,
+          query: 'run setup',
+          limit: 1,
+        });
+
+        expect(response.status()).toBe(200);
+        const result = await response.json();
+        expect(result.result.isError, 'search_document should succeed').toBe(false);
+
+        // Then: The first match is described and the next page is pointed to
+        const found = result.result.structuredContent;
+        expect(found.total).toBe(2);
+        expect(found.next_offset).toBe(1);
+        expect(found.matches).toHaveLength(1);
+        expect(found.matches[0].occurrence).toBe(1);
+        expect(found.matches[0].paragraph).toBe('Run setup.');
+        expect(found.matches[0].heading_path).toBe('Setup > Linux');
+
+        // And: The stored document is untouched
+        const after = await (await request.get(fixtureUrl(documentId))).json();
+        expect(after.revisionId).toBe(before.revisionId);
+      }
+    );
+
+    test(
+      'ORPHAN: get_outline returns paths that section-scoped edits accept',
+      async ({ request }) => {
+        // Given: A seeded document with nested headings
+        const documentId = `test-int-outline-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), {
+          data: { markdown: '# Guide\n\n## Linux\n\nUse apt.\n\n## Mac\n\nUse brew now.' },
+        });
+
+        // When: Client reads the outline and appends to its last section
+        const response = await callTool(request, 'get_outline', { documentId });
+
+        expect(response.status()).toBe(200);
+        const result = await response.json();
+        expect(result.result.isError, 'get_outline should succeed').toBe(false);
+
+        const outline = result.result.structuredContent;
+        expect(outline.headings.map((h: { path: string }) => h.path)).toEqual([
+          'Guide',
+          'Guide > Linux',
+          'Guide > Mac',
+        ]);
+        expect(outline.headings[2].level).toBe(2);
+        expect(outline.headings[2].wordCount).toBe(3);
+
+        const edited = await callTool(request, 'append', {
+          documentId,
+          section: outline.headings[2].path,
+          content: 'Done.',
+        });
+
+        // Then: The edit lands in the outlined section
+        expect((await edited.json()).result.isError, 'append should succeed').toBe(false);
+        const doc = await (await request.get(fixtureUrl(documentId))).json();
+        expect(doc.text).toBe('Guide\nLinux\nUse apt.\nMac\nUse brew now.\nDone.\n');
+      }
+    );
+
+    test(
+      'ORPHAN: Segment edits reach the header and footnotes become native footnotes',
+      async ({ request }) => {
+        // Given: A seeded document with a header
+        const documentId = `test-int-segment-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), {
+          data: { markdown: 'Body text.', header: 'Draft' },
+        });
+
+        // When: Client appends to the header, then adds a footnote to the body
+        const header = await callTool(request, 'append', {
+          documentId,
+          segment: 'header',
+          content: 'Confidential',
+        });
+        const body = await callTool(request, 'append', {
+          documentId,
+          content: 'Revenue grew[^1].\n\n[^1]: Audited figures.',
+        });
+
+        // Then: The header changed, the body did not, and the footnote holds its text
+        expect((await header.json()).result.isError, 'header append should succeed').toBe(false);
+        expect((await body.json()).result.isError, 'footnote append should succeed').toBe(false);
+
+        const doc = await (await request.get(fixtureUrl(documentId))).json();
+        expect(doc.header).toBe('Draft\nConfidential\n');
+        expect(doc.text).toContain('Body text.\nRevenue grew');
+        expect(Object.values(doc.footnotes)).toEqual([' Audited figures.\n']);
+      }
+    );
+  });
+
+  /**
+   * Clients declaring the elicitation capability are asked, over their SSE
+   * stream, which match of an ambiguous anchor an edit should change.
+   */
+  test.describe('Elicitation', () => {
+    test.skip(!fakeGoogleDocsUrl, 'Requires the fake Google Docs API of the local stack');
+
+    const fixtureUrl = (documentId: string) =>
+      `${fakeGoogleDocsUrl}/fixtures/documents/${encodeURIComponent(documentId)}`;
+
+    interface IElicitationRequest {
+      id: string;
+      method: string;
+      params: { message: string; requestedSchema: { properties: { occurrence: { enum: string[] } } } };
+    }
+
+    // Opens a session with the given capabilities and its SSE stream
+    const openSession = async (capabilities: Record<string, unknown>, protocolVersion = '2025-06-18') => {
+      const init = await fetch(`${mcpServiceUrl}/mcp`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          jsonrpc: '2.0',
+          method: 'initialize',
+          id: 1,
+          params: {
+            protocolVersion,
+            capabilities,
+            clientInfo: { name: 'elicitation-client', version: '1.0.0' },
+          },
+        }),
+      });
+      const sessionId = init.headers.get('mcp-session-id')!;
+
+      const controller = new AbortController();
+      const stream = await fetch(`${mcpServiceUrl}/mcp`, {
+        headers: { Accept: 'text/event-stream', 'Mcp-Session-Id': sessionId },
+        signal: controller.signal,
+      });
+      const reader = stream.body!.getReader();
+      const decoder = new TextDecoder();
+      let buffer = '';
+
+      // Resolves with the next server request, skipping pings
+      const nextMessage = async (): Promise<IElicitationRequest> => {
+        for (;;) {
+          const end = buffer.indexOf('\n\n');
+          if (end >= 0) {
+            const block = buffer.slice(0, end);
+            buffer = buffer.slice(end + 2);
+
+            const lines = block.split('\n');
+            const data = lines
+              .filter((line) => line.startsWith('data: '))
+              .map((line) => line.slice('data: '.length))
+              .join('');
+            if (data && !lines.some((line) => line === 'event: ping')) return JSON.parse(data);
+            continue;
+          }
+
+          const { value, done } = await reader.read();
+          if (done) throw new Error('SSE stream ended');
+          buffer += decoder.decode(value, { stream: true });
+        }
+      };
+
+      const post = (message: unknown) =>
+        fetch(`${mcpServiceUrl}/mcp`, {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json', 'Mcp-Session-Id': sessionId },
+          body: JSON.stringify(message),
+        });
+
+      return { nextMessage, post, close: () => controller.abort() };
+    };
+
+    const insertAfterFoo = (documentId: string) => ({
+      jsonrpc: '2.0',
+      method: 'tools/call',
+      id: 2,
+      params: { name: 'insertAfter', arguments: { documentId, anchorText: 'foo', content: 'Inserted' } },
+    });
+
+    test(
+      'ORPHAN: The chosen match is the only one edited',
+      async ({ request }) => {
+        // Given: A document where the anchor matches twice
+        const documentId = `test-int-elicit-accept-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const session = await openSession({ elicitation: {} });
+
+        try {
+          // When: The client is asked which match to use and picks the second
+          const call = session.post(insertAfterFoo(documentId));
+          const question = await session.nextMessage();
+          expect(question.method).toBe('elicitation/create');
+          expect(question.params.requestedSchema.properties.occurrence.enum).toEqual(['1', '2', 'all']);
+
+          await session.post({
+            jsonrpc: '2.0',
+            id: question.id,
+            result: { action: 'accept', content: { occurrence: '2' } },
+          });
+          const result = await (await call).json();
+
+          // Then: Only the second match was edited
+          expect(result.result.isError).toBe(false);
+          expect(result.result.structuredContent.matches_changed).toBe(1);
+          const doc = await (await request.get(fixtureUrl(documentId))).json();
+          expect(doc.text).toBe('Foo one.\nFoo two.\nInserted\n');
+        } finally {
+          session.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: A declined elicitation cancels the edit',
+      async ({ request }) => {
+        // Given: A document where the anchor matches twice
+        const documentId = `test-int-elicit-decline-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const before = await (await request.get(fixtureUrl(documentId))).json();
+        const session = await openSession({ elicitation: {} });
+
+        try {
+          // When: The user declines to choose
+          const call = session.post(insertAfterFoo(documentId));
+          const question = await session.nextMessage();
+          await session.post({ jsonrpc: '2.0', id: question.id, result: { action: 'decline' } });
+          const result = await (await call).json();
+
+          // Then: The edit fails with EDIT_CANCELLED and nothing is written
+          expect(result.result.isError).toBe(true);
+          expect(result.result.structuredContent.code).toBe('EDIT_CANCELLED');
+          const after = await (await request.get(fixtureUrl(documentId))).json();
+          expect(after.revisionId).toBe(before.revisionId);
+        } finally {
+          session.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: An elicitation answered with an error cancels the edit',
+      async ({ request }) => {
+        // Given: A document where the anchor matches twice
+        const documentId = `test-int-elicit-error-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const before = await (await request.get(fixtureUrl(documentId))).json();
+        const session = await openSession({ elicitation: {} });
+
+        try {
+          // When: The client fails to handle the question
+          const call = session.post(insertAfterFoo(documentId));
+          const question = await session.nextMessage();
+          await session.post({
+            jsonrpc: '2.0',
+            id: question.id,
+            error: { code: -32603, message: 'no UI available' },
+          });
+          const result = await (await call).json();
+
+          // Then: The edit fails with EDIT_CANCELLED and nothing is written
+          expect(result.result.isError).toBe(true);
+          expect(result.result.structuredContent.code).toBe('EDIT_CANCELLED');
+          const after = await (await request.get(fixtureUrl(documentId))).json();
+          expect(after.revisionId).toBe(before.revisionId);
+        } finally {
+          session.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: The edit lock is released while the user decides',
+      async ({ request }) => {
+        // Given: A document where the anchor matches twice and a pending question
+        const documentId = `test-int-elicit-unlocked-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const session = await openSession({ elicitation: {} });
+
+        try {
+          const call = session.post(insertAfterFoo(documentId));
+          const question = await session.nextMessage();
+
+          // When: Another edit changes the document before the user answers
+          const other = await request.post(`${mcpServiceUrl}/mcp`, {
+            data: {
+              jsonrpc: '2.0',
+              method: 'tools/call',
+              id: 3,
+              params: { name: 'append', arguments: { documentId, content: 'Meanwhile' } },
+            },
+          });
+          await session.post({
+            jsonrpc: '2.0',
+            id: question.id,
+            result: { action: 'accept', content: { occurrence: '2' } },
+          });
+          const result = await (await call).json();
+
+          // Then: The other edit ran and the stale edit fails with REVISION_MISMATCH
+          expect((await other.json()).result.isError).toBe(false);
+          expect(result.result.isError).toBe(true);
+          expect(result.result.structuredContent.code).toBe('REVISION_MISMATCH');
+          const doc = await (await request.get(fixtureUrl(documentId))).json();
+          expect(doc.text).toBe('Foo one.\nFoo two.\nMeanwhile\n');
+        } finally {
+          session.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: Elicitation is not used under a protocol version without it',
+      async ({ request }) => {
+        // Given: A client declaring elicitation while negotiating 2024-11-05
+        const documentId = `test-int-elicit-old-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const session = await openSession({ elicitation: {} }, '2024-11-05');
+
+        try {
+          // When: The client makes the ambiguous edit
+          const result = await (await session.post(insertAfterFoo(documentId))).json();
+
+          // Then: Every match is edited without asking
+          expect(result.result.isError).toBe(false);
+          expect(result.result.structuredContent.matches_changed).toBe(2);
+        } finally {
+          session.close();
+        }
+      }
+    );
+
+    test(
+      'ORPHAN: Clients without elicitation keep the default of every match',
+      async ({ request }) => {
+        // Given: A document where the anchor matches twice
+        const documentId = `test-int-elicit-none-${Date.now()}`;
+        await request.put(fixtureUrl(documentId), { data: { markdown: 'Foo one.\n\nFoo two.' } });
+        const session = await openSession({});
+
+        try {
+          // When: A client without the capability makes the ambiguous edit
+          const result = await (await session.post(insertAfterFoo(documentId))).json();
+
+          // Then: Every match is edited without asking
+          expect(result.result.isError).toBe(false);
+          expect(result.result.structuredContent.matches_changed).toBe(2);
+        } finally {
+          session.close();
+        }
+      }
+    );
+  });
 });